@@ -5,6 +5,11 @@ import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/middleware"
 	"asset-management-system/server/pkg/config"
+	"asset-management-system/server/pkg/jobs"
+	"asset-management-system/server/pkg/labels"
+	"asset-management-system/server/pkg/reservations"
+	"asset-management-system/server/pkg/trash"
+	"asset-management-system/server/pkg/uploads"
 	"asset-management-system/server/pkg/utils"
 	"asset-management-system/server/routes"
 	"fmt"
@@ -12,6 +17,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -54,6 +60,15 @@ func runPre(*cobra.Command, []string) {
 		global.AppConfig.AppVersion, 
 		global.AppConfig.NodeEnv)
 
+	// 默认JWT密钥公开可见，任何人都能用它签发令牌，生产环境拒绝启动
+	if config.IsInsecureJWTSecret() {
+		if config.IsProduction() {
+			fmt.Println("JWT_SECRET 为空或仍为默认值，生产环境拒绝启动，请设置安全的随机密钥")
+			os.Exit(1)
+		}
+		fmt.Println("⚠️  JWT_SECRET 为空或仍为默认值，任何人都能伪造令牌，部署前请设置安全的随机密钥")
+	}
+
 	// 创建必要的目录
 	if err := os.MkdirAll(global.AppConfig.UploadDir, 0755); err != nil {
 		fmt.Printf("创建上传目录失败: %v\n", err)
@@ -70,6 +85,19 @@ func runPre(*cobra.Command, []string) {
 		fmt.Printf("初始化数据库失败: %v\n", err)
 		os.Exit(1)
 	}
+
+	// 初始化Redis（可选），连接失败时会话回退到数据库存储
+	if global.AppConfig.RedisEnabled {
+		if err := database.InitRedis(); err != nil {
+			fmt.Printf("初始化Redis失败，会话将仅使用数据库存储: %v\n", err)
+			global.Redis = nil
+		}
+	}
+
+	// 检查标签字体，不支持中文时标签中的中文资产名称会显示为方框
+	if err := labels.CheckFont(); err != nil {
+		fmt.Printf("⚠️  标签字体不支持中文，请配置 LABEL_FONT_PATH 指向中文字体: %v\n", err)
+	}
 }
 
 func runServer(*cobra.Command, []string) {
@@ -105,6 +133,20 @@ func runServer(*cobra.Command, []string) {
 	// 注册路由
 	routes.RegisterRoutes(r)
 
+	// 定时清理未被引用的上传文件
+	uploads.StartSweeper(global.DB, global.AppConfig.UploadDir,
+		time.Duration(global.AppConfig.UploadSweepIntervalHours)*time.Hour,
+		time.Duration(global.AppConfig.UploadSweepGraceHours)*time.Hour)
+
+	// 定时清除回收站中超过保留天数的记录
+	trash.StartPurger(global.DB, global.AppConfig.TrashRetentionDays)
+
+	// 启动后台批量任务执行器
+	jobs.Start(global.DB)
+
+	// 定时将未按时取用的预约置为过期
+	reservations.StartExpirer(global.DB, time.Duration(global.AppConfig.ReservationPickupGraceHours)*time.Hour)
+
 	// 启动服务器
 	fmt.Printf("🚀 %s 服务器启动在端口: %s\n", global.AppConfig.AppName, global.AppConfig.GoServicePort)
 	if err := r.Run(":" + global.AppConfig.GoServicePort); err != nil {
@@ -115,8 +157,8 @@ func runServer(*cobra.Command, []string) {
 
 // setupStaticFileServing 设置静态文件服务
 func setupStaticFileServing(r *gin.Engine) {
-	// 上传文件服务
-	r.Static("/uploads", global.AppConfig.UploadDir)
+	// 上传文件服务，附件目录不对外提供，只能通过鉴权的下载接口访问
+	r.StaticFS("/uploads", uploads.StaticFS(global.AppConfig.UploadDir))
 	
 	// 生产环境下服务前端静态文件
 	if config.IsProduction() {
@@ -7,6 +7,10 @@ import (
 
 // CreateIndexes 创建数据库索引
 func CreateIndexes() error {
+	if err := dropLegacyUniqueIndexes(); err != nil {
+		return err
+	}
+
 	// 资产表索引
 	assetIndexes := []string{
 		"CREATE INDEX IF NOT EXISTS idx_assets_asset_no ON assets(asset_no)",
@@ -92,4 +96,26 @@ func CreateIndexes() error {
 
 	fmt.Println("数据库索引创建完成")
 	return nil
-}
\ No newline at end of file
+}
+// legacyUniqueIndexes 旧版本建立的唯一索引，同时约束已删除的记录；现已改为只约束未删除记录的唯一索引，
+// 以便回收站中的记录不占用编号和编码
+var legacyUniqueIndexes = []string{"idx_assets_asset_no", "idx_categories_code", "idx_departments_code"}
+
+// dropLegacyUniqueIndexes 删除旧的唯一索引，随后按普通索引重建
+func dropLegacyUniqueIndexes() error {
+	for _, name := range legacyUniqueIndexes {
+		var count int64
+		if err := global.DB.Raw(
+			"SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ? AND sql LIKE 'CREATE UNIQUE INDEX%'", name,
+		).Scan(&count).Error; err != nil {
+			return fmt.Errorf("检查索引 %s 失败: %v", name, err)
+		}
+		if count == 0 {
+			continue
+		}
+		if err := global.DB.Exec("DROP INDEX " + name).Error; err != nil {
+			return fmt.Errorf("删除索引 %s 失败: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,9 @@ import (
 
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/config"
+	"asset-management-system/server/pkg/search"
 	"asset-management-system/server/pkg/utils"
 
 	"gorm.io/driver/sqlite"
@@ -32,8 +35,10 @@ func InitSQLiteDatabase() error {
 	}
 
 	// 连接SQLite数据库
+	// 后台任务与请求并发写入时等待锁释放，事务开始即获取写锁，避免读锁升级时死锁
+	// 写事务已由写锁串行化，不要在持有进程内互斥锁时等待开启事务，否则与已持有写锁、再请求该互斥锁的事务互相等待
 	var err error
-	global.DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	global.DB, err = gorm.Open(sqlite.Open(dbPath+"?_busy_timeout=5000&_txlock=immediate"), &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
 	})
 	if err != nil {
@@ -52,6 +57,16 @@ func InitSQLiteDatabase() error {
 
 	fmt.Printf("SQLite数据库连接成功: %s\n", dbPath)
 
+	// 注册数据范围回调
+	if err := auth.RegisterDataScopeCallbacks(global.DB); err != nil {
+		return fmt.Errorf("注册数据范围回调失败: %v", err)
+	}
+
+	// 检查全文检索环境，需在迁移前处理
+	if err := search.Prepare(global.DB); err != nil {
+		return fmt.Errorf("初始化全文检索失败: %v", err)
+	}
+
 	// 执行自动迁移
 	if err := models.AutoMigrate(global.DB); err != nil {
 		return fmt.Errorf("数据库迁移失败: %v", err)
@@ -64,11 +79,25 @@ func InitSQLiteDatabase() error {
 		return fmt.Errorf("创建索引失败: %v", err)
 	}
 
+	// 初始化全文检索
+	if err := search.Init(global.DB); err != nil {
+		return fmt.Errorf("初始化全文检索失败: %v", err)
+	}
+	fmt.Printf("全文检索引擎: %s\n", search.Engine())
+	if search.Engine() == search.EngineLike {
+		fmt.Println("⚠️  SQLite 未编译 FTS5，全文检索已回退为 LIKE 匹配，请使用 -tags sqlite_fts5 构建")
+	}
+
 	// 插入初始数据
 	if err := seedInitialData(); err != nil {
 		return fmt.Errorf("插入初始数据失败: %v", err)
 	}
 
+	// 创建初始管理员
+	if err := seedAdminUser(); err != nil {
+		return fmt.Errorf("创建初始管理员失败: %v", err)
+	}
+
 	fmt.Println("初始数据插入完成")
 
 	return nil
@@ -167,6 +196,11 @@ func seedInitialData() error {
 			ConfigValue: "ASSET",
 			Description: "资产编号前缀",
 		},
+		{
+			ConfigKey:   "asset_no_pattern",
+			ConfigValue: "{PREFIX}-{CATEGORY}-{YYYY}-{SEQ:4}",
+			Description: "资产编号规则，可用 asset_no_pattern.分类编码 为分类单独配置",
+		},
 		{
 			ConfigKey:   "default_warranty_period",
 			ConfigValue: "12",
@@ -193,6 +227,51 @@ func seedInitialData() error {
 	return nil
 }
 
+// seedAdminUser 创建初始管理员账号
+func seedAdminUser() error {
+	// 已有用户时不再创建
+	var userCount int64
+	if err := global.DB.Model(&models.User{}).Count(&userCount).Error; err != nil {
+		return err
+	}
+
+	if userCount > 0 {
+		warnDefaultAdminPassword()
+		return nil
+	}
+
+	admin := models.User{
+		Username: global.AppConfig.AdminUsername,
+		Name:     "系统管理员",
+		Roles:    "admin",
+		IsActive: true,
+	}
+	if err := admin.SetPassword(global.AppConfig.AdminPassword); err != nil {
+		return err
+	}
+
+	if err := global.DB.Create(&admin).Error; err != nil {
+		return err
+	}
+
+	fmt.Printf("已创建初始管理员账号: %s\n", admin.Username)
+	if global.AppConfig.AdminPassword == config.DefaultAdminPassword {
+		fmt.Printf("⚠️  初始管理员 %s 使用默认密码 %s，请立即登录修改或通过 ADMIN_PASSWORD 配置\n", admin.Username, config.DefaultAdminPassword)
+	}
+	return nil
+}
+
+// warnDefaultAdminPassword 初始管理员仍使用默认密码时给出警告
+func warnDefaultAdminPassword() {
+	var admin models.User
+	if err := global.DB.Where("username = ? AND is_active = ?", global.AppConfig.AdminUsername, true).First(&admin).Error; err != nil {
+		return
+	}
+	if admin.CheckPassword(config.DefaultAdminPassword) {
+		fmt.Printf("⚠️  管理员 %s 仍在使用默认密码 %s，请立即修改\n", admin.Username, config.DefaultAdminPassword)
+	}
+}
+
 // CloseDatabase 关闭数据库连接
 func CloseDatabase() error {
 	if global.DB != nil {
@@ -32,6 +32,17 @@ type Config struct {
 	UploadDir           string `env:"UPLOAD_DIR" envDefault:"./uploads"`
 	UploadMaxSize       int64  `env:"UPLOAD_MAX_SIZE" envDefault:"10485760"`
 	UploadAllowedTypes  string `env:"UPLOAD_ALLOWED_TYPES" envDefault:"jpg,jpeg,png,gif,pdf,doc,docx,xlsx,xls"`
+	UploadSweepIntervalHours int `env:"UPLOAD_SWEEP_INTERVAL_HOURS" envDefault:"24"` // 清理未引用上传文件的间隔，0 表示不自动清理
+	UploadSweepGraceHours    int `env:"UPLOAD_SWEEP_GRACE_HOURS" envDefault:"24"`    // 上传后在此时间内的文件不清理
+
+	// 回收站配置
+	TrashRetentionDays int `env:"TRASH_RETENTION_DAYS" envDefault:"30"` // 已删除记录保留天数，到期自动彻底删除，0 表示永久保留
+
+	// 资产预约配置
+	ReservationPickupGraceHours int `env:"RESERVATION_PICKUP_GRACE_HOURS" envDefault:"24"` // 预约开始后超过此时间未取用则自动过期
+	
+	// 标签打印配置（字体需包含中文字形，如 Noto Sans CJK）
+	LabelFontPath string `env:"LABEL_FONT_PATH" envDefault:""`
 	
 	// 日志配置
 	LogLevel     string `env:"LOG_LEVEL" envDefault:"info"`
@@ -44,7 +55,25 @@ type Config struct {
 	
 	// 安全配置
 	JWTSecret   string `env:"JWT_SECRET" envDefault:"default-jwt-secret"`
+	JWTIssuer   string `env:"JWT_ISSUER" envDefault:"asset-management-system"`
+	JWTExpireHours        int `env:"JWT_EXPIRE_HOURS" envDefault:"2"`
+	JWTRefreshExpireHours int `env:"JWT_REFRESH_EXPIRE_HOURS" envDefault:"168"`
 	CORSOrigins string `env:"CORS_ORIGINS" envDefault:"*"`
+	SessionIdleHours      int `env:"SESSION_IDLE_HOURS" envDefault:"24"`
+	
+	// Redis配置（会话缓存，未启用时会话仅存储在数据库）
+	RedisEnabled bool `env:"REDIS_ENABLED" envDefault:"false"`
+	
+	// DooTask 单点登录（插件部署）
+	DooTaskSSOEnabled   bool   `env:"DOOTASK_SSO_ENABLED" envDefault:"false"`
+	DooTaskUserInfoURL  string `env:"DOOTASK_USER_INFO_URL" envDefault:"http://nginx/api/users/info"`
+	DooTaskTokenHeader  string `env:"DOOTASK_TOKEN_HEADER" envDefault:"X-DooTask-Token"`
+	DooTaskCacheSeconds int    `env:"DOOTASK_CACHE_SECONDS" envDefault:"300"`
+	DooTaskDefaultRole  string `env:"DOOTASK_DEFAULT_ROLE" envDefault:"viewer"`
+	
+	// 初始管理员账号
+	AdminUsername string `env:"ADMIN_USERNAME" envDefault:"admin"`
+	AdminPassword string `env:"ADMIN_PASSWORD" envDefault:"admin123"`
 	
 	// 性能配置
 	MaxConcurrentRequests    int `env:"MAX_CONCURRENT_REQUESTS" envDefault:"100"`
@@ -5,9 +5,15 @@ go 1.23.0
 toolchain go1.23.11
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/gin-contrib/cors v1.7.6
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/spf13/cobra v1.9.1
 	github.com/xuri/excelize/v2 v2.9.1
+	golang.org/x/crypto v0.39.0
+	golang.org/x/image v0.25.0
+	golang.org/x/text v0.26.0
 	gorm.io/gorm v1.30.0
 )
 
@@ -56,11 +62,9 @@ require (
 	github.com/xuri/efp v0.0.1 // indirect
 	github.com/xuri/nfp v0.0.1 // indirect
 	golang.org/x/arch v0.18.0 // indirect
-	golang.org/x/crypto v0.39.0 // indirect
 	golang.org/x/net v0.41.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.26.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
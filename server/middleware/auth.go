@@ -1,22 +1,207 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware 简化的认证中间件（资产管理系统暂不需要复杂认证）
+// publicAPIPaths 不需要认证的API路径
+var publicAPIPaths = []string{
+	"/api/auth/login",
+	"/api/auth/refresh",
+}
+
+// APIKeyHeader API密钥请求头
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware 认证中间件，校验 Authorization: Bearer <token>
+// 未携带Bearer令牌时依次尝试 X-API-Key 和插件网关转发的DooTask令牌
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 标记为已认证
-		c.Set("is_authenticated", true)
+		// 仅对API请求进行认证，预检请求和公开接口直接放行
+		path := c.Request.URL.Path
+		if !strings.HasPrefix(path, "/api/") || c.Request.Method == http.MethodOptions || isPublicAPIPath(path) {
+			c.Next()
+			return
+		}
+
+		tokenString := extractBearerToken(c)
+		if tokenString == "" {
+			if apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeader)); apiKey != "" {
+				authenticateAPIKey(c, apiKey)
+				return
+			}
+			if dooTaskToken := extractDooTaskToken(c); dooTaskToken != "" {
+				authenticateDooTask(c, dooTaskToken)
+				return
+			}
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, "缺少访问令牌", nil)
+			c.Abort()
+			return
+		}
+
+		claims, err := auth.ParseToken(tokenString, auth.TokenTypeAccess)
+		if err != nil {
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+			c.Abort()
+			return
+		}
+
+		// 校验服务端会话，被注销的会话立即失效
+		if err := auth.ValidateSession(claims.SessionID, claims.UserID); err != nil {
+			if errors.Is(err, auth.ErrSessionInvalid) {
+				utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+			} else {
+				utils.InternalError(c, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		// 角色和部门从用户记录加载，令牌中的声明仅用于前端展示
+		identity, err := auth.LoadSessionIdentity(claims)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrUserDisabled):
+				utils.Error(c, utils.AUTH_USER_DISABLED, nil)
+			case errors.Is(err, auth.ErrSessionInvalid):
+				utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+			default:
+				utils.InternalError(c, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		auth.SetIdentity(c, identity)
 		c.Next()
 	}
 }
 
-// UserRoleMiddleware 用户权限中间件（简化版本）
+// authenticateAPIKey 使用API密钥认证
+func authenticateAPIKey(c *gin.Context, key string) {
+	identity, err := auth.AuthenticateAPIKey(key, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyInvalid) || errors.Is(err, auth.ErrAPIKeyExpired) || errors.Is(err, auth.ErrAPIKeyRevoked) {
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+		} else {
+			utils.InternalError(c, err.Error())
+		}
+		c.Abort()
+		return
+	}
+
+	auth.SetIdentity(c, identity)
+	c.Next()
+}
+
+// authenticateDooTask 使用DooTask令牌认证
+func authenticateDooTask(c *gin.Context, token string) {
+	identity, err := auth.AuthenticateDooTask(token)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUserDisabled):
+			utils.Error(c, utils.AUTH_USER_DISABLED, nil)
+		case errors.Is(err, auth.ErrDooTaskUnavailable):
+			utils.ErrorWithMessage(c, utils.AUTH_SSO_UNAVAILABLE, err.Error(), nil)
+		case errors.Is(err, auth.ErrDooTaskTokenInvalid):
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+		default:
+			utils.InternalError(c, err.Error())
+		}
+		c.Abort()
+		return
+	}
+
+	auth.SetIdentity(c, identity)
+	c.Next()
+}
+
+// UserRoleMiddleware 用户权限中间件
+// 指定角色时要求用户拥有其中之一，未指定时按权限矩阵校验路由分组和操作
 func UserRoleMiddleware(role ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 资产管理系统暂时不需要复杂的权限控制
+		if isPublicAPIPath(c.Request.URL.Path) || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		identity := auth.GetIdentity(c)
+		if identity == nil {
+			utils.Error(c, utils.UNAUTHORIZED, nil)
+			c.Abort()
+			return
+		}
+
+		resource, action := auth.ResolvePermission(c.Request.Method, c.Request.URL.Path)
+
+		// API密钥仅按授权范围校验，不能访问限定角色的接口
+		if identity.AuthType == auth.AuthTypeAPIKey {
+			if len(role) > 0 || !auth.HasScope(identity.Scopes, resource, action) {
+				utils.Error(c, utils.FORBIDDEN, gin.H{"resource": resource, "action": action})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// 管理员拥有全部权限
+		if identity.HasRole(auth.RoleAdmin) {
+			c.Next()
+			return
+		}
+
+		if len(role) > 0 {
+			if !identity.HasRole(role...) {
+				utils.Error(c, utils.FORBIDDEN, gin.H{"required_roles": role})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if !auth.HasPermission(identity.Roles, resource, action) {
+			utils.Error(c, utils.FORBIDDEN, gin.H{"resource": resource, "action": action})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
+
+// isPublicAPIPath 判断是否为公开接口
+func isPublicAPIPath(path string) bool {
+	for _, publicPath := range publicAPIPaths {
+		if path == publicPath {
+			return true
+		}
+	}
+	return false
+}
+
+// extractBearerToken 从请求头中提取Bearer令牌
+func extractBearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// extractDooTaskToken 从请求头中提取DooTask令牌，未启用单点登录时返回空
+func extractDooTaskToken(c *gin.Context) string {
+	if global.AppConfig == nil || !global.AppConfig.DooTaskSSOEnabled {
+		return ""
+	}
+	return strings.TrimSpace(c.GetHeader(global.AppConfig.DooTaskTokenHeader))
+}
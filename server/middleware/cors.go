@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"asset-management-system/server/global"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -11,7 +13,10 @@ import (
 func CorsMiddleware() gin.HandlerFunc {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", APIKeyHeader}
+	if global.AppConfig != nil && global.AppConfig.DooTaskTokenHeader != "" {
+		config.AllowHeaders = append(config.AllowHeaders, global.AppConfig.DooTaskTokenHeader)
+	}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
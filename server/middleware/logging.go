@@ -86,12 +86,30 @@ func extractTableName(path string) string {
 		switch parts[1] {
 		case "assets":
 			return "assets"
+		case "attachments":
+			return "attachments"
 		case "categories":
 			return "categories"
 		case "departments":
 			return "departments"
 		case "borrow-records":
 			return "borrow_records"
+		case "transfers":
+			return "asset_transfers"
+		case "reservations":
+			return "asset_reservations"
+		case "jobs":
+			return "bulk_jobs"
+		case "maintenance":
+			return "maintenance_orders"
+		case "disposals":
+			return "asset_disposals"
+		case "consumables":
+			return "consumables"
+		case "licenses":
+			return "licenses"
+		case "asset-no-rules":
+			return "system_configs"
 		case "inventory-tasks":
 			return "inventory_tasks"
 		case "inventory-records":
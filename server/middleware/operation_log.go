@@ -3,6 +3,7 @@ package middleware
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 	"bytes"
 	"encoding/json"
 	"io"
@@ -26,16 +27,37 @@ type AuditLogConfig struct {
 func DefaultAuditLogConfig() *AuditLogConfig {
 	return &AuditLogConfig{
 		TableMapping: map[string]string{
-			"/api/assets":      "assets",
-			"/api/categories":  "categories",
-			"/api/departments": "departments",
-			"/api/borrow":      "borrow_records",
-			"/api/inventory":   "inventory_tasks",
+			"/api/assets":         "assets",
+			"/api/attachments":    "attachments",
+			"/api/categories":     "categories",
+			"/api/departments":    "departments",
+			"/api/borrow":         "borrow_records",
+			"/api/transfers":      "asset_transfers",
+			"/api/reservations":   "asset_reservations",
+			"/api/jobs":           "bulk_jobs",
+			"/api/maintenance":    "maintenance_orders",
+			"/api/disposals":      "asset_disposals",
+			"/api/consumables":    "consumables",
+			"/api/licenses":       "licenses",
+			"/api/inventory":      "inventory_tasks",
+			"/api/api-keys":       "api_keys",
+			"/api/asset-no-rules": "system_configs",
+			// 回收站恢复、彻底删除记录到原表
+			"/api/trash/asset":          "assets",
+			"/api/trash/category":       "categories",
+			"/api/trash/department":     "departments",
+			"/api/trash/borrow":         "borrow_records",
+			"/api/trash/inventory_task": "inventory_tasks",
+			// 扫码操作按动作由处理函数通过 SetAuditTarget 指定
+			"/api/scan": "",
 		},
 		Operations: []string{"POST", "PUT", "DELETE"},
 		ExcludePaths: []string{
 			"/api/assets/check-asset-no",
 			"/api/assets/export",
+			"/api/assets/import/file",
+			"/api/attachments/upload",
+			"/api/attachments/sweep",
 			"/api/upload",
 		},
 	}
@@ -85,6 +107,31 @@ func AuditLogMiddleware(config *AuditLogConfig) gin.HandlerFunc {
 	}
 }
 
+// auditTargetKey 处理函数指定审计目标的上下文键
+const auditTargetKey = "audit_target"
+
+// auditTarget 审计目标，用于同一接口按请求内容操作不同数据表的情况
+type auditTarget struct {
+	table     string
+	recordID  uint
+	operation models.OperationType
+}
+
+// SetAuditTarget 由处理函数指定本次请求操作日志的表、记录ID和操作类型
+func SetAuditTarget(c *gin.Context, table string, recordID uint, operation models.OperationType) {
+	c.Set(auditTargetKey, auditTarget{table: table, recordID: recordID, operation: operation})
+}
+
+// getAuditTarget 获取处理函数指定的审计目标
+func getAuditTarget(c *gin.Context) (auditTarget, bool) {
+	value, exists := c.Get(auditTargetKey)
+	if !exists {
+		return auditTarget{}, false
+	}
+	target, ok := value.(auditTarget)
+	return target, ok
+}
+
 // shouldLogAuditOperation 判断是否需要记录操作日志
 func shouldLogAuditOperation(c *gin.Context, config *AuditLogConfig) bool {
 	// 检查HTTP方法
@@ -159,11 +206,66 @@ func getAuditOldData(c *gin.Context, config *AuditLogConfig) interface{} {
 		if err := global.DB.Preload("Asset").Preload("Department").First(&borrowRecord, id).Error; err == nil {
 			return borrowRecord
 		}
+	case "asset_transfers":
+		var transfer models.AssetTransfer
+		if err := global.DB.Preload("Asset").Preload("FromDepartment").Preload("ToDepartment").First(&transfer, id).Error; err == nil {
+			return transfer
+		}
+	case "asset_reservations":
+		var reservation models.AssetReservation
+		if err := global.DB.Preload("Asset").Preload("Department").First(&reservation, id).Error; err == nil {
+			return reservation
+		}
+	case "bulk_jobs":
+		var job models.BulkJob
+		if err := global.DB.First(&job, id).Error; err == nil {
+			return job
+		}
+	case "maintenance_orders":
+		var order models.MaintenanceOrder
+		if err := global.DB.Preload("Asset").First(&order, id).Error; err == nil {
+			return order
+		}
+	case "asset_disposals":
+		var disposal models.AssetDisposal
+		if err := global.DB.Preload("Asset").First(&disposal, id).Error; err == nil {
+			return disposal
+		}
+	case "consumables":
+		var consumable models.Consumable
+		if err := global.DB.Preload("Stocks").First(&consumable, id).Error; err == nil {
+			return consumable
+		}
+	case "consumable_transactions":
+		var transaction models.ConsumableTransaction
+		if err := global.DB.First(&transaction, id).Error; err == nil {
+			return transaction
+		}
+	case "licenses":
+		var license models.License
+		if err := global.DB.First(&license, id).Error; err == nil {
+			return license
+		}
+	case "license_seats":
+		var seat models.LicenseSeat
+		if err := global.DB.First(&seat, id).Error; err == nil {
+			return seat
+		}
 	case "inventory_tasks":
 		var inventoryTask models.InventoryTask
 		if err := global.DB.First(&inventoryTask, id).Error; err == nil {
 			return inventoryTask
 		}
+	case "attachments":
+		var attachment models.Attachment
+		if err := global.DB.First(&attachment, id).Error; err == nil {
+			return attachment
+		}
+	case "api_keys":
+		var apiKey models.APIKey
+		if err := global.DB.First(&apiKey, id).Error; err == nil {
+			return apiKey
+		}
 	}
 
 	return nil
@@ -177,7 +279,13 @@ func extractAuditIDFromPath(path string) uint {
 			// 确保这是一个有效的ID（不是其他数字参数）
 			if i > 0 && (parts[i-1] == "assets" || parts[i-1] == "categories" || 
 				parts[i-1] == "departments" || parts[i-1] == "borrow" || 
-				parts[i-1] == "inventory") {
+				parts[i-1] == "inventory" || parts[i-1] == "api-keys" ||
+				parts[i-1] == "transfers" || parts[i-1] == "maintenance" ||
+				parts[i-1] == "disposals" || parts[i-1] == "attachments" ||
+				parts[i-1] == "asset" || parts[i-1] == "category" ||
+				parts[i-1] == "department" || parts[i-1] == "inventory_task" ||
+				parts[i-1] == "reservations" || parts[i-1] == "jobs" ||
+				parts[i-1] == "consumables" || parts[i-1] == "licenses") {
 				return uint(id)
 			}
 		}
@@ -201,6 +309,12 @@ func logAuditOperation(c *gin.Context, config *AuditLogConfig, requestBody []byt
 		}
 	}
 
+	// 处理函数指定的审计目标优先于路径映射
+	target, hasTarget := getAuditTarget(c)
+	if hasTarget {
+		tableName = target.table
+	}
+
 	if tableName == "" {
 		return
 	}
@@ -229,6 +343,11 @@ func logAuditOperation(c *gin.Context, config *AuditLogConfig, requestBody []byt
 		recordID = extractAuditIDFromResponse(auditWriter.body.Bytes())
 	}
 
+	if hasTarget {
+		operation = target.operation
+		recordID = target.recordID
+	}
+
 	// 准备日志数据
 	var oldDataJSON []byte
 	var newDataJSON []byte
@@ -282,12 +401,8 @@ func extractAuditIDFromResponse(responseBody []byte) uint {
 
 // getAuditOperator 获取操作者信息
 func getAuditOperator(c *gin.Context) string {
-	// 这里可以从JWT token或session中获取用户信息
-	// 目前系统没有用户认证，返回默认值
-	if operator := c.GetHeader("X-Operator"); operator != "" {
-		return operator
-	}
-	return "system"
+	// 从认证中间件写入的身份信息中获取
+	return auth.GetOperator(c)
 }
 
 // auditResponseWriter 响应写入器，用于捕获响应内容
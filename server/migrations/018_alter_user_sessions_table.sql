@@ -0,0 +1,11 @@
+-- Description: 调整用户会话表以支持服务端会话
+-- 用户ID改为数值类型，补充客户端信息和活跃时间
+
+ALTER TABLE user_sessions ALTER COLUMN user_id TYPE BIGINT USING user_id::BIGINT;
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS user_agent TEXT;
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS last_active_at TIMESTAMP;
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at ON user_sessions(expires_at);
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey API密钥模型，仅保存密钥摘要
+type APIKey struct {
+	ID         uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string         `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	KeyPrefix  string         `json:"key_prefix" gorm:"size:20;index"` // 密钥前缀，便于识别
+	KeyHash    string         `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	Scopes     string         `json:"scopes" gorm:"size:1000"` // 授权范围列表，逗号分隔，格式为 资源:操作
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	LastUsedIP string         `json:"last_used_ip" gorm:"size:45"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+	CreatedBy  string         `json:"created_by" gorm:"size:100"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// GetScopes 获取授权范围列表
+func (k *APIKey) GetScopes() []string {
+	var scopes []string
+	for _, scope := range strings.Split(k.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// IsExpired 判断密钥是否已过期
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// IsRevoked 判断密钥是否已吊销
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -19,27 +19,31 @@ const (
 
 // Asset 资产模型
 type Asset struct {
-	ID                  uint           `json:"id" gorm:"primaryKey;autoIncrement"`
-	AssetNo             string         `json:"asset_no" gorm:"size:100;uniqueIndex;not null" validate:"required,max=100"`
-	Name                string         `json:"name" gorm:"size:200;not null" validate:"required,max=200"`
-	CategoryID          uint           `json:"category_id" gorm:"not null;index" validate:"required"`
-	DepartmentID        *uint          `json:"department_id" gorm:"index"`
-	Brand               string         `json:"brand" gorm:"size:100" validate:"max=100"`
-	Model               string         `json:"model" gorm:"size:100" validate:"max=100"`
-	SerialNumber        string         `json:"serial_number" gorm:"size:100" validate:"max=100"`
-	PurchaseDate        *time.Time     `json:"purchase_date" gorm:"type:date"`
-	PurchasePrice       *float64       `json:"purchase_price" gorm:"type:decimal(12,2)"`
-	Supplier            string         `json:"supplier" gorm:"size:200" validate:"max=200"`
-	WarrantyPeriod      *int           `json:"warranty_period"` // 保修期（月）
-	Status              AssetStatus    `json:"status" gorm:"size:20;default:available" validate:"oneof=available borrowed maintenance scrapped"`
-	Location            string         `json:"location" gorm:"size:200" validate:"max=200"`
-	ResponsiblePerson   string         `json:"responsible_person" gorm:"size:100" validate:"max=100"`
-	Description         string         `json:"description" gorm:"type:text"`
-	ImageURL            string         `json:"image_url" gorm:"size:500" validate:"max=500"`
-	CustomAttributes    datatypes.JSON `json:"custom_attributes" gorm:"type:json"` // 自定义属性
-	CreatedAt           time.Time      `json:"created_at"`
-	UpdatedAt           time.Time      `json:"updated_at"`
-	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                 uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	AssetNo            string             `json:"asset_no" gorm:"size:100;uniqueIndex:idx_assets_asset_no_active,where:deleted_at IS NULL;not null" validate:"required,max=100"`
+	Name               string             `json:"name" gorm:"size:200;not null" validate:"required,max=200"`
+	CategoryID         uint               `json:"category_id" gorm:"not null;index" validate:"required"`
+	DepartmentID       *uint              `json:"department_id" gorm:"index"`
+	ParentID           *uint              `json:"parent_id" gorm:"index"` // 父资产，如整机、套件；通过挂载、拆卸组件维护
+	Brand              string             `json:"brand" gorm:"size:100" validate:"max=100"`
+	Model              string             `json:"model" gorm:"size:100" validate:"max=100"`
+	SerialNumber       string             `json:"serial_number" gorm:"size:100" validate:"max=100"`
+	PurchaseDate       *time.Time         `json:"purchase_date" gorm:"type:date"`
+	PurchasePrice      *float64           `json:"purchase_price" gorm:"type:decimal(12,2)"`
+	Supplier           string             `json:"supplier" gorm:"size:200" validate:"max=200"`
+	WarrantyPeriod     *int               `json:"warranty_period"` // 保修期（月）
+	Status             AssetStatus        `json:"status" gorm:"size:20;default:available" validate:"oneof=available borrowed maintenance scrapped"`
+	Location           string             `json:"location" gorm:"size:200" validate:"max=200"`
+	ResponsiblePerson  string             `json:"responsible_person" gorm:"size:100" validate:"max=100"`
+	Description        string             `json:"description" gorm:"type:text"`
+	ImageURL           string             `json:"image_url" gorm:"size:500" validate:"max=500"`
+	CustomAttributes   datatypes.JSON     `json:"custom_attributes" gorm:"type:json"`    // 自定义属性
+	DepreciationMethod DepreciationMethod `json:"depreciation_method" gorm:"size:30"`    // 折旧方法，为空时沿用分类设置
+	UsefulLifeMonths   *int               `json:"useful_life_months"`                    // 使用寿命（月），为空时沿用分类设置
+	SalvageRate        *float64           `json:"salvage_rate" gorm:"type:decimal(5,4)"` // 残值率，为空时沿用分类设置
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt     `json:"-" gorm:"index"`
 
 	// 关联关系
 	Category      Category       `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
@@ -71,7 +75,7 @@ func (a *Asset) BeforeUpdate(tx *gorm.DB) error {
 func (a *Asset) BeforeDelete(tx *gorm.DB) error {
 	// 检查是否有未归还的借用记录
 	var borrowCount int64
-	if err := tx.Model(&BorrowRecord{}).Where("asset_id = ? AND status = ?", a.ID, BorrowStatusBorrowed).Count(&borrowCount).Error; err != nil {
+	if err := tx.Model(&BorrowRecord{}).Where("asset_id = ? AND status IN ?", a.ID, []BorrowStatus{BorrowStatusBorrowed, BorrowStatusOverdue}).Count(&borrowCount).Error; err != nil {
 		return err
 	}
 	if borrowCount > 0 {
@@ -81,6 +85,16 @@ func (a *Asset) BeforeDelete(tx *gorm.DB) error {
 	return nil
 }
 
+// AfterSave 保存后钩子，同步全文检索文档
+func (a *Asset) AfterSave(tx *gorm.DB) error {
+	return SyncSearchDocuments(tx, SearchKindAsset, a.ID)
+}
+
+// AfterDelete 删除后钩子，移除全文检索文档
+func (a *Asset) AfterDelete(tx *gorm.DB) error {
+	return RemoveSearchDocument(tx, SearchKindAsset, a.ID)
+}
+
 // IsAvailable 检查资产是否可用
 func (a *Asset) IsAvailable() bool {
 	return a.Status == AssetStatusAvailable
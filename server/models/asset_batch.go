@@ -0,0 +1,158 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssetBatchUpdate 批量更新资产的字段，为空的字段不更新
+type AssetBatchUpdate struct {
+	Status            *AssetStatus `json:"status" validate:"omitempty,oneof=available borrowed maintenance scrapped"`
+	DepartmentID      *uint        `json:"department_id"`
+	Location          *string      `json:"location" validate:"omitempty,max=200"`
+	ResponsiblePerson *string      `json:"responsible_person" validate:"omitempty,max=100"`
+}
+
+// Columns 获取需要更新的列
+func (u *AssetBatchUpdate) Columns() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if u.Status != nil {
+		updates["status"] = *u.Status
+	}
+	if u.DepartmentID != nil {
+		updates["department_id"] = *u.DepartmentID
+	}
+	if u.Location != nil {
+		updates["location"] = *u.Location
+	}
+	if u.ResponsiblePerson != nil {
+		updates["responsible_person"] = *u.ResponsiblePerson
+	}
+	return updates
+}
+
+// AssetUpdateBlockedReason 检查资产能否批量更新，不能更新时返回原因
+// 已冻结的资产不能更新；变更部门时不能有进行中的调拨单；变更状态须符合状态流转规则
+func AssetUpdateBlockedReason(tx *gorm.DB, asset *Asset, update *AssetBatchUpdate) (string, error) {
+	frozen, err := IsAssetFrozen(tx, asset.ID)
+	if err != nil {
+		return "", err
+	}
+	if frozen {
+		return "资产处置已批准，不能借用、编辑或删除", nil
+	}
+
+	if update.DepartmentID != nil {
+		inProgress, err := HasOpenTransfer(tx, asset.ID)
+		if err != nil {
+			return "", err
+		}
+		if inProgress {
+			return "资产存在进行中的调拨单", nil
+		}
+	}
+
+	if update.Status != nil {
+		if err := CheckAssetStatusTransition(tx, asset, *update.Status); err != nil {
+			var transitionErr *AssetStatusTransitionError
+			if !errors.As(err, &transitionErr) {
+				return "", err
+			}
+			return transitionErr.Error(), nil
+		}
+	}
+	return "", nil
+}
+
+// ApplyAssetBatchUpdate 批量更新已通过校验的资产，直接变更部门的资产补记调拨记录
+// 须在事务中调用
+func ApplyAssetBatchUpdate(tx *gorm.DB, assets []Asset, update *AssetBatchUpdate, operator, reason string) error {
+	updates := update.Columns()
+	if len(assets) == 0 || len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(assets))
+	for i := range assets {
+		ids[i] = assets[i].ID
+		if update.DepartmentID == nil {
+			continue
+		}
+		toLocation, toResponsiblePerson := assets[i].Location, assets[i].ResponsiblePerson
+		if update.Location != nil {
+			toLocation = *update.Location
+		}
+		if update.ResponsiblePerson != nil {
+			toResponsiblePerson = *update.ResponsiblePerson
+		}
+		if err := RecordDirectTransfer(tx, &assets[i], *update.DepartmentID, toLocation, toResponsiblePerson, operator, reason); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&Asset{}).Where("id IN ?", ids).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// 批量更新时模型钩子无法获知具体记录，手动同步检索文档
+	return SyncSearchDocuments(tx, SearchKindAsset, ids...)
+}
+
+var (
+	// ErrAssetInUse 资产借用中或超期未还
+	ErrAssetInUse = errors.New("资产正在使用中，无法删除")
+	// ErrAssetReserved 资产有未取用的预约
+	ErrAssetReserved = errors.New("资产有未取用的预约，无法删除")
+	// ErrAssetFrozen 资产处置已批准或已完成
+	ErrAssetFrozen = errors.New("资产处置已批准或已核销，无法删除")
+	// ErrAssetInDisposal 资产有进行中的处置申请
+	ErrAssetInDisposal = errors.New("资产处置中，无法删除")
+)
+
+// CheckAssetDeletable 检查资产能否删除，单个删除、批量删除和后台批量任务共用
+// 借用中或超期未还、有未取用预约、处置中或已核销的资产不能删除，返回对应的错误，grace 为预约的取用宽限时间
+func CheckAssetDeletable(tx *gorm.DB, asset *Asset, grace time.Duration) error {
+	var borrowCount int64
+	if err := tx.Model(&BorrowRecord{}).
+		Where("asset_id = ? AND status IN ?", asset.ID, []BorrowStatus{BorrowStatusBorrowed, BorrowStatusOverdue}).
+		Count(&borrowCount).Error; err != nil {
+		return err
+	}
+	if borrowCount > 0 {
+		return ErrAssetInUse
+	}
+
+	var reservationCount int64
+	if err := ActiveReservations(tx.Model(&AssetReservation{}), grace).
+		Where("asset_id = ?", asset.ID).
+		Count(&reservationCount).Error; err != nil {
+		return err
+	}
+	if reservationCount > 0 {
+		return ErrAssetReserved
+	}
+
+	frozen, err := IsAssetFrozen(tx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if frozen {
+		return ErrAssetFrozen
+	}
+	inDisposal, err := HasOpenDisposal(tx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if inDisposal {
+		return ErrAssetInDisposal
+	}
+	return nil
+}
+
+// IsAssetDeleteBlocked 判断 CheckAssetDeletable 返回的是否为不满足删除条件的错误，而非查询失败
+func IsAssetDeleteBlocked(err error) bool {
+	return errors.Is(err, ErrAssetInUse) || errors.Is(err, ErrAssetReserved) ||
+		errors.Is(err, ErrAssetFrozen) || errors.Is(err, ErrAssetInDisposal)
+}
@@ -0,0 +1,261 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 组件变更动作
+const (
+	ComponentActionAttach = "attach" // 挂载
+	ComponentActionDetach = "detach" // 拆卸
+)
+
+// MaxComponentDepth 组件树最大层级（含顶层资产）
+const MaxComponentDepth = 5
+
+var (
+	// ErrComponentSelf 不能将资产挂载到自身或其下级组件
+	ErrComponentSelf = errors.New("不能将资产挂载到自身或其下级组件")
+	// ErrComponentTooDeep 组件层级超出限制
+	ErrComponentTooDeep = errors.New("组件层级超出限制")
+	// ErrComponentAttached 资产已挂载在该父资产下
+	ErrComponentAttached = errors.New("资产已挂载在该父资产下")
+	// ErrComponentNotAttached 资产不是该父资产的组件
+	ErrComponentNotAttached = errors.New("资产不是该父资产的组件")
+)
+
+// AssetComponentLog 组件挂载、拆卸历史
+type AssetComponentLog struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ParentAssetID uint      `json:"parent_id" gorm:"column:parent_id;not null;index"` // 字段名避开 Asset.ParentID，否则关联会被识别为 has one
+	ComponentID   uint      `json:"component_id" gorm:"not null;index"`
+	Action        string    `json:"action" gorm:"size:20;not null"` // attach 挂载、detach 拆卸
+	Operator      string    `json:"operator" gorm:"size:100"`
+	Notes         string    `json:"notes" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// 关联关系（含已删除资产，以便查看历史）
+	Parent    Asset `json:"parent,omitempty" gorm:"foreignKey:ParentAssetID"`
+	Component Asset `json:"component,omitempty" gorm:"foreignKey:ComponentID"`
+}
+
+// TableName 指定表名
+func (AssetComponentLog) TableName() string {
+	return "asset_component_logs"
+}
+
+// AssetComponentNode 组件树节点
+type AssetComponentNode struct {
+	ID             uint                 `json:"id"`
+	AssetNo        string               `json:"asset_no"`
+	Name           string               `json:"name"`
+	CategoryID     uint                 `json:"category_id"`
+	DepartmentID   *uint                `json:"department_id"`
+	Status         AssetStatus          `json:"status"`
+	PurchasePrice  *float64             `json:"purchase_price"`
+	ComponentValue float64              `json:"component_value"` // 下级组件原值合计
+	TotalValue     float64              `json:"total_value"`     // 自身及下级组件原值合计
+	Components     []AssetComponentNode `json:"components"`
+}
+
+// AttachComponent 将资产挂载到父资产下；已挂载在其他父资产下时先记录拆卸，便于追溯组件在整机间的移动
+// 须在事务中调用
+func AttachComponent(tx *gorm.DB, parentID, componentID uint, operator, notes string) error {
+	if parentID == componentID {
+		return ErrComponentSelf
+	}
+
+	var component Asset
+	if err := tx.First(&component, componentID).Error; err != nil {
+		return err
+	}
+	if component.ParentID != nil && *component.ParentID == parentID {
+		return ErrComponentAttached
+	}
+
+	// 父资产不能是组件自身的下级，挂载后层级不能超出限制
+	ancestors, err := componentAncestorIDs(tx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == componentID {
+			return ErrComponentSelf
+		}
+	}
+	height, err := componentHeight(tx, componentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+1+height > MaxComponentDepth {
+		return ErrComponentTooDeep
+	}
+
+	if component.ParentID != nil {
+		if err := detachComponent(tx, &component, operator, notes); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&component).Update("parent_id", parentID).Error; err != nil {
+		return err
+	}
+	return tx.Create(&AssetComponentLog{
+		ParentAssetID: parentID,
+		ComponentID:   componentID,
+		Action:        ComponentActionAttach,
+		Operator:      operator,
+		Notes:         notes,
+	}).Error
+}
+
+// DetachComponent 将组件从父资产拆卸为独立资产，须在事务中调用
+func DetachComponent(tx *gorm.DB, parentID, componentID uint, operator, notes string) error {
+	var component Asset
+	if err := tx.First(&component, componentID).Error; err != nil {
+		return err
+	}
+	if component.ParentID == nil || *component.ParentID != parentID {
+		return ErrComponentNotAttached
+	}
+	return detachComponent(tx, &component, operator, notes)
+}
+
+// DetachAllComponents 删除资产前调用：拆下其全部直接组件，并将其从所属父资产拆下
+func DetachAllComponents(tx *gorm.DB, asset *Asset, operator, notes string) error {
+	var components []Asset
+	if err := tx.Where("parent_id = ?", asset.ID).Find(&components).Error; err != nil {
+		return err
+	}
+	for i := range components {
+		if err := detachComponent(tx, &components[i], operator, notes); err != nil {
+			return err
+		}
+	}
+
+	if asset.ParentID != nil {
+		return detachComponent(tx, asset, operator, notes)
+	}
+	return nil
+}
+
+// ComponentDescendantIDs 获取资产的全部下级组件ID（按层级顺序）
+func ComponentDescendantIDs(tx *gorm.DB, assetID uint) ([]uint, error) {
+	var result []uint
+	level := []uint{assetID}
+	for depth := 1; depth < MaxComponentDepth && len(level) > 0; depth++ {
+		var children []uint
+		if err := tx.Model(&Asset{}).Where("parent_id IN ?", level).Order("id").Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		result = append(result, children...)
+		level = children
+	}
+	return result, nil
+}
+
+// LoadComponentTree 加载资产的组件树，并逐级汇总组件原值；db 可携带数据范围
+func LoadComponentTree(db *gorm.DB, assetID uint) ([]AssetComponentNode, error) {
+	children := make(map[uint][]Asset)
+	level := []uint{assetID}
+	for depth := 1; depth < MaxComponentDepth && len(level) > 0; depth++ {
+		var assets []Asset
+		if err := db.Session(&gorm.Session{}).Where("parent_id IN ?", level).Order("asset_no ASC").Find(&assets).Error; err != nil {
+			return nil, err
+		}
+		level = level[:0]
+		for _, asset := range assets {
+			children[*asset.ParentID] = append(children[*asset.ParentID], asset)
+			level = append(level, asset.ID)
+		}
+	}
+	return buildComponentNodes(children, assetID), nil
+}
+
+// SumComponentValue 汇总组件树的原值
+func SumComponentValue(nodes []AssetComponentNode) float64 {
+	var total float64
+	for _, node := range nodes {
+		total += node.TotalValue
+	}
+	return total
+}
+
+// buildComponentNodes 按父子关系递归生成组件树节点
+func buildComponentNodes(children map[uint][]Asset, parentID uint) []AssetComponentNode {
+	nodes := make([]AssetComponentNode, 0, len(children[parentID]))
+	for _, asset := range children[parentID] {
+		node := AssetComponentNode{
+			ID:            asset.ID,
+			AssetNo:       asset.AssetNo,
+			Name:          asset.Name,
+			CategoryID:    asset.CategoryID,
+			DepartmentID:  asset.DepartmentID,
+			Status:        asset.Status,
+			PurchasePrice: asset.PurchasePrice,
+			Components:    buildComponentNodes(children, asset.ID),
+		}
+		node.ComponentValue = SumComponentValue(node.Components)
+		node.TotalValue = node.ComponentValue
+		if asset.PurchasePrice != nil {
+			node.TotalValue += *asset.PurchasePrice
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// detachComponent 清除组件的父资产并记录拆卸
+func detachComponent(tx *gorm.DB, component *Asset, operator, notes string) error {
+	parentID := *component.ParentID
+	if err := tx.Model(component).Update("parent_id", nil).Error; err != nil {
+		return err
+	}
+	component.ParentID = nil
+	return tx.Create(&AssetComponentLog{
+		ParentAssetID: parentID,
+		ComponentID:   component.ID,
+		Action:        ComponentActionDetach,
+		Operator:      operator,
+		Notes:         notes,
+	}).Error
+}
+
+// componentAncestorIDs 获取资产自身及其全部上级资产ID（由近及远）
+func componentAncestorIDs(tx *gorm.DB, assetID uint) ([]uint, error) {
+	ids := []uint{assetID}
+	current := assetID
+	for len(ids) <= MaxComponentDepth {
+		var asset Asset
+		if err := tx.Select("id", "parent_id").First(&asset, current).Error; err != nil {
+			return nil, err
+		}
+		if asset.ParentID == nil {
+			return ids, nil
+		}
+		current = *asset.ParentID
+		ids = append(ids, current)
+	}
+	return ids, nil
+}
+
+// componentHeight 获取以资产为根的组件树层数（不含自身）
+func componentHeight(tx *gorm.DB, assetID uint) (int, error) {
+	height := 0
+	level := []uint{assetID}
+	for len(level) > 0 && height <= MaxComponentDepth {
+		var children []uint
+		if err := tx.Model(&Asset{}).Where("parent_id IN ?", level).Pluck("id", &children).Error; err != nil {
+			return 0, err
+		}
+		if len(children) == 0 {
+			break
+		}
+		height++
+		level = children
+	}
+	return height, nil
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DisposalMethod 处置方式枚举
+type DisposalMethod string
+
+const (
+	DisposalMethodSell           DisposalMethod = "sell"             // 出售
+	DisposalMethodDonate         DisposalMethod = "donate"           // 捐赠
+	DisposalMethodDestroy        DisposalMethod = "destroy"          // 销毁
+	DisposalMethodReturnToVendor DisposalMethod = "return_to_vendor" // 退回供应商
+)
+
+// DisposalStatus 处置申请状态枚举
+type DisposalStatus string
+
+const (
+	DisposalStatusRequested DisposalStatus = "requested" // 已申请
+	DisposalStatusApproved  DisposalStatus = "approved"  // 已批准
+	DisposalStatusCompleted DisposalStatus = "completed" // 已完成
+	DisposalStatusRejected  DisposalStatus = "rejected"  // 已驳回
+)
+
+// DisposalStatusTransitions 处置申请状态流转表：当前状态 -> 允许的下一状态
+var DisposalStatusTransitions = map[DisposalStatus][]DisposalStatus{
+	DisposalStatusRequested: {DisposalStatusApproved, DisposalStatusRejected},
+	DisposalStatusApproved:  {DisposalStatusCompleted, DisposalStatusRejected},
+	DisposalStatusCompleted: {},
+	DisposalStatusRejected:  {},
+}
+
+// OpenDisposalStatuses 进行中的处置状态，同一资产同时只能有一张进行中的处置申请
+var OpenDisposalStatuses = []DisposalStatus{DisposalStatusRequested, DisposalStatusApproved}
+
+// FrozenDisposalStatuses 冻结资产的处置状态，批准后资产不能再借用、编辑或删除
+var FrozenDisposalStatuses = []DisposalStatus{DisposalStatusApproved, DisposalStatusCompleted}
+
+// AssetDisposal 资产处置申请模型
+type AssetDisposal struct {
+	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	AssetID       uint           `json:"asset_id" gorm:"not null;index" validate:"required"`
+	Reason        string         `json:"reason" gorm:"type:text;not null" validate:"required"`
+	Method        DisposalMethod `json:"method" gorm:"size:20;not null;index" validate:"required,oneof=sell donate destroy return_to_vendor"`
+	ResidualValue *float64       `json:"residual_value" gorm:"type:decimal(12,2)"` // 预计残值（处置收入）
+	Status        DisposalStatus `json:"status" gorm:"size:20;default:requested;index" validate:"oneof=requested approved completed rejected"`
+	RequestedBy   string         `json:"requested_by" gorm:"size:100"`
+	ReviewedBy    string         `json:"reviewed_by" gorm:"size:100"` // 审批人
+	ReviewedAt    *time.Time     `json:"reviewed_at"`                 // 审批日期
+	ReviewComment string         `json:"review_comment" gorm:"type:text"`
+	DisposalDate  *time.Time     `json:"disposal_date"` // 实际处置日期
+	CompletedBy   string         `json:"completed_by" gorm:"size:100"`
+	CompletedAt   *time.Time     `json:"completed_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 关联关系
+	Asset    Asset          `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+	WriteOff *AssetWriteOff `json:"write_off,omitempty" gorm:"foreignKey:DisposalID"`
+}
+
+// TableName 指定表名
+func (AssetDisposal) TableName() string {
+	return "asset_disposals"
+}
+
+// BeforeCreate 创建前钩子
+func (d *AssetDisposal) BeforeCreate(tx *gorm.DB) error {
+	// 设置默认状态
+	if d.Status == "" {
+		d.Status = DisposalStatusRequested
+	}
+	return nil
+}
+
+// CanTransitionTo 判断处置申请能否流转到指定状态
+func (d *AssetDisposal) CanTransitionTo(to DisposalStatus) bool {
+	for _, status := range DisposalStatusTransitions[d.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete 完成处置：资产置为已报废，处置申请置为已完成，并写入核销记录
+// 须在事务中调用，writeOff 由调用方填好资产和账面价值快照
+func (d *AssetDisposal) Complete(tx *gorm.DB, writeOff *AssetWriteOff) error {
+	// 资产状态须在处置申请仍为已批准时变更，以通过报废守卫
+	if err := ChangeAssetStatus(tx, d.AssetID, AssetStatusScrapped); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	d.Status = DisposalStatusCompleted
+	d.DisposalDate = &writeOff.DisposalDate
+	d.CompletedBy = writeOff.WrittenOffBy
+	d.CompletedAt = &now
+	if err := tx.Model(d).Updates(map[string]interface{}{
+		"status":        d.Status,
+		"disposal_date": d.DisposalDate,
+		"completed_by":  d.CompletedBy,
+		"completed_at":  d.CompletedAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	writeOff.DisposalID = d.ID
+	writeOff.AssetID = d.AssetID
+	return tx.Create(writeOff).Error
+}
+
+// AssetWriteOff 资产核销记录，处置完成时生成，保存资产和账面价值快照，不允许修改或删除
+type AssetWriteOff struct {
+	ID                      uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	DisposalID              uint           `json:"disposal_id" gorm:"not null;uniqueIndex"`
+	AssetID                 uint           `json:"asset_id" gorm:"not null;index"`
+	AssetNo                 string         `json:"asset_no" gorm:"size:50"`
+	AssetName               string         `json:"asset_name" gorm:"size:200"`
+	CategoryName            string         `json:"category_name" gorm:"size:100"`
+	DepartmentID            *uint          `json:"department_id" gorm:"index"`
+	DepartmentName          string         `json:"department_name" gorm:"size:100"`
+	Method                  DisposalMethod `json:"method" gorm:"size:20;index"`
+	Reason                  string         `json:"reason" gorm:"type:text"`
+	OriginalValue           float64        `json:"original_value" gorm:"type:decimal(12,2)"`           // 原值
+	AccumulatedDepreciation float64        `json:"accumulated_depreciation" gorm:"type:decimal(12,2)"` // 累计折旧
+	NetValue                float64        `json:"net_value" gorm:"type:decimal(12,2)"`                // 核销时账面净值
+	ResidualValue           float64        `json:"residual_value" gorm:"type:decimal(12,2)"`           // 处置残值收入
+	GainLoss                float64        `json:"gain_loss" gorm:"type:decimal(12,2)"`                // 处置损益 = 残值 - 净值
+	ApprovedBy              string         `json:"approved_by" gorm:"size:100"`
+	ApprovedAt              *time.Time     `json:"approved_at"`
+	DisposalDate            time.Time      `json:"disposal_date" gorm:"not null;index"`
+	WrittenOffBy            string         `json:"written_off_by" gorm:"size:100"`
+	CreatedAt               time.Time      `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AssetWriteOff) TableName() string {
+	return "asset_write_offs"
+}
+
+// HasOpenDisposal 判断资产是否存在进行中的处置申请
+func HasOpenDisposal(tx *gorm.DB, assetID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&AssetDisposal{}).
+		Where("asset_id = ? AND status IN ?", assetID, OpenDisposalStatuses).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsAssetFrozen 判断资产是否因处置已批准或已完成而冻结
+func IsAssetFrozen(tx *gorm.DB, assetID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&AssetDisposal{}).
+		Where("asset_id = ? AND status IN ?", assetID, FrozenDisposalStatuses).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func init() {
+	RegisterAssetStatusGuard(guardDisposal)
+}
+
+// guardDisposal 报废状态须与处置申请一致：
+// 报废必须通过已批准的处置申请完成，处置已批准的资产不能再变更为其他状态
+func guardDisposal(tx *gorm.DB, asset *Asset, to AssetStatus) (string, error) {
+	var approved int64
+	if err := tx.Model(&AssetDisposal{}).
+		Where("asset_id = ? AND status = ?", asset.ID, DisposalStatusApproved).
+		Count(&approved).Error; err != nil {
+		return "", err
+	}
+
+	if to == AssetStatusScrapped {
+		if approved == 0 {
+			return "没有已批准的处置申请，请通过资产处置流程报废", nil
+		}
+		return "", nil
+	}
+	if approved > 0 {
+		return "资产处置已批准，已冻结", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// AssetNoSequence 资产编号流水号，每个编号范围（编号规则中流水号以外部分）一行
+type AssetNoSequence struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SeqKey    string    `json:"seq_key" gorm:"size:200;uniqueIndex;not null"` // 编号范围，如 ASSET-LAPTOP-2026-{SEQ:4}
+	Value     int64     `json:"value" gorm:"not null;default:0"`              // 最后分配的流水号
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AssetNoSequence) TableName() string {
+	return "asset_no_sequences"
+}
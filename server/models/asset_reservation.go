@@ -0,0 +1,140 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReservationStatus 预约状态枚举
+type ReservationStatus string
+
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"  // 已预约
+	ReservationStatusFulfilled ReservationStatus = "fulfilled" // 已取用，已转为借用记录
+	ReservationStatusCancelled ReservationStatus = "cancelled" // 已取消
+	ReservationStatusExpired   ReservationStatus = "expired"   // 未按时取用，已过期
+)
+
+// AssetReservation 资产预约模型，在指定时间段内为借用人保留资产
+type AssetReservation struct {
+	ID              uint              `json:"id" gorm:"primaryKey;autoIncrement"`
+	AssetID         uint              `json:"asset_id" gorm:"not null;index" validate:"required"`
+	ReserverName    string            `json:"reserver_name" gorm:"size:100;not null" validate:"required,max=100"`
+	ReserverContact string            `json:"reserver_contact" gorm:"size:100" validate:"max=100"`
+	DepartmentID    *uint             `json:"department_id" gorm:"index"`
+	StartTime       time.Time         `json:"start_time" gorm:"not null;index" validate:"required"`
+	EndTime         time.Time         `json:"end_time" gorm:"not null;index" validate:"required"`
+	Purpose         string            `json:"purpose" gorm:"type:text"`
+	Notes           string            `json:"notes" gorm:"type:text"`
+	Status          ReservationStatus `json:"status" gorm:"size:20;default:reserved;index" validate:"oneof=reserved fulfilled cancelled expired"`
+	BorrowRecordID  *uint             `json:"borrow_record_id" gorm:"index"` // 取用时生成的借用记录
+	ReservedBy      string            `json:"reserved_by" gorm:"size:100"`
+	FulfilledAt     *time.Time        `json:"fulfilled_at"`
+	CancelledBy     string            `json:"cancelled_by" gorm:"size:100"`
+	CancelledAt     *time.Time        `json:"cancelled_at"`
+	CancelReason    string            `json:"cancel_reason" gorm:"type:text"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	// 关联关系
+	Asset        Asset         `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+	Department   *Department   `json:"department,omitempty" gorm:"foreignKey:DepartmentID"`
+	BorrowRecord *BorrowRecord `json:"borrow_record,omitempty" gorm:"foreignKey:BorrowRecordID"`
+}
+
+// TableName 指定表名
+func (AssetReservation) TableName() string {
+	return "asset_reservations"
+}
+
+// BeforeCreate 创建前钩子
+func (r *AssetReservation) BeforeCreate(tx *gorm.DB) error {
+	// 设置默认状态
+	if r.Status == "" {
+		r.Status = ReservationStatusReserved
+	}
+	return nil
+}
+
+// PickupDeadline 取用截止时间：开始时间加宽限时间，且不晚于结束时间
+func (r *AssetReservation) PickupDeadline(grace time.Duration) time.Time {
+	deadline := r.StartTime.Add(grace)
+	if deadline.After(r.EndTime) {
+		return r.EndTime
+	}
+	return deadline
+}
+
+// IsActive 是否为有效预约：已预约且未过取用截止时间
+func (r *AssetReservation) IsActive(grace time.Duration) bool {
+	return r.Status == ReservationStatusReserved && time.Now().Before(r.PickupDeadline(grace))
+}
+
+// ActiveReservations 限定为有效预约，未被定时任务置为过期的超时预约同样排除
+func ActiveReservations(db *gorm.DB, grace time.Duration) *gorm.DB {
+	now := time.Now()
+	return db.Where("asset_reservations.status = ? AND asset_reservations.start_time > ? AND asset_reservations.end_time > ?",
+		ReservationStatusReserved, now.Add(-grace), now)
+}
+
+// ExpireReservations 将超过取用截止时间仍未取用的预约置为已过期，返回过期的预约数
+func ExpireReservations(db *gorm.DB, grace time.Duration) (int64, error) {
+	now := time.Now()
+	result := db.Model(&AssetReservation{}).
+		Where("status = ? AND (start_time <= ? OR end_time <= ?)", ReservationStatusReserved, now.Add(-grace), now).
+		Update("status", ReservationStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// FindReservationConflicts 查找资产在时间段内与之重叠的有效预约
+// end 为空表示时间段不设结束时间，excludeID 为不参与比较的预约（如正在取用的预约）
+func FindReservationConflicts(db *gorm.DB, assetID uint, start time.Time, end *time.Time, grace time.Duration, excludeID uint) ([]AssetReservation, error) {
+	query := ActiveReservations(db, grace).Where("asset_id = ? AND end_time > ?", assetID, start)
+	if end != nil {
+		query = query.Where("start_time < ?", *end)
+	}
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var reservations []AssetReservation
+	if err := query.Order("start_time ASC").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// FindBorrowConflict 查找在指定时间之后仍占用资产的未归还借用记录
+// 已逾期或未填写预计归还日期的借用无法确定归还时间，视为一直占用
+func FindBorrowConflict(db *gorm.DB, assetID uint, start time.Time) (*BorrowRecord, error) {
+	var record BorrowRecord
+	err := db.Where("asset_id = ?", assetID).
+		Where("status = ? OR (status = ? AND (expected_return_date IS NULL OR expected_return_date > ?))",
+			BorrowStatusOverdue, BorrowStatusBorrowed, start).
+		Order("borrow_date DESC").
+		First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// AssetsFreeDuring 限定为整个时间段内可借用的资产：
+// 未报废、未维修、未冻结，没有届时仍未归还的借用，也没有与时间段重叠的有效预约
+func AssetsFreeDuring(db *gorm.DB, start, end time.Time, grace time.Duration) *gorm.DB {
+	now := time.Now()
+	return db.
+		Where("assets.status IN ?", []AssetStatus{AssetStatusAvailable, AssetStatusBorrowed}).
+		Where("assets.id NOT IN (SELECT asset_id FROM asset_disposals WHERE status IN ? AND deleted_at IS NULL)", FrozenDisposalStatuses).
+		Where("assets.id NOT IN (SELECT asset_id FROM borrow_records WHERE deleted_at IS NULL AND "+
+			"(status = ? OR (status = ? AND (expected_return_date IS NULL OR expected_return_date > ?))))",
+			BorrowStatusOverdue, BorrowStatusBorrowed, start).
+		Where("assets.id NOT IN (SELECT asset_id FROM asset_reservations WHERE deleted_at IS NULL AND "+
+			"status = ? AND start_time > ? AND end_time > ? AND start_time < ? AND end_time > ?)",
+			ReservationStatusReserved, now.Add(-grace), now, end, start)
+}
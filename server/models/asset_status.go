@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// AssetStatusTransitions 资产状态流转表：当前状态 -> 允许的下一状态
+// 所有资产状态写入都必须经过 CheckAssetStatusTransition 或 ChangeAssetStatus
+var AssetStatusTransitions = map[AssetStatus][]AssetStatus{
+	AssetStatusAvailable:   {AssetStatusBorrowed, AssetStatusMaintenance, AssetStatusScrapped},
+	AssetStatusBorrowed:    {AssetStatusAvailable},
+	AssetStatusMaintenance: {AssetStatusAvailable, AssetStatusScrapped},
+	AssetStatusScrapped:    {},
+}
+
+// InitialAssetStatuses 新建资产允许的初始状态，借用中只能通过借用登记产生，维护中只能通过维修工单产生
+var InitialAssetStatuses = []AssetStatus{AssetStatusAvailable, AssetStatusScrapped}
+
+// AssetStatusGuard 状态流转守卫，返回非空字符串表示拒绝原因
+type AssetStatusGuard func(tx *gorm.DB, asset *Asset, to AssetStatus) (string, error)
+
+// assetStatusGuards 已注册的状态流转守卫
+var assetStatusGuards = []AssetStatusGuard{
+	guardOpenBorrow,
+}
+
+// RegisterAssetStatusGuard 注册状态流转守卫
+func RegisterAssetStatusGuard(guard AssetStatusGuard) {
+	assetStatusGuards = append(assetStatusGuards, guard)
+}
+
+// AssetStatusTransitionError 非法状态流转错误
+type AssetStatusTransitionError struct {
+	AssetID uint          `json:"asset_id,omitempty"`
+	From    AssetStatus   `json:"from"`
+	To      AssetStatus   `json:"to"`
+	Allowed []AssetStatus `json:"allowed"`
+	Reason  string        `json:"reason,omitempty"`
+}
+
+// Error 实现 error 接口
+func (e *AssetStatusTransitionError) Error() string {
+	allowed := make([]string, len(e.Allowed))
+	for i, status := range e.Allowed {
+		allowed[i] = string(status)
+	}
+	allowedText := strings.Join(allowed, ", ")
+	if allowedText == "" {
+		allowedText = "无"
+	}
+
+	if e.Reason != "" {
+		return fmt.Sprintf("资产状态不能从 %s 变更为 %s：%s", e.From, e.To, e.Reason)
+	}
+	if e.From == "" {
+		return fmt.Sprintf("新建资产的状态不能为 %s，允许的状态: %s", e.To, allowedText)
+	}
+	return fmt.Sprintf("资产状态不能从 %s 变更为 %s，允许的下一状态: %s", e.From, e.To, allowedText)
+}
+
+// AllowedNextStatuses 获取允许的下一状态
+func AllowedNextStatuses(from AssetStatus) []AssetStatus {
+	return AssetStatusTransitions[from]
+}
+
+// CheckInitialAssetStatus 校验新建资产的初始状态
+func CheckInitialAssetStatus(status AssetStatus) error {
+	if status == "" {
+		return nil
+	}
+	for _, allowed := range InitialAssetStatuses {
+		if status == allowed {
+			return nil
+		}
+	}
+	return &AssetStatusTransitionError{To: status, Allowed: InitialAssetStatuses}
+}
+
+// CheckAssetStatusTransition 校验资产状态流转是否合法，状态未变化时直接通过
+func CheckAssetStatusTransition(tx *gorm.DB, asset *Asset, to AssetStatus) error {
+	if asset.Status == to {
+		return nil
+	}
+
+	allowed := AllowedNextStatuses(asset.Status)
+	transitionErr := &AssetStatusTransitionError{
+		AssetID: asset.ID,
+		From:    asset.Status,
+		To:      to,
+		Allowed: allowed,
+	}
+
+	permitted := false
+	for _, status := range allowed {
+		if status == to {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return transitionErr
+	}
+
+	for _, guard := range assetStatusGuards {
+		reason, err := guard(tx, asset, to)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			transitionErr.Reason = reason
+			return transitionErr
+		}
+	}
+
+	return nil
+}
+
+// ChangeAssetStatus 校验并写入资产状态
+func ChangeAssetStatus(tx *gorm.DB, assetID uint, to AssetStatus) error {
+	var asset Asset
+	if err := tx.First(&asset, assetID).Error; err != nil {
+		return err
+	}
+	if asset.Status == to {
+		return nil
+	}
+	if err := CheckAssetStatusTransition(tx, &asset, to); err != nil {
+		return err
+	}
+	return tx.Model(&asset).Update("status", to).Error
+}
+
+// guardOpenBorrow 借用状态须与借用记录一致：
+// 进入借用中必须存在未归还的借用记录，离开借用中或进入维护、报废前借用记录必须已归还
+func guardOpenBorrow(tx *gorm.DB, asset *Asset, to AssetStatus) (string, error) {
+	var openBorrows int64
+	if err := tx.Model(&BorrowRecord{}).
+		Where("asset_id = ? AND status IN ?", asset.ID, []BorrowStatus{BorrowStatusBorrowed, BorrowStatusOverdue}).
+		Count(&openBorrows).Error; err != nil {
+		return "", err
+	}
+
+	if to == AssetStatusBorrowed {
+		if openBorrows == 0 {
+			return "没有未归还的借用记录，请通过借用登记借出资产", nil
+		}
+		return "", nil
+	}
+	if openBorrows > 0 {
+		return "存在未归还的借用记录，请先办理归还", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TransferStatus 调拨状态枚举
+type TransferStatus string
+
+const (
+	TransferStatusRequested TransferStatus = "requested" // 已申请
+	TransferStatusApproved  TransferStatus = "approved"  // 已审批
+	TransferStatusCompleted TransferStatus = "completed" // 已完成
+	TransferStatusRejected  TransferStatus = "rejected"  // 已驳回
+)
+
+// TransferStatusTransitions 调拨单状态流转表：当前状态 -> 允许的下一状态
+var TransferStatusTransitions = map[TransferStatus][]TransferStatus{
+	TransferStatusRequested: {TransferStatusApproved, TransferStatusRejected},
+	TransferStatusApproved:  {TransferStatusCompleted, TransferStatusRejected},
+	TransferStatusCompleted: {},
+	TransferStatusRejected:  {},
+}
+
+// OpenTransferStatuses 进行中的调拨状态，同一资产同时只能有一张进行中的调拨单
+var OpenTransferStatuses = []TransferStatus{TransferStatusRequested, TransferStatusApproved}
+
+// ErrTransferAssetScrapped 资产已报废，不能调拨
+var ErrTransferAssetScrapped = errors.New("资产已报废，不能调拨")
+
+// ErrTransferAssetFrozen 资产处置已批准，不能调拨
+var ErrTransferAssetFrozen = errors.New("资产处置已批准，不能调拨")
+
+// AssetTransfer 资产调拨单模型
+type AssetTransfer struct {
+	ID                    uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	AssetID               uint           `json:"asset_id" gorm:"not null;index" validate:"required"`
+	FromDepartmentID      *uint          `json:"from_department_id" gorm:"index"`
+	ToDepartmentID        uint           `json:"to_department_id" gorm:"not null;index" validate:"required"`
+	FromLocation          string         `json:"from_location" gorm:"size:200"`
+	ToLocation            string         `json:"to_location" gorm:"size:200" validate:"max=200"`
+	FromResponsiblePerson string         `json:"from_responsible_person" gorm:"size:100"`
+	ToResponsiblePerson   string         `json:"to_responsible_person" gorm:"size:100" validate:"max=100"`
+	Reason                string         `json:"reason" gorm:"type:text"`
+	IncludeComponents     bool           `json:"include_components"` // 完成时组件随父资产一同调拨
+	Status                TransferStatus `json:"status" gorm:"size:20;default:requested;index" validate:"oneof=requested approved completed rejected"`
+	RequestedBy           string         `json:"requested_by" gorm:"size:100"`
+	ReviewedBy            string         `json:"reviewed_by" gorm:"size:100"`
+	ReviewedAt            *time.Time     `json:"reviewed_at"`
+	ReviewComment         string         `json:"review_comment" gorm:"type:text"`
+	CompletedBy           string         `json:"completed_by" gorm:"size:100"`
+	CompletedAt           *time.Time     `json:"completed_at"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 关联关系
+	Asset          Asset       `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+	FromDepartment *Department `json:"from_department,omitempty" gorm:"foreignKey:FromDepartmentID"`
+	ToDepartment   *Department `json:"to_department,omitempty" gorm:"foreignKey:ToDepartmentID"`
+}
+
+// TableName 指定表名
+func (AssetTransfer) TableName() string {
+	return "asset_transfers"
+}
+
+// BeforeCreate 创建前钩子
+func (t *AssetTransfer) BeforeCreate(tx *gorm.DB) error {
+	// 设置默认状态
+	if t.Status == "" {
+		t.Status = TransferStatusRequested
+	}
+	return nil
+}
+
+// IsOpenTransferStatus 判断调拨状态是否为进行中
+func IsOpenTransferStatus(status TransferStatus) bool {
+	for _, open := range OpenTransferStatuses {
+		if status == open {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen 是否为进行中的调拨单
+func (t *AssetTransfer) IsOpen() bool {
+	return IsOpenTransferStatus(t.Status)
+}
+
+// CanTransitionTo 判断调拨单能否流转到指定状态
+func (t *AssetTransfer) CanTransitionTo(to TransferStatus) bool {
+	for _, status := range TransferStatusTransitions[t.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete 完成调拨：更新资产的部门、位置和责任人，并将调拨单置为已完成
+// 须在事务中调用，保证资产和调拨单同时更新
+func (t *AssetTransfer) Complete(tx *gorm.DB, operator string) error {
+	var asset Asset
+	if err := tx.First(&asset, t.AssetID).Error; err != nil {
+		return err
+	}
+	if asset.Status == AssetStatusScrapped {
+		return ErrTransferAssetScrapped
+	}
+	frozen, err := IsAssetFrozen(tx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if frozen {
+		return ErrTransferAssetFrozen
+	}
+
+	updates := map[string]interface{}{
+		"department_id": t.ToDepartmentID,
+	}
+	if t.ToLocation != "" {
+		updates["location"] = t.ToLocation
+	}
+	if t.ToResponsiblePerson != "" {
+		updates["responsible_person"] = t.ToResponsiblePerson
+	}
+	if err := tx.Model(&asset).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if t.IncludeComponents {
+		if err := t.transferComponents(tx, operator); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	t.Status = TransferStatusCompleted
+	t.CompletedBy = operator
+	t.CompletedAt = &now
+	return tx.Model(t).Updates(map[string]interface{}{
+		"status":       t.Status,
+		"completed_by": t.CompletedBy,
+		"completed_at": t.CompletedAt,
+	}).Error
+}
+
+// transferComponents 将父资产的下级组件一并调拨到目标部门，每个组件补记一张已完成的调拨单
+// 已报废、已冻结或有自己进行中调拨单的组件保持不变
+func (t *AssetTransfer) transferComponents(tx *gorm.DB, operator string) error {
+	ids, err := ComponentDescendantIDs(tx, t.AssetID)
+	if err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("随父资产调拨（调拨单 #%d）", t.ID)
+	for _, id := range ids {
+		var component Asset
+		if err := tx.First(&component, id).Error; err != nil {
+			return err
+		}
+		if component.Status == AssetStatusScrapped {
+			continue
+		}
+		frozen, err := IsAssetFrozen(tx, component.ID)
+		if err != nil {
+			return err
+		}
+		open, err := HasOpenTransfer(tx, component.ID)
+		if err != nil {
+			return err
+		}
+		if frozen || open {
+			continue
+		}
+
+		if err := RecordDirectTransfer(tx, &component, t.ToDepartmentID, t.ToLocation, t.ToResponsiblePerson, operator, reason); err != nil {
+			return err
+		}
+		updates := map[string]interface{}{
+			"department_id": t.ToDepartmentID,
+		}
+		if t.ToLocation != "" {
+			updates["location"] = t.ToLocation
+		}
+		if t.ToResponsiblePerson != "" {
+			updates["responsible_person"] = t.ToResponsiblePerson
+		}
+		if err := tx.Model(&component).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasOpenTransfer 判断资产是否存在进行中的调拨单
+func HasOpenTransfer(tx *gorm.DB, assetID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&AssetTransfer{}).
+		Where("asset_id = ? AND status IN ?", assetID, OpenTransferStatuses).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecordDirectTransfer 为直接修改资产部门的操作补记一张已完成的调拨单
+// asset 为修改前的资产，部门未变化时不记录
+func RecordDirectTransfer(tx *gorm.DB, asset *Asset, toDepartmentID uint, toLocation, toResponsiblePerson, operator, reason string) error {
+	if asset.DepartmentID != nil && *asset.DepartmentID == toDepartmentID {
+		return nil
+	}
+
+	now := time.Now()
+	transfer := AssetTransfer{
+		AssetID:               asset.ID,
+		FromDepartmentID:      asset.DepartmentID,
+		ToDepartmentID:        toDepartmentID,
+		FromLocation:          asset.Location,
+		ToLocation:            toLocation,
+		FromResponsiblePerson: asset.ResponsiblePerson,
+		ToResponsiblePerson:   toResponsiblePerson,
+		Reason:                reason,
+		Status:                TransferStatusCompleted,
+		RequestedBy:           operator,
+		ReviewedBy:            operator,
+		ReviewedAt:            &now,
+		CompletedBy:           operator,
+		CompletedAt:           &now,
+	}
+	return tx.Create(&transfer).Error
+}
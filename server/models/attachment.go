@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 附件所属记录类型
+const (
+	AttachmentOwnerAsset       = "asset"       // 资产
+	AttachmentOwnerBorrow      = "borrow"      // 借用记录
+	AttachmentOwnerMaintenance = "maintenance" // 维修工单
+)
+
+// AttachmentOwnerTypes 全部附件所属记录类型
+var AttachmentOwnerTypes = []string{AttachmentOwnerAsset, AttachmentOwnerBorrow, AttachmentOwnerMaintenance}
+
+// AttachmentKind 附件类别枚举
+type AttachmentKind string
+
+const (
+	AttachmentKindInvoice  AttachmentKind = "invoice"  // 发票
+	AttachmentKindManual   AttachmentKind = "manual"   // 说明书
+	AttachmentKindPhoto    AttachmentKind = "photo"    // 照片
+	AttachmentKindContract AttachmentKind = "contract" // 合同
+	AttachmentKindOther    AttachmentKind = "other"    // 其他
+)
+
+// Attachment 附件模型，文件保存在上传目录，记录关联到资产、借用记录或维修工单
+type Attachment struct {
+	ID          uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	OwnerType   string         `json:"owner_type" gorm:"size:20;not null;index:idx_attachments_owner"`
+	OwnerID     uint           `json:"owner_id" gorm:"not null;index:idx_attachments_owner"`
+	AssetID     uint           `json:"asset_id" gorm:"not null;index"` // 所属资产，借用记录和维修工单取其资产，用于数据范围过滤
+	Kind        AttachmentKind `json:"kind" gorm:"size:20;default:other;index"`
+	FileName    string         `json:"file_name" gorm:"size:255;not null"` // 原始文件名
+	FilePath    string         `json:"-" gorm:"size:500;not null"`         // 存储路径，只通过下载接口访问
+	MimeType    string         `json:"mime_type" gorm:"size:100"`
+	FileSize    int64          `json:"file_size"`
+	Checksum    string         `json:"checksum" gorm:"size:64;index"` // SHA-256 校验和
+	Description string         `json:"description" gorm:"size:500"`
+	UploadedBy  string         `json:"uploaded_by" gorm:"size:100"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// BeforeCreate 创建前钩子
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	// 设置默认类别
+	if a.Kind == "" {
+		a.Kind = AttachmentKindOther
+	}
+	return nil
+}
+
+// ReferencedUploadPaths 获取业务数据引用的全部上传文件路径（含已软删除的记录，以便恢复）
+func ReferencedUploadPaths(db *gorm.DB) ([]string, error) {
+	var paths []string
+
+	// 附件、资产图片、报表文件
+	sources := []struct {
+		model  interface{}
+		column string
+	}{
+		{&Attachment{}, "file_path"},
+		{&Asset{}, "image_url"},
+		{&ReportRecord{}, "file_path"},
+	}
+	for _, source := range sources {
+		var values []string
+		if err := db.Unscoped().Model(source.model).
+			Where(source.column+" IS NOT NULL AND "+source.column+" <> ''").
+			Pluck(source.column, &values).Error; err != nil {
+			return nil, err
+		}
+		paths = append(paths, values...)
+	}
+
+	// 维修工单以 JSON 数组保存的附件路径
+	var lists []string
+	if err := db.Unscoped().Model(&MaintenanceOrder{}).
+		Where("attachments IS NOT NULL").
+		Pluck("attachments", &lists).Error; err != nil {
+		return nil, err
+	}
+	for _, list := range lists {
+		var values []string
+		if json.Unmarshal([]byte(list), &values) == nil {
+			paths = append(paths, values...)
+		}
+	}
+
+	return paths, nil
+}
@@ -28,6 +28,7 @@ type BorrowRecord struct {
 	Status               BorrowStatus   `json:"status" gorm:"size:20;default:borrowed" validate:"oneof=borrowed returned overdue"`
 	Purpose              string         `json:"purpose" gorm:"type:text"`
 	Notes                string         `json:"notes" gorm:"type:text"`
+	ParentRecordID       *uint          `json:"parent_record_id" gorm:"index"` // 随父资产一同借出时指向父资产的借用记录
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
 	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
@@ -35,6 +36,7 @@ type BorrowRecord struct {
 	// 关联关系
 	Asset      Asset       `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
 	Department *Department `json:"department,omitempty" gorm:"foreignKey:DepartmentID"`
+	ComponentRecords []BorrowRecord `json:"component_records,omitempty" gorm:"foreignKey:ParentRecordID"`
 }
 
 // TableName 指定表名
@@ -70,16 +72,33 @@ func (br *BorrowRecord) BeforeUpdate(tx *gorm.DB) error {
 // AfterCreate 创建后钩子
 func (br *BorrowRecord) AfterCreate(tx *gorm.DB) error {
 	// 更新资产状态为借用中
-	return tx.Model(&Asset{}).Where("id = ?", br.AssetID).Update("status", AssetStatusBorrowed).Error
+	return ChangeAssetStatus(tx, br.AssetID, AssetStatusBorrowed)
 }
 
 // AfterUpdate 更新后钩子
 func (br *BorrowRecord) AfterUpdate(tx *gorm.DB) error {
-	// 如果归还了，更新资产状态为可用
-	if br.Status == BorrowStatusReturned {
-		return tx.Model(&Asset{}).Where("id = ?", br.AssetID).Update("status", AssetStatusAvailable).Error
+	// 如果归还了，借用中的资产恢复为可用
+	if br.Status != BorrowStatusReturned || br.AssetID == 0 {
+		return nil
 	}
-	return nil
+	var asset Asset
+	if err := tx.Select("id", "status").First(&asset, br.AssetID).Error; err != nil {
+		return err
+	}
+	if asset.Status != AssetStatusBorrowed {
+		return nil
+	}
+	return ChangeAssetStatus(tx, br.AssetID, AssetStatusAvailable)
+}
+
+// AfterSave 保存后钩子，同步全文检索文档
+func (br *BorrowRecord) AfterSave(tx *gorm.DB) error {
+	return SyncSearchDocuments(tx, SearchKindBorrow, br.ID)
+}
+
+// AfterDelete 删除后钩子，移除全文检索文档
+func (br *BorrowRecord) AfterDelete(tx *gorm.DB) error {
+	return RemoveSearchDocument(tx, SearchKindBorrow, br.ID)
 }
 
 // IsOverdue 检查是否超期
@@ -104,4 +123,22 @@ func (br *BorrowRecord) Return(tx *gorm.DB) error {
 	br.ActualReturnDate = &now
 	br.Status = BorrowStatusReturned
 	return tx.Save(br).Error
-}
\ No newline at end of file
+}
+
+// ReturnComponentRecords 归还随父资产借出且尚未归还的组件借用记录
+func ReturnComponentRecords(tx *gorm.DB, parentRecordID uint, returnDate time.Time) error {
+	var records []BorrowRecord
+	if err := tx.Where("parent_record_id = ? AND status <> ?", parentRecordID, BorrowStatusReturned).
+		Find(&records).Error; err != nil {
+		return err
+	}
+	for i := range records {
+		if err := tx.Model(&records[i]).Updates(map[string]interface{}{
+			"actual_return_date": returnDate,
+			"status":             BorrowStatusReturned,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// BulkJobType 批量任务类型枚举
+type BulkJobType string
+
+const (
+	BulkJobTypeAssetUpdate BulkJobType = "asset_update" // 批量更新资产
+	BulkJobTypeAssetDelete BulkJobType = "asset_delete" // 批量删除资产
+)
+
+// BulkJobStatus 批量任务状态枚举
+type BulkJobStatus string
+
+const (
+	BulkJobStatusPending   BulkJobStatus = "pending"   // 排队中
+	BulkJobStatusRunning   BulkJobStatus = "running"   // 执行中
+	BulkJobStatusCompleted BulkJobStatus = "completed" // 已完成
+	BulkJobStatusCancelled BulkJobStatus = "cancelled" // 已取消
+	BulkJobStatusFailed    BulkJobStatus = "failed"    // 异常终止
+)
+
+// BulkJob 后台批量任务模型，按批次处理目标资产并记录进度
+type BulkJob struct {
+	ID              uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Type            BulkJobType    `json:"type" gorm:"size:30;not null;index"`
+	Status          BulkJobStatus  `json:"status" gorm:"size:20;default:pending;index"`
+	Params          datatypes.JSON `json:"params" gorm:"type:json"`    // 任务参数，如更新内容
+	Filters         datatypes.JSON `json:"filters" gorm:"type:json"`   // 按筛选条件提交时的条件，仅供查看
+	AssetIDs        datatypes.JSON `json:"-" gorm:"type:json"`         // 目标资产ID，提交时按数据范围解析
+	Total           int            `json:"total"`                      // 目标资产数
+	Processed       int            `json:"processed"`                  // 已处理数
+	SucceededCount  int            `json:"succeeded_count"`            // 成功数
+	FailedCount     int            `json:"failed_count"`               // 失败数
+	CancelRequested bool           `json:"cancel_requested"`           // 已请求取消，当前批次完成后停止
+	Error           string         `json:"error" gorm:"type:text"`     // 异常终止原因
+	CreatedBy       string         `json:"created_by" gorm:"size:100"` // 提交人
+	CreatedByID     uint           `json:"created_by_id" gorm:"index"`
+	StartedAt       *time.Time     `json:"started_at"`
+	FinishedAt      *time.Time     `json:"finished_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (BulkJob) TableName() string {
+	return "bulk_jobs"
+}
+
+// IsFinished 任务是否已结束
+func (j *BulkJob) IsFinished() bool {
+	return j.Status == BulkJobStatusCompleted || j.Status == BulkJobStatusCancelled || j.Status == BulkJobStatusFailed
+}
+
+// Progress 完成百分比
+func (j *BulkJob) Progress() float64 {
+	if j.Total == 0 {
+		return 100
+	}
+	return float64(j.Processed) * 100 / float64(j.Total)
+}
+
+// BulkJobError 批量任务中单个资产的失败记录
+type BulkJobError struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobID     uint      `json:"job_id" gorm:"not null;index"`
+	AssetID   uint      `json:"asset_id" gorm:"index"`
+	AssetNo   string    `json:"asset_no" gorm:"size:100"`
+	Error     string    `json:"error" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (BulkJobError) TableName() string {
+	return "bulk_job_errors"
+}
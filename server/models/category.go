@@ -9,15 +9,18 @@ import (
 
 // Category 资产分类模型
 type Category struct {
-	ID          uint           `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name        string         `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
-	Code        string         `json:"code" gorm:"size:50;uniqueIndex;not null" validate:"required,max=50"`
-	ParentID    *uint          `json:"parent_id" gorm:"index"`
-	Description string         `json:"description" gorm:"type:text"`
-	Attributes  datatypes.JSON `json:"attributes" gorm:"type:json"` // 分类特定属性模板
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                 uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name               string             `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Code               string             `json:"code" gorm:"size:50;uniqueIndex:idx_categories_code_active,where:deleted_at IS NULL;not null" validate:"required,max=50"`
+	ParentID           *uint              `json:"parent_id" gorm:"index"`
+	Description        string             `json:"description" gorm:"type:text"`
+	Attributes         datatypes.JSON     `json:"attributes" gorm:"type:json"`           // 分类特定属性模板
+	DepreciationMethod DepreciationMethod `json:"depreciation_method" gorm:"size:30"`    // 折旧方法
+	UsefulLifeMonths   *int               `json:"useful_life_months"`                    // 使用寿命（月）
+	SalvageRate        *float64           `json:"salvage_rate" gorm:"type:decimal(5,4)"` // 残值率（0-1）
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt     `json:"-" gorm:"index"`
 
 	// 关联关系
 	Parent   *Category  `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
@@ -65,9 +68,36 @@ func (c *Category) BeforeDelete(tx *gorm.DB) error {
 	return nil
 }
 
+// AfterSave 保存后钩子，同步全文检索文档
+func (c *Category) AfterSave(tx *gorm.DB) error {
+	return SyncSearchDocuments(tx, SearchKindCategory, c.ID)
+}
+
+// AfterDelete 删除后钩子，移除全文检索文档
+func (c *Category) AfterDelete(tx *gorm.DB) error {
+	return RemoveSearchDocument(tx, SearchKindCategory, c.ID)
+}
+
+// 未定义字段处理策略
+const (
+	UnknownFieldsAllow  = "allow"  // 原样保留（默认）
+	UnknownFieldsStrip  = "strip"  // 丢弃
+	UnknownFieldsReject = "reject" // 报错
+)
+
+// 分类字段类型
+const (
+	FieldTypeText    = "text"
+	FieldTypeNumber  = "number"
+	FieldTypeDate    = "date"
+	FieldTypeSelect  = "select"
+	FieldTypeBoolean = "boolean"
+)
+
 // CategoryAttributes 分类属性模板结构
 type CategoryAttributes struct {
-	Fields []CategoryField `json:"fields"`
+	Fields        []CategoryField `json:"fields"`
+	UnknownFields string          `json:"unknown_fields,omitempty"` // 未定义字段处理策略：allow, strip, reject
 }
 
 // CategoryField 分类字段定义
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConsumableTransactionType 耗材出入库类型枚举
+type ConsumableTransactionType string
+
+const (
+	ConsumableTransactionIn  ConsumableTransactionType = "in"  // 入库
+	ConsumableTransactionOut ConsumableTransactionType = "out" // 出库（领用）
+)
+
+// ErrInsufficientStock 库存不足
+var ErrInsufficientStock = errors.New("库存不足")
+
+// Consumable 耗材模型，按数量管理的物品（如硒鼓、线缆、鼠标），库存按存放位置记录
+type Consumable struct {
+	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code          string         `json:"code" gorm:"size:50;uniqueIndex:idx_consumables_code_active,where:deleted_at IS NULL;not null"`
+	Name          string         `json:"name" gorm:"size:200;not null;index"`
+	Specification string         `json:"specification" gorm:"size:200"` // 规格型号
+	Unit          string         `json:"unit" gorm:"size:20"`           // 计量单位
+	MinStock      int            `json:"min_stock"`                     // 最低库存，总库存低于该值时预警，0 表示不预警
+	Description   string         `json:"description" gorm:"type:text"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 关联关系
+	Stocks []ConsumableStock `json:"stocks,omitempty" gorm:"foreignKey:ConsumableID"`
+}
+
+// TableName 指定表名
+func (Consumable) TableName() string {
+	return "consumables"
+}
+
+// TotalStock 各位置库存合计，须已加载 Stocks
+func (c *Consumable) TotalStock() int {
+	total := 0
+	for _, stock := range c.Stocks {
+		total += stock.Quantity
+	}
+	return total
+}
+
+// IsLowStock 总库存是否低于最低库存，须已加载 Stocks
+func (c *Consumable) IsLowStock() bool {
+	return c.MinStock > 0 && c.TotalStock() < c.MinStock
+}
+
+// ConsumableStock 耗材在某个存放位置的库存
+type ConsumableStock struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ConsumableID uint      `json:"consumable_id" gorm:"not null;uniqueIndex:idx_consumable_stocks_location"`
+	Location     string    `json:"location" gorm:"size:200;not null;uniqueIndex:idx_consumable_stocks_location"`
+	Quantity     int       `json:"quantity" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ConsumableStock) TableName() string {
+	return "consumable_stocks"
+}
+
+// ConsumableTransaction 耗材出入库流水，记录每笔变动后的位置余额和总余额，构成库存台账
+type ConsumableTransaction struct {
+	ID                uint                      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ConsumableID      uint                      `json:"consumable_id" gorm:"not null;index"`
+	Type              ConsumableTransactionType `json:"type" gorm:"size:10;not null;index"`
+	Location          string                    `json:"location" gorm:"size:200;not null;index"`
+	Quantity          int                       `json:"quantity" gorm:"not null"`             // 变动数量，始终为正数
+	BalanceAfter      int                       `json:"balance_after"`                        // 变动后该位置余额
+	TotalBalanceAfter int                       `json:"total_balance_after"`                  // 变动后各位置合计余额
+	UnitPrice         *float64                  `json:"unit_price" gorm:"type:decimal(12,2)"` // 入库单价
+	DepartmentID      *uint                     `json:"department_id" gorm:"index"`           // 领用部门
+	RecipientName     string                    `json:"recipient_name" gorm:"size:100"`       // 领用人
+	Supplier          string                    `json:"supplier" gorm:"size:200"`             // 入库供应商
+	Notes             string                    `json:"notes" gorm:"type:text"`
+	Operator          string                    `json:"operator" gorm:"size:100"`
+	CreatedAt         time.Time                 `json:"created_at" gorm:"index"`
+
+	// 关联关系
+	Consumable *Consumable `json:"consumable,omitempty" gorm:"foreignKey:ConsumableID"`
+	Department *Department `json:"department,omitempty" gorm:"foreignKey:DepartmentID"`
+}
+
+// TableName 指定表名
+func (ConsumableTransaction) TableName() string {
+	return "consumable_transactions"
+}
+
+// Delta 对库存的变动量，出库为负数
+func (t *ConsumableTransaction) Delta() int {
+	if t.Type == ConsumableTransactionOut {
+		return -t.Quantity
+	}
+	return t.Quantity
+}
+
+// RecordConsumableTransaction 登记出入库流水并更新该位置库存，出库数量超过该位置库存时返回 ErrInsufficientStock，须在事务中调用
+func RecordConsumableTransaction(tx *gorm.DB, t *ConsumableTransaction) error {
+	var stock ConsumableStock
+	err := tx.Where("consumable_id = ? AND location = ?", t.ConsumableID, t.Location).First(&stock).Error
+	if err == gorm.ErrRecordNotFound {
+		if t.Type == ConsumableTransactionOut {
+			return ErrInsufficientStock
+		}
+		stock = ConsumableStock{ConsumableID: t.ConsumableID, Location: t.Location}
+		if err := tx.Create(&stock).Error; err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// 以条件更新扣减库存，避免并发出库导致余额为负
+	delta := t.Delta()
+	result := tx.Model(&ConsumableStock{}).
+		Where("id = ? AND quantity + ? >= 0", stock.ID, delta).
+		Update("quantity", gorm.Expr("quantity + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+
+	if err := tx.Model(&ConsumableStock{}).Where("id = ?", stock.ID).Pluck("quantity", &t.BalanceAfter).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&ConsumableStock{}).
+		Where("consumable_id = ?", t.ConsumableID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&t.TotalBalanceAfter).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(t).Error
+}
+
+// LowStockConsumables 总库存低于最低库存的耗材
+func LowStockConsumables(db *gorm.DB) *gorm.DB {
+	return db.Model(&Consumable{}).
+		Where("min_stock > 0").
+		Where("min_stock > (SELECT COALESCE(SUM(quantity), 0) FROM consumable_stocks WHERE consumable_stocks.consumable_id = consumables.id)")
+}
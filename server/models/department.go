@@ -8,17 +8,21 @@ import (
 
 // Department 部门模型
 type Department struct {
-	ID          uint           `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name        string         `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
-	Code        string         `json:"code" gorm:"size:50;uniqueIndex;not null" validate:"required,max=50"`
-	Manager     string         `json:"manager" gorm:"size:100" validate:"max=100"`
-	Contact     string         `json:"contact" gorm:"size:100" validate:"max=100"`
-	Description string         `json:"description" gorm:"type:text"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                  uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name                string         `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Code                string         `json:"code" gorm:"size:50;uniqueIndex:idx_departments_code_active,where:deleted_at IS NULL;not null" validate:"required,max=50"`
+	ParentID            *uint          `json:"parent_id" gorm:"index"`                                          // 上级部门
+	DooTaskDepartmentID *uint          `json:"dootask_department_id" gorm:"column:dootask_department_id;index"` // 关联的 DooTask 部门
+	Manager             string         `json:"manager" gorm:"size:100" validate:"max=100"`
+	Contact             string         `json:"contact" gorm:"size:100" validate:"max=100"`
+	Description         string         `json:"description" gorm:"type:text"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// 关联关系
+	Parent        *Department    `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	Children      []Department   `json:"children,omitempty" gorm:"foreignKey:ParentID"`
 	Assets        []Asset        `json:"assets,omitempty" gorm:"foreignKey:DepartmentID"`
 	BorrowRecords []BorrowRecord `json:"borrow_records,omitempty" gorm:"foreignKey:DepartmentID"`
 }
@@ -52,4 +56,18 @@ func (d *Department) BeforeDelete(tx *gorm.DB) error {
 	}
 
 	return nil
+}
+
+// GetDepartmentDescendantIDs 获取部门及其所有子部门ID
+func GetDepartmentDescendantIDs(tx *gorm.DB, departmentID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Raw(`
+		WITH RECURSIVE sub(id) AS (
+			SELECT id FROM departments WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT d.id FROM departments d JOIN sub ON d.parent_id = sub.id WHERE d.deleted_at IS NULL
+		)
+		SELECT id FROM sub
+	`, departmentID).Scan(&ids).Error
+	return ids, err
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package models
+
+// DepreciationMethod 折旧方法枚举
+type DepreciationMethod string
+
+const (
+	DepreciationMethodStraightLine    DepreciationMethod = "straight_line"    // 直线法
+	DepreciationMethodDoubleDeclining DepreciationMethod = "double_declining" // 双倍余额递减法
+	DepreciationMethodSumOfYears      DepreciationMethod = "sum_of_years"     // 年数总和法
+)
+
+// IsValidDepreciationMethod 判断折旧方法是否有效
+func IsValidDepreciationMethod(method DepreciationMethod) bool {
+	switch method {
+	case DepreciationMethodStraightLine, DepreciationMethodDoubleDeclining, DepreciationMethodSumOfYears:
+		return true
+	}
+	return false
+}
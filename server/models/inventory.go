@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/datatypes"
@@ -27,18 +28,19 @@ const (
 
 // InventoryTask 盘点任务模型
 type InventoryTask struct {
-	ID          uint                    `json:"id" gorm:"primaryKey;autoIncrement"`
-	TaskName    string                  `json:"task_name" gorm:"size:200;not null" validate:"required,max=200"`
-	TaskType    InventoryTaskType       `json:"task_type" gorm:"size:50;default:full" validate:"oneof=full category department"`
-	ScopeFilter datatypes.JSON          `json:"scope_filter" gorm:"type:json"` // 盘点范围过滤条件
-	Status      InventoryTaskStatus     `json:"status" gorm:"size:20;default:pending" validate:"oneof=pending in_progress completed"`
-	StartDate   *time.Time              `json:"start_date"`
-	EndDate     *time.Time              `json:"end_date"`
-	CreatedBy   string                  `json:"created_by" gorm:"size:100" validate:"max=100"`
-	Notes       string                  `json:"notes" gorm:"type:text"`
-	CreatedAt   time.Time               `json:"created_at"`
-	UpdatedAt   time.Time               `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt          `json:"-" gorm:"index"`
+	ID           uint                `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskName     string              `json:"task_name" gorm:"size:200;not null" validate:"required,max=200"`
+	TaskType     InventoryTaskType   `json:"task_type" gorm:"size:50;default:full" validate:"oneof=full category department"`
+	ScopeFilter  datatypes.JSON      `json:"scope_filter" gorm:"type:json"` // 盘点范围过滤条件
+	Status       InventoryTaskStatus `json:"status" gorm:"size:20;default:pending" validate:"oneof=pending in_progress completed"`
+	StartDate    *time.Time          `json:"start_date"`
+	EndDate      *time.Time          `json:"end_date"`
+	CreatedBy    string              `json:"created_by" gorm:"size:100" validate:"max=100"`
+	DepartmentID *uint               `json:"department_id" gorm:"index"` // 任务所属部门
+	Notes        string              `json:"notes" gorm:"type:text"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt      `json:"-" gorm:"index"`
 
 	// 关联关系
 	Records []InventoryRecord `json:"records,omitempty" gorm:"foreignKey:TaskID"`
@@ -64,6 +66,46 @@ func (it *InventoryTask) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ScopeAssets 将资产查询限定为任务盘点范围内的资产
+func (it *InventoryTask) ScopeAssets(db *gorm.DB) *gorm.DB {
+	// 解析范围过滤条件
+	var scopeFilter InventoryScopeFilter
+	if len(it.ScopeFilter) > 0 {
+		json.Unmarshal(it.ScopeFilter, &scopeFilter)
+	}
+
+	// 根据任务类型和范围过滤条件构建查询
+	switch it.TaskType {
+	case InventoryTaskTypeCategory:
+		if len(scopeFilter.CategoryIDs) > 0 {
+			db = db.Where("category_id IN ?", scopeFilter.CategoryIDs)
+		}
+	case InventoryTaskTypeDepartment:
+		if len(scopeFilter.DepartmentIDs) > 0 {
+			db = db.Where("department_id IN ?", scopeFilter.DepartmentIDs)
+		}
+	}
+
+	// 部门盘点任务只包含所属部门及子部门的资产
+	if it.DepartmentID != nil && it.TaskType != InventoryTaskTypeDepartment {
+		if departmentIDs, err := GetDepartmentDescendantIDs(db.Session(&gorm.Session{NewDB: true}), *it.DepartmentID); err == nil {
+			db = db.Where("department_id IN ?", departmentIDs)
+		}
+	}
+
+	// 状态过滤
+	if len(scopeFilter.AssetStatuses) > 0 {
+		db = db.Where("status IN ?", scopeFilter.AssetStatuses)
+	}
+
+	// 位置过滤
+	if scopeFilter.LocationFilter != "" {
+		db = db.Where("location LIKE ?", "%"+scopeFilter.LocationFilter+"%")
+	}
+
+	return db
+}
+
 // InventoryResult 盘点结果枚举
 type InventoryResult string
 
@@ -85,6 +127,7 @@ type InventoryRecord struct {
 	Notes          string          `json:"notes" gorm:"type:text"`
 	CheckedAt      *time.Time      `json:"checked_at"`
 	CheckedBy      string          `json:"checked_by" gorm:"size:100" validate:"max=100"`
+	KitRecordID    *uint           `json:"kit_record_id" gorm:"index"` // 按套件盘点时指向父资产的盘点记录
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt  `json:"-" gorm:"index"`
@@ -92,6 +135,7 @@ type InventoryRecord struct {
 	// 关联关系
 	Task  InventoryTask `json:"task,omitempty" gorm:"foreignKey:TaskID"`
 	Asset Asset         `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+	Components []InventoryRecord `json:"components,omitempty" gorm:"foreignKey:KitRecordID"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LicenseType 许可证类型枚举
+type LicenseType string
+
+const (
+	LicenseTypeSaaS    LicenseType = "saas"    // SaaS 订阅
+	LicenseTypeDesktop LicenseType = "desktop" // 桌面软件
+)
+
+var (
+	// ErrNoSeatsAvailable 许可证席位已分配完
+	ErrNoSeatsAvailable = errors.New("许可证席位已分配完")
+	// ErrSeatAlreadyAssigned 同一人员或资产已占用该许可证席位
+	ErrSeatAlreadyAssigned = errors.New("该人员或资产已分配此许可证")
+)
+
+// License 软件许可证模型，按席位分配给人员或资产
+type License struct {
+	ID           uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Product      string         `json:"product" gorm:"size:200;not null;index"`    // 软件产品
+	Vendor       string         `json:"vendor" gorm:"size:200;index"`              // 厂商
+	Type         LicenseType    `json:"type" gorm:"size:20;default:desktop;index"` // 许可证类型
+	LicenseKey   string         `json:"license_key" gorm:"size:500"`               // 许可证密钥或订阅账号
+	SeatCount    int            `json:"seat_count" gorm:"not null"`                // 席位数
+	PurchaseDate *time.Time     `json:"purchase_date"`                             // 购买日期
+	ExpiryDate   *time.Time     `json:"expiry_date" gorm:"index"`                  // 到期日期，永久授权为空
+	Cost         *float64       `json:"cost" gorm:"type:decimal(12,2)"`            // 购买或续费费用
+	Notes        string         `json:"notes" gorm:"type:text"`
+	CreatedBy    string         `json:"created_by" gorm:"size:100"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 关联关系
+	Seats []LicenseSeat `json:"seats,omitempty" gorm:"foreignKey:LicenseID"`
+}
+
+// TableName 指定表名
+func (License) TableName() string {
+	return "licenses"
+}
+
+// IsExpired 许可证是否已过期，到期日当天仍视为有效
+func (l *License) IsExpired(now time.Time) bool {
+	days := l.DaysToExpiry(now)
+	return days != nil && *days < 0
+}
+
+// DaysToExpiry 距到期的天数，永久授权返回 nil，已过期为负数
+func (l *License) DaysToExpiry(now time.Time) *int {
+	if l.ExpiryDate == nil {
+		return nil
+	}
+	expiry := time.Date(l.ExpiryDate.Year(), l.ExpiryDate.Month(), l.ExpiryDate.Day(), 0, 0, 0, 0, now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := int(expiry.Sub(today).Hours() / 24)
+	return &days
+}
+
+// LicenseSeat 许可证席位分配记录，分配给人员或资产，回收后保留记录
+type LicenseSeat struct {
+	ID              uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	LicenseID       uint       `json:"license_id" gorm:"not null;index"`
+	AssigneeName    string     `json:"assignee_name" gorm:"size:100;index"` // 使用人
+	AssigneeContact string     `json:"assignee_contact" gorm:"size:100"`    // 使用人联系方式
+	AssetID         *uint      `json:"asset_id" gorm:"index"`               // 安装的资产
+	AssignedAt      time.Time  `json:"assigned_at"`
+	AssignedBy      string     `json:"assigned_by" gorm:"size:100"`
+	RevokedAt       *time.Time `json:"revoked_at" gorm:"index"`
+	RevokedBy       string     `json:"revoked_by" gorm:"size:100"`
+	Notes           string     `json:"notes" gorm:"type:text"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// 关联关系
+	License *License `json:"license,omitempty" gorm:"foreignKey:LicenseID"`
+	Asset   *Asset   `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+}
+
+// TableName 指定表名
+func (LicenseSeat) TableName() string {
+	return "license_seats"
+}
+
+// IsActive 席位是否仍在使用
+func (s *LicenseSeat) IsActive() bool {
+	return s.RevokedAt == nil
+}
+
+// ActiveLicenseSeats 未回收的席位
+func ActiveLicenseSeats(db *gorm.DB) *gorm.DB {
+	return db.Model(&LicenseSeat{}).Where("revoked_at IS NULL")
+}
+
+// CountActiveSeats 统计许可证已分配的席位数
+func CountActiveSeats(tx *gorm.DB, licenseID uint) (int64, error) {
+	var count int64
+	err := ActiveLicenseSeats(tx).Where("license_id = ?", licenseID).Count(&count).Error
+	return count, err
+}
+
+// AssignLicenseSeat 分配席位，席位已满时返回 ErrNoSeatsAvailable，同一人员或资产重复分配时返回 ErrSeatAlreadyAssigned，须在事务中调用
+func AssignLicenseSeat(tx *gorm.DB, license *License, seat *LicenseSeat) error {
+	duplicate := ActiveLicenseSeats(tx).Where("license_id = ?", license.ID)
+	if seat.AssetID != nil {
+		duplicate = duplicate.Where("asset_id = ?", *seat.AssetID)
+	} else {
+		duplicate = duplicate.Where("asset_id IS NULL AND assignee_name = ?", seat.AssigneeName)
+	}
+	var count int64
+	if err := duplicate.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSeatAlreadyAssigned
+	}
+
+	used, err := CountActiveSeats(tx, license.ID)
+	if err != nil {
+		return err
+	}
+	if used >= int64(license.SeatCount) {
+		return ErrNoSeatsAvailable
+	}
+
+	seat.LicenseID = license.ID
+	if seat.AssignedAt.IsZero() {
+		seat.AssignedAt = time.Now()
+	}
+	return tx.Create(seat).Error
+}
+
+// ExpiringLicenses 在指定天数内到期（含今天）的许可证
+func ExpiringLicenses(db *gorm.DB, now time.Time, days int) *gorm.DB {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return db.Model(&License{}).
+		Where("expiry_date IS NOT NULL AND expiry_date >= ? AND expiry_date < ?", today, today.AddDate(0, 0, days+1))
+}
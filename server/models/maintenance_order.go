@@ -0,0 +1,145 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// MaintenanceStatus 维修工单状态枚举
+type MaintenanceStatus string
+
+const (
+	MaintenanceStatusOpen   MaintenanceStatus = "open"   // 维修中
+	MaintenanceStatusClosed MaintenanceStatus = "closed" // 已关闭
+)
+
+// MaintenanceResult 维修结果枚举
+type MaintenanceResult string
+
+const (
+	MaintenanceResultRepaired     MaintenanceResult = "repaired"     // 已修复
+	MaintenanceResultUnrepairable MaintenanceResult = "unrepairable" // 无法修复
+	MaintenanceResultNoFault      MaintenanceResult = "no_fault"     // 未发现故障
+)
+
+// MaintenanceOrder 维修工单模型
+type MaintenanceOrder struct {
+	ID               uint              `json:"id" gorm:"primaryKey;autoIncrement"`
+	AssetID          uint              `json:"asset_id" gorm:"not null;index" validate:"required"`
+	FaultDescription string            `json:"fault_description" gorm:"type:text;not null" validate:"required"`
+	Vendor           string            `json:"vendor" gorm:"size:200" validate:"max=200"` // 维修商
+	Cost             *float64          `json:"cost" gorm:"type:decimal(12,2)"`            // 维修费用
+	StartDate        time.Time         `json:"start_date" gorm:"not null"`                // 送修日期
+	FinishDate       *time.Time        `json:"finish_date"`                               // 完成日期
+	Result           MaintenanceResult `json:"result" gorm:"size:20"`                     // 维修结果
+	ResultNotes      string            `json:"result_notes" gorm:"type:text"`             // 结果说明
+	Attachments      datatypes.JSON    `json:"attachments" gorm:"type:json"`              // 附件路径列表
+	Status           MaintenanceStatus `json:"status" gorm:"size:20;default:open;index" validate:"oneof=open closed"`
+	CreatedBy        string            `json:"created_by" gorm:"size:100"`
+	ClosedBy         string            `json:"closed_by" gorm:"size:100"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	// 关联关系
+	Asset Asset `json:"asset,omitempty" gorm:"foreignKey:AssetID"`
+}
+
+// TableName 指定表名
+func (MaintenanceOrder) TableName() string {
+	return "maintenance_orders"
+}
+
+// BeforeCreate 创建前钩子
+func (mo *MaintenanceOrder) BeforeCreate(tx *gorm.DB) error {
+	// 设置默认状态
+	if mo.Status == "" {
+		mo.Status = MaintenanceStatusOpen
+	}
+
+	// 设置默认送修时间
+	if mo.StartDate.IsZero() {
+		mo.StartDate = time.Now()
+	}
+
+	return nil
+}
+
+// AfterCreate 创建后钩子
+func (mo *MaintenanceOrder) AfterCreate(tx *gorm.DB) error {
+	// 开单即送修，资产进入维护中
+	if mo.Status != MaintenanceStatusOpen {
+		return nil
+	}
+	return ChangeAssetStatus(tx, mo.AssetID, AssetStatusMaintenance)
+}
+
+// IsOpen 工单是否未关闭
+func (mo *MaintenanceOrder) IsOpen() bool {
+	return mo.Status == MaintenanceStatusOpen
+}
+
+// Close 关闭工单，维护中的资产恢复为可用，须在事务中调用
+// 无法修复时资产保持维护中，不能再借用或预约，须通过资产处置流程报废
+func (mo *MaintenanceOrder) Close(tx *gorm.DB, updates map[string]interface{}) error {
+	updates["status"] = MaintenanceStatusClosed
+	if _, exists := updates["finish_date"]; !exists {
+		updates["finish_date"] = time.Now()
+	}
+	if err := tx.Model(mo).Updates(updates).Error; err != nil {
+		return err
+	}
+	if result, _ := updates["result"].(MaintenanceResult); result == MaintenanceResultUnrepairable {
+		return nil
+	}
+
+	var asset Asset
+	if err := tx.Select("id", "status").First(&asset, mo.AssetID).Error; err != nil {
+		return err
+	}
+	if asset.Status != AssetStatusMaintenance {
+		return nil
+	}
+	return ChangeAssetStatus(tx, mo.AssetID, AssetStatusAvailable)
+}
+
+// HasOpenMaintenance 判断资产是否存在未关闭的维修工单
+func HasOpenMaintenance(tx *gorm.DB, assetID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&MaintenanceOrder{}).
+		Where("asset_id = ? AND status = ?", assetID, MaintenanceStatusOpen).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func init() {
+	RegisterAssetStatusGuard(guardOpenMaintenance)
+}
+
+// guardOpenMaintenance 维护状态须与维修工单一致：
+// 进入维护中必须存在未关闭的维修工单，离开维护中前工单必须已关闭
+func guardOpenMaintenance(tx *gorm.DB, asset *Asset, to AssetStatus) (string, error) {
+	if to != AssetStatusMaintenance && asset.Status != AssetStatusMaintenance {
+		return "", nil
+	}
+
+	inMaintenance, err := HasOpenMaintenance(tx, asset.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if to == AssetStatusMaintenance {
+		if !inMaintenance {
+			return "没有未关闭的维修工单，请通过维修工单送修", nil
+		}
+		return "", nil
+	}
+	if inMaintenance {
+		return "存在未关闭的维修工单，请先关闭工单", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupMaintenanceTest 初始化内存数据库，创建一个已送修的资产
+func setupMaintenanceTest(t *testing.T) (*gorm.DB, *MaintenanceOrder) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("迁移数据库失败: %v", err)
+	}
+
+	category := Category{Name: "笔记本", Code: "LAPTOP"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("创建分类失败: %v", err)
+	}
+	asset := Asset{AssetNo: "LAPTOP-0001", Name: "笔记本电脑", CategoryID: category.ID}
+	if err := db.Create(&asset).Error; err != nil {
+		t.Fatalf("创建资产失败: %v", err)
+	}
+	order := MaintenanceOrder{AssetID: asset.ID, FaultDescription: "无法开机"}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("创建维修工单失败: %v", err)
+	}
+
+	return db, &order
+}
+
+func TestMaintenanceOrderClose(t *testing.T) {
+	tests := []struct {
+		result MaintenanceResult
+		want   AssetStatus
+	}{
+		{MaintenanceResultRepaired, AssetStatusAvailable},
+		{MaintenanceResultNoFault, AssetStatusAvailable},
+		// 无法修复的资产不能恢复为可用，保持维护中等待处置
+		{MaintenanceResultUnrepairable, AssetStatusMaintenance},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.result), func(t *testing.T) {
+			db, order := setupMaintenanceTest(t)
+
+			var asset Asset
+			if err := db.First(&asset, order.AssetID).Error; err != nil {
+				t.Fatalf("查询资产失败: %v", err)
+			}
+			if asset.Status != AssetStatusMaintenance {
+				t.Fatalf("送修后资产状态 = %q, want %q", asset.Status, AssetStatusMaintenance)
+			}
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return order.Close(tx, map[string]interface{}{"result": tt.result})
+			})
+			if err != nil {
+				t.Fatalf("关闭工单失败: %v", err)
+			}
+
+			if err := db.First(&asset, order.AssetID).Error; err != nil {
+				t.Fatalf("查询资产失败: %v", err)
+			}
+			if asset.Status != tt.want {
+				t.Errorf("关闭工单后资产状态 = %q, want %q", asset.Status, tt.want)
+			}
+			if err := db.First(order, order.ID).Error; err != nil {
+				t.Fatalf("查询维修工单失败: %v", err)
+			}
+			if order.Status != MaintenanceStatusClosed || order.Result != tt.result {
+				t.Errorf("工单 = %q/%q, want %q/%q", order.Status, order.Result, MaintenanceStatusClosed, tt.result)
+			}
+		})
+	}
+}
+
+func TestUnrepairableAssetCanBeScrapped(t *testing.T) {
+	db, order := setupMaintenanceTest(t)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return order.Close(tx, map[string]interface{}{"result": MaintenanceResultUnrepairable})
+	})
+	if err != nil {
+		t.Fatalf("关闭工单失败: %v", err)
+	}
+
+	// 未经处置审批不能报废，也不能借出
+	for _, to := range []AssetStatus{AssetStatusScrapped, AssetStatusBorrowed} {
+		if err := ChangeAssetStatus(db, order.AssetID, to); err == nil {
+			t.Errorf("无法修复的资产直接变更为 %q 应失败", to)
+		}
+	}
+
+	disposal := AssetDisposal{AssetID: order.AssetID, Reason: "无法修复", Method: DisposalMethodDestroy, Status: DisposalStatusApproved}
+	if err := db.Create(&disposal).Error; err != nil {
+		t.Fatalf("创建处置申请失败: %v", err)
+	}
+	if err := ChangeAssetStatus(db, order.AssetID, AssetStatusScrapped); err != nil {
+		t.Errorf("处置批准后报废失败: %v", err)
+	}
+}
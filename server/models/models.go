@@ -8,12 +8,31 @@ func AllModels() []interface{} {
 		&Category{},
 		&Department{},
 		&Asset{},
+		&AssetComponentLog{},
 		&BorrowRecord{},
+		&AssetTransfer{},
+		&AssetReservation{},
+		&MaintenanceOrder{},
+		&AssetDisposal{},
+		&AssetWriteOff{},
+		&Consumable{},
+		&ConsumableStock{},
+		&ConsumableTransaction{},
+		&License{},
+		&LicenseSeat{},
 		&InventoryTask{},
 		&InventoryRecord{},
 		&OperationLog{},
 		&SystemConfig{},
+		&AssetNoSequence{},
+		&SearchDocument{},
+		&Attachment{},
 		&ReportRecord{},
+		&BulkJob{},
+		&BulkJobError{},
+		&User{},
+		&APIKey{},
+		&UserSession{},
 	}
 }
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/datatypes"
@@ -63,4 +64,16 @@ func (SystemConfig) TableName() string {
 func (sc *SystemConfig) BeforeCreate(tx *gorm.DB) error {
 	// 系统配置不需要特殊处理
 	return nil
-}
\ No newline at end of file
+}
+
+// GetSystemConfigValue 读取系统配置值，不存在时返回空字符串
+func GetSystemConfigValue(db *gorm.DB, key string) (string, error) {
+	var config SystemConfig
+	if err := db.Where("config_key = ?", key).First(&config).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(config.ConfigValue), nil
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 全文检索文档类型
+const (
+	SearchKindAsset    = "asset"    // 资产
+	SearchKindBorrow   = "borrow"   // 借用记录
+	SearchKindCategory = "category" // 分类
+)
+
+// SearchKinds 全部检索文档类型
+var SearchKinds = []string{SearchKindAsset, SearchKindBorrow, SearchKindCategory}
+
+// SearchDocument 全文检索文档，由资产、借用记录和分类的模型钩子维护
+type SearchDocument struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind      string    `json:"kind" gorm:"size:20;not null;uniqueIndex:idx_search_documents_ref"`
+	RefID     uint      `json:"ref_id" gorm:"not null;uniqueIndex:idx_search_documents_ref"`
+	Title     string    `json:"title" gorm:"size:300"`    // 标题，如资产名称
+	Subtitle  string    `json:"subtitle" gorm:"size:300"` // 副标题，如资产编号
+	Content   string    `json:"content" gorm:"type:text"` // 其他可检索内容，每个字段一行
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SearchDocument) TableName() string {
+	return "search_documents"
+}
+
+// SyncSearchDocuments 按来源记录重建检索文档，来源记录不存在（含已删除）时移除文档
+func SyncSearchDocuments(tx *gorm.DB, kind string, refIDs ...uint) error {
+	for _, refID := range refIDs {
+		if refID == 0 {
+			continue
+		}
+		document, err := buildSearchDocument(tx, kind, refID)
+		if err != nil {
+			return err
+		}
+		if document == nil {
+			if err := RemoveSearchDocument(tx, kind, refID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "kind"}, {Name: "ref_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "subtitle", "content", "updated_at"}),
+		}).Create(document).Error; err != nil {
+			return err
+		}
+
+		// 借用记录的副标题引用资产编号和名称，随资产一起更新
+		if kind == SearchKindAsset {
+			subtitle := strings.TrimSpace(document.Subtitle + " " + document.Title)
+			if err := tx.Model(&SearchDocument{}).
+				Where("kind = ? AND subtitle <> ? AND ref_id IN (SELECT id FROM borrow_records WHERE asset_id = ?)", SearchKindBorrow, subtitle, refID).
+				Update("subtitle", subtitle).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveSearchDocument 移除检索文档
+func RemoveSearchDocument(tx *gorm.DB, kind string, refID uint) error {
+	if refID == 0 {
+		return nil
+	}
+	return tx.Where("kind = ? AND ref_id = ?", kind, refID).Delete(&SearchDocument{}).Error
+}
+
+// RebuildSearchDocuments 清空并按来源数据重建全部检索文档
+func RebuildSearchDocuments(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&SearchDocument{}).Error; err != nil {
+			return err
+		}
+
+		sources := map[string]interface{}{
+			SearchKindAsset:    &Asset{},
+			SearchKindBorrow:   &BorrowRecord{},
+			SearchKindCategory: &Category{},
+		}
+		for _, kind := range SearchKinds {
+			var ids []uint
+			if err := tx.Model(sources[kind]).Order("id").Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if err := SyncSearchDocuments(tx, kind, ids...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// buildSearchDocument 读取来源记录生成检索文档，记录不存在时返回nil
+func buildSearchDocument(tx *gorm.DB, kind string, refID uint) (*SearchDocument, error) {
+	document := &SearchDocument{Kind: kind, RefID: refID, UpdatedAt: time.Now()}
+	var err error
+	switch kind {
+	case SearchKindAsset:
+		var asset Asset
+		if err = tx.First(&asset, refID).Error; err == nil {
+			document.Title = asset.Name
+			document.Subtitle = asset.AssetNo
+			document.Content = joinSearchContent(append([]string{
+				asset.Brand, asset.Model, asset.SerialNumber, asset.Supplier,
+				asset.Location, asset.ResponsiblePerson, asset.Description,
+			}, searchAttributeValues(asset.CustomAttributes)...)...)
+		}
+	case SearchKindBorrow:
+		var record BorrowRecord
+		if err = tx.Preload("Asset").First(&record, refID).Error; err == nil {
+			document.Title = record.BorrowerName
+			document.Subtitle = strings.TrimSpace(record.Asset.AssetNo + " " + record.Asset.Name)
+			document.Content = joinSearchContent(record.BorrowerContact, record.Purpose, record.Notes)
+		}
+	case SearchKindCategory:
+		var category Category
+		if err = tx.First(&category, refID).Error; err == nil {
+			document.Title = category.Name
+			document.Subtitle = category.Code
+			var template CategoryAttributes
+			var labels []string
+			if len(category.Attributes) > 0 && json.Unmarshal(category.Attributes, &template) == nil {
+				for _, field := range template.Fields {
+					labels = append(labels, field.Label, field.Name)
+				}
+			}
+			document.Content = joinSearchContent(append([]string{category.Description}, labels...)...)
+		}
+	default:
+		return nil, fmt.Errorf("未知的检索文档类型: %s", kind)
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// searchAttributeValues 获取自定义属性中的标量值，按字段名排序
+func searchAttributeValues(raw []byte) []string {
+	var values map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &values) != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		switch value := values[name].(type) {
+		case string:
+			result = append(result, value)
+		case float64, bool:
+			result = append(result, fmt.Sprint(value))
+		}
+	}
+	return result
+}
+
+// joinSearchContent 拼接非空字段，每个字段一行
+func joinSearchContent(values ...string) string {
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			lines = append(lines, value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// User 用户模型
+type User struct {
+	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username      string         `json:"username" gorm:"size:100;uniqueIndex;not null" validate:"required,max=100"`
+	PasswordHash  string         `json:"-" gorm:"size:255;not null"`
+	Name          string         `json:"name" gorm:"size:100" validate:"max=100"`
+	Email         string         `json:"email" gorm:"size:200" validate:"omitempty,email,max=200"`
+	Phone         string         `json:"phone" gorm:"size:50" validate:"max=50"`
+	Roles         string         `json:"roles" gorm:"size:200"` // 角色列表，逗号分隔
+	DepartmentID  *uint          `json:"department_id" gorm:"index"`
+	DooTaskUserID *uint          `json:"dootask_user_id" gorm:"column:dootask_user_id;index"` // 关联的 DooTask 用户
+	IsActive      bool           `json:"is_active" gorm:"default:true"`
+	LastLoginAt   *time.Time     `json:"last_login_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 关联关系
+	Department *Department `json:"department,omitempty" gorm:"foreignKey:DepartmentID"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "users"
+}
+
+// SetPassword 设置密码（bcrypt加密）
+func (u *User) SetPassword(password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword 校验密码
+func (u *User) CheckPassword(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// GetRoles 获取角色列表
+func (u *User) GetRoles() []string {
+	var roles []string
+	for _, role := range strings.Split(u.Roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetDisplayName 获取显示名称
+func (u *User) GetDisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Username
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// UserSession 用户会话模型，对应 user_sessions 表
+type UserSession struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`
+	SessionToken string    `json:"-" gorm:"size:100;uniqueIndex;not null"` // 会话标识，写入令牌的 sid 声明
+	IPAddress    string    `json:"ip_address" gorm:"size:45"`
+	UserAgent    string    `json:"user_agent" gorm:"type:text"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"` // 空闲过期时间，每次访问顺延
+	LastActiveAt time.Time `json:"last_active_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsExpired 判断会话是否已过期
+func (s *UserSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -0,0 +1,265 @@
+package attributes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/datatypes"
+)
+
+// DateLayout 日期类型属性的存储格式
+const DateLayout = "2006-01-02"
+
+// dateLayouts 日期类型属性可接受的输入格式
+var dateLayouts = []string{DateLayout, time.RFC3339, "2006-01-02 15:04:05", "2006/01/02"}
+
+// fieldNamePattern 字段名只允许字母、数字、下划线和连字符，便于安全地拼接到 JSON 路径中
+var fieldNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors 字段校验错误列表
+type ValidationErrors []FieldError
+
+// Error 实现 error 接口
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// IsValidFieldName 判断字段名是否合法
+func IsValidFieldName(name string) bool {
+	return fieldNamePattern.MatchString(name)
+}
+
+// JSONPath 获取字段在 custom_attributes 中的 JSON 路径，调用前须确认字段名合法
+func JSONPath(name string) string {
+	return `$."` + name + `"`
+}
+
+// ParseTemplate 解析分类属性模板，未配置模板时返回空模板
+func ParseTemplate(raw datatypes.JSON) (*models.CategoryAttributes, error) {
+	template := &models.CategoryAttributes{}
+	if len(raw) == 0 || string(raw) == "null" {
+		return template, nil
+	}
+	if err := json.Unmarshal(raw, template); err != nil {
+		return nil, fmt.Errorf("分类属性模板格式错误: %v", err)
+	}
+	return template, nil
+}
+
+// CheckTemplate 校验分类属性模板本身：字段名、类型、选项和默认值
+func CheckTemplate(template *models.CategoryAttributes) error {
+	var errs ValidationErrors
+
+	switch template.UnknownFields {
+	case "", models.UnknownFieldsAllow, models.UnknownFieldsStrip, models.UnknownFieldsReject:
+	default:
+		errs = append(errs, FieldError{Field: "unknown_fields", Message: "未定义字段处理策略只能为 allow、strip 或 reject"})
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range template.Fields {
+		if !IsValidFieldName(field.Name) {
+			errs = append(errs, FieldError{Field: field.Name, Message: "字段名只能包含字母、数字、下划线和连字符"})
+			continue
+		}
+		if seen[field.Name] {
+			errs = append(errs, FieldError{Field: field.Name, Message: "字段名重复"})
+			continue
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case models.FieldTypeText, models.FieldTypeNumber, models.FieldTypeDate, models.FieldTypeBoolean:
+		case models.FieldTypeSelect:
+			if len(field.Options) == 0 {
+				errs = append(errs, FieldError{Field: field.Name, Message: "选择类型字段必须配置选项"})
+				continue
+			}
+		default:
+			errs = append(errs, FieldError{Field: field.Name, Message: fmt.Sprintf("不支持的字段类型: %s", field.Type)})
+			continue
+		}
+
+		if !isEmpty(field.DefaultValue) {
+			if _, err := coerce(field, field.DefaultValue); err != nil {
+				errs = append(errs, FieldError{Field: field.Name, Message: "默认值无效: " + err.Error()})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate 按分类模板校验资产自定义属性，返回规范化后的属性：
+// 缺失的字段填充默认值，值按字段类型转换，未定义字段按模板策略保留、丢弃或报错
+func Validate(template *models.CategoryAttributes, values map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(values))
+	var errs ValidationErrors
+
+	defined := make(map[string]bool, len(template.Fields))
+	for _, field := range template.Fields {
+		defined[field.Name] = true
+
+		value, exists := values[field.Name]
+		if !exists || isEmpty(value) {
+			value = field.DefaultValue
+		}
+		if isEmpty(value) {
+			if field.Required {
+				errs = append(errs, FieldError{Field: field.Name, Message: fieldLabel(field) + "为必填项"})
+			}
+			continue
+		}
+
+		coerced, err := coerce(field, value)
+		if err != nil {
+			errs = append(errs, FieldError{Field: field.Name, Message: fieldLabel(field) + err.Error()})
+			continue
+		}
+		result[field.Name] = coerced
+	}
+
+	for name, value := range values {
+		if defined[name] {
+			continue
+		}
+		switch template.UnknownFields {
+		case models.UnknownFieldsStrip:
+		case models.UnknownFieldsReject:
+			errs = append(errs, FieldError{Field: name, Message: "分类模板中未定义该字段"})
+		default:
+			result[name] = value
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// Marshal 校验自定义属性并序列化为 JSON，没有任何属性时返回nil
+func Marshal(template *models.CategoryAttributes, values map[string]interface{}) (datatypes.JSON, error) {
+	normalized, err := Validate(template, values)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(data), nil
+}
+
+// coerce 按字段类型转换属性值
+func coerce(field models.CategoryField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case models.FieldTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case string:
+			if number, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return number, nil
+			}
+		}
+		return nil, fmt.Errorf("必须为数字")
+	case models.FieldTypeDate:
+		if v, ok := value.(string); ok {
+			for _, layout := range dateLayouts {
+				if parsed, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+					return parsed.Format(DateLayout), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("必须为日期，格式为 YYYY-MM-DD")
+	case models.FieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case float64:
+			if v == 0 || v == 1 {
+				return v == 1, nil
+			}
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "true", "1", "yes", "是":
+				return true, nil
+			case "false", "0", "no", "否":
+				return false, nil
+			}
+		}
+		return nil, fmt.Errorf("必须为布尔值")
+	case models.FieldTypeSelect:
+		text := toText(value)
+		for _, option := range field.Options {
+			if text == option {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("必须为以下选项之一: %s", strings.Join(field.Options, ", "))
+	default:
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("必须为文本")
+		}
+		return toText(value), nil
+	}
+}
+
+// toText 将标量值转换为文本
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// isEmpty 判断属性值是否为空（nil 或空白字符串）
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if text, ok := value.(string); ok {
+		return strings.TrimSpace(text) == ""
+	}
+	return false
+}
+
+// fieldLabel 获取字段显示名称
+func fieldLabel(field models.CategoryField) string {
+	if field.Label != "" {
+		return field.Label
+	}
+	return field.Name
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"asset-management-system/server/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 认证方式
+const (
+	AuthTypeJWT     = "jwt"     // JWT令牌
+	AuthTypeDooTask = "dootask" // DooTask单点登录
+	AuthTypeAPIKey  = "api_key" // API密钥
+)
+
+// 上下文键
+const (
+	ContextKeyIdentity     = "identity"
+	ContextKeyUserID       = "user_id"
+	ContextKeyUserName     = "user_name"
+	ContextKeyUserRoles    = "user_roles"
+	ContextKeyDepartmentID = "department_id"
+	ContextKeyOperator     = "operator"
+)
+
+// Identity 当前请求的身份信息
+type Identity struct {
+	UserID       uint     `json:"user_id"`
+	UserName     string   `json:"user_name"`
+	Roles        []string `json:"roles"`
+	DepartmentID *uint    `json:"department_id"`
+	AuthType     string   `json:"auth_type"`
+	SessionID    string   `json:"session_id,omitempty"` // 服务端会话标识（JWT）
+	Scopes       []string `json:"scopes,omitempty"`     // API密钥授权范围
+}
+
+// NewIdentityFromUser 根据用户构造身份信息
+func NewIdentityFromUser(user *models.User) *Identity {
+	return &Identity{
+		UserID:       user.ID,
+		UserName:     user.GetDisplayName(),
+		Roles:        user.GetRoles(),
+		DepartmentID: user.DepartmentID,
+		AuthType:     AuthTypeJWT,
+	}
+}
+
+// HasRole 判断是否拥有任一角色
+func (i *Identity) HasRole(roles ...string) bool {
+	for _, owned := range i.Roles {
+		for _, role := range roles {
+			if owned == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetIdentity 将身份信息写入上下文
+func SetIdentity(c *gin.Context, identity *Identity) {
+	c.Set(ContextKeyIdentity, identity)
+	c.Set(ContextKeyUserID, identity.UserID)
+	c.Set(ContextKeyUserName, identity.UserName)
+	c.Set(ContextKeyUserRoles, identity.Roles)
+	c.Set(ContextKeyOperator, identity.UserName)
+	if identity.DepartmentID != nil {
+		c.Set(ContextKeyDepartmentID, *identity.DepartmentID)
+	}
+	c.Set("is_authenticated", true)
+}
+
+// GetIdentity 获取当前请求的身份信息，未认证时返回nil
+func GetIdentity(c *gin.Context) *Identity {
+	if value, exists := c.Get(ContextKeyIdentity); exists {
+		if identity, ok := value.(*Identity); ok {
+			return identity
+		}
+	}
+	return nil
+}
+
+// GetOperator 获取当前操作者名称
+func GetOperator(c *gin.Context) string {
+	if operator := c.GetString(ContextKeyOperator); operator != "" {
+		return operator
+	}
+	return "system"
+}
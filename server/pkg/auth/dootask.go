@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDooTaskTokenInvalid = errors.New("无效的DooTask令牌")
+	ErrDooTaskUnavailable  = errors.New("DooTask用户服务不可用")
+	ErrUserDisabled        = errors.New("用户已被禁用")
+)
+
+// dooTaskHTTPClient 请求DooTask用户信息的客户端
+var dooTaskHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// dooTaskCache DooTask令牌校验结果缓存：sha256(token) -> dooTaskCacheEntry
+var dooTaskCache sync.Map
+
+// dooTaskCacheEntry 缓存条目
+type dooTaskCacheEntry struct {
+	identity  *Identity
+	expiresAt time.Time
+}
+
+// DooTaskUser DooTask用户信息
+type DooTaskUser struct {
+	UserID     uint     `json:"userid"`
+	Email      string   `json:"email"`
+	Nickname   string   `json:"nickname"`
+	Department []uint   `json:"department"`
+	Identity   []string `json:"identity"`
+}
+
+// dooTaskResponse DooTask接口响应，ret为1表示成功
+type dooTaskResponse struct {
+	Ret  int         `json:"ret"`
+	Msg  string      `json:"msg"`
+	Data DooTaskUser `json:"data"`
+}
+
+// AuthenticateDooTask 校验DooTask令牌并映射为本地用户身份
+// 首次登录自动创建本地用户，之后同步姓名、邮箱和部门，角色以本地配置为准
+func AuthenticateDooTask(token string) (*Identity, error) {
+	cacheKey := hashDooTaskToken(token)
+	if value, exists := dooTaskCache.Load(cacheKey); exists {
+		entry := value.(*dooTaskCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.identity, nil
+		}
+		dooTaskCache.Delete(cacheKey)
+	}
+
+	dooTaskUser, err := fetchDooTaskUser(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := syncDooTaskUser(dooTaskUser)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserDisabled
+	}
+
+	identity := NewIdentityFromUser(user)
+	identity.AuthType = AuthTypeDooTask
+
+	if ttl := time.Duration(global.AppConfig.DooTaskCacheSeconds) * time.Second; ttl > 0 {
+		dooTaskCache.Store(cacheKey, &dooTaskCacheEntry{identity: identity, expiresAt: time.Now().Add(ttl)})
+	}
+
+	return identity, nil
+}
+
+// forgetDooTaskUser 清除用户的DooTask身份缓存，使禁用、强制下线立即生效
+func forgetDooTaskUser(userID uint) {
+	dooTaskCache.Range(func(key, value interface{}) bool {
+		if entry := value.(*dooTaskCacheEntry); entry.identity.UserID == userID {
+			dooTaskCache.Delete(key)
+		}
+		return true
+	})
+}
+
+// fetchDooTaskUser 调用DooTask用户信息接口
+func fetchDooTaskUser(token string) (*DooTaskUser, error) {
+	req, err := http.NewRequest(http.MethodGet, global.AppConfig.DooTaskUserInfoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDooTaskUnavailable, err)
+	}
+	req.Header.Set("token", token)
+
+	resp, err := dooTaskHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDooTaskUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrDooTaskTokenInvalid
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %d", ErrDooTaskUnavailable, resp.StatusCode)
+	}
+
+	var result dooTaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDooTaskUnavailable, err)
+	}
+	if result.Ret != 1 || result.Data.UserID == 0 {
+		return nil, ErrDooTaskTokenInvalid
+	}
+
+	return &result.Data, nil
+}
+
+// syncDooTaskUser 按DooTask用户ID同步本地用户
+func syncDooTaskUser(dooTaskUser *DooTaskUser) (*models.User, error) {
+	departmentID, err := resolveDooTaskDepartment(dooTaskUser.Department)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(dooTaskUser.Nickname)
+	if name == "" {
+		name = dooTaskUser.Email
+	}
+
+	var user models.User
+	err = global.DB.Where("dootask_user_id = ?", dooTaskUser.UserID).First(&user).Error
+	if err == gorm.ErrRecordNotFound {
+		dooTaskUserID := dooTaskUser.UserID
+		now := time.Now()
+		user = models.User{
+			Username:      fmt.Sprintf("dootask_%d", dooTaskUser.UserID),
+			Name:          name,
+			Email:         dooTaskUser.Email,
+			Roles:         dooTaskDefaultRole(dooTaskUser),
+			DepartmentID:  departmentID,
+			DooTaskUserID: &dooTaskUserID,
+			IsActive:      true,
+			LastLoginAt:   &now,
+		}
+		// 单点登录用户不使用本地密码，设置随机密码防止被直接登录
+		if err := user.SetPassword(randomPassword()); err != nil {
+			return nil, fmt.Errorf("设置密码失败: %v", err)
+		}
+		if err := global.DB.Create(&user).Error; err != nil {
+			return nil, fmt.Errorf("创建DooTask用户失败: %v", err)
+		}
+		return &user, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询DooTask用户失败: %v", err)
+	}
+
+	updates := map[string]interface{}{
+		"name":          name,
+		"email":         dooTaskUser.Email,
+		"department_id": departmentID,
+		"last_login_at": time.Now(),
+	}
+	if err := global.DB.Model(&user).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("同步DooTask用户失败: %v", err)
+	}
+	user.Name = name
+	user.Email = dooTaskUser.Email
+	user.DepartmentID = departmentID
+
+	return &user, nil
+}
+
+// resolveDooTaskDepartment 将DooTask部门映射为本地部门，取第一个已关联的部门
+func resolveDooTaskDepartment(dooTaskDepartmentIDs []uint) (*uint, error) {
+	if len(dooTaskDepartmentIDs) == 0 {
+		return nil, nil
+	}
+
+	var departments []models.Department
+	if err := global.DB.Where("dootask_department_id IN ?", dooTaskDepartmentIDs).Find(&departments).Error; err != nil {
+		return nil, fmt.Errorf("查询DooTask部门映射失败: %v", err)
+	}
+
+	for _, dooTaskDepartmentID := range dooTaskDepartmentIDs {
+		for _, department := range departments {
+			if department.DooTaskDepartmentID != nil && *department.DooTaskDepartmentID == dooTaskDepartmentID {
+				departmentID := department.ID
+				return &departmentID, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// dooTaskDefaultRole 新用户的默认角色，DooTask管理员映射为系统管理员
+func dooTaskDefaultRole(dooTaskUser *DooTaskUser) string {
+	for _, identity := range dooTaskUser.Identity {
+		if identity == "admin" {
+			return RoleAdmin
+		}
+	}
+	if IsValidRole(global.AppConfig.DooTaskDefaultRole) {
+		return global.AppConfig.DooTaskDefaultRole
+	}
+	return RoleViewer
+}
+
+// hashDooTaskToken 计算令牌摘要，避免在内存中保存明文令牌
+func hashDooTaskToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomPassword 生成随机密码
+func randomPassword() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return hashDooTaskToken(time.Now().String())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dooTaskStub 模拟DooTask用户信息接口，按令牌返回用户
+type dooTaskStub struct {
+	server *httptest.Server
+	users  map[string]DooTaskUser
+	hits   atomic.Int32
+}
+
+func newDooTaskStub(t *testing.T, users map[string]DooTaskUser) *dooTaskStub {
+	t.Helper()
+	stub := &dooTaskStub{users: users}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.hits.Add(1)
+		user, exists := stub.users[r.Header.Get("token")]
+		if !exists {
+			json.NewEncoder(w).Encode(dooTaskResponse{Ret: 0, Msg: "请登录后继续"})
+			return
+		}
+		json.NewEncoder(w).Encode(dooTaskResponse{Ret: 1, Data: user})
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+// setupDooTaskTest 初始化内存数据库和DooTask配置
+func setupDooTaskTest(t *testing.T, users map[string]DooTaskUser) *dooTaskStub {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	// 内存数据库每个连接独立，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Department{}, &models.User{}); err != nil {
+		t.Fatalf("迁移数据库失败: %v", err)
+	}
+
+	stub := newDooTaskStub(t, users)
+
+	previousDB, previousConfig := global.DB, global.AppConfig
+	global.DB = db
+	global.AppConfig = &global.Config{
+		DooTaskUserInfoURL:  stub.server.URL,
+		DooTaskCacheSeconds: 300,
+		DooTaskDefaultRole:  RoleViewer,
+	}
+	t.Cleanup(func() {
+		global.DB, global.AppConfig = previousDB, previousConfig
+		dooTaskCache.Range(func(key, _ interface{}) bool {
+			dooTaskCache.Delete(key)
+			return true
+		})
+		sqlDB.Close()
+	})
+
+	return stub
+}
+
+func TestAuthenticateDooTaskMapsUser(t *testing.T) {
+	setupDooTaskTest(t, map[string]DooTaskUser{
+		"admin-token":  {UserID: 1, Email: "boss@example.com", Nickname: "老板", Department: []uint{99, 7}, Identity: []string{"admin"}},
+		"member-token": {UserID: 2, Email: "member@example.com"},
+	})
+
+	dooTaskDepartmentID := uint(7)
+	department := models.Department{Name: "研发部", Code: "RD", DooTaskDepartmentID: &dooTaskDepartmentID}
+	if err := global.DB.Create(&department).Error; err != nil {
+		t.Fatalf("创建部门失败: %v", err)
+	}
+
+	identity, err := AuthenticateDooTask("admin-token")
+	if err != nil {
+		t.Fatalf("校验令牌失败: %v", err)
+	}
+	if identity.AuthType != AuthTypeDooTask {
+		t.Errorf("AuthType = %q, want %q", identity.AuthType, AuthTypeDooTask)
+	}
+	if !identity.HasRole(RoleAdmin) {
+		t.Errorf("DooTask管理员应映射为系统管理员, roles = %v", identity.Roles)
+	}
+	if identity.DepartmentID == nil || *identity.DepartmentID != department.ID {
+		t.Errorf("DepartmentID = %v, want %d", identity.DepartmentID, department.ID)
+	}
+
+	var user models.User
+	if err := global.DB.Where("dootask_user_id = ?", 1).First(&user).Error; err != nil {
+		t.Fatalf("未创建本地用户: %v", err)
+	}
+	if user.Username != "dootask_1" || user.Name != "老板" {
+		t.Errorf("本地用户 = %q/%q, want dootask_1/老板", user.Username, user.Name)
+	}
+
+	// 无昵称时以邮箱作为姓名，角色取默认角色
+	identity, err = AuthenticateDooTask("member-token")
+	if err != nil {
+		t.Fatalf("校验令牌失败: %v", err)
+	}
+	if identity.UserName != "member@example.com" {
+		t.Errorf("UserName = %q, want member@example.com", identity.UserName)
+	}
+	if !identity.HasRole(RoleViewer) || identity.HasRole(RoleAdmin) {
+		t.Errorf("roles = %v, want [%s]", identity.Roles, RoleViewer)
+	}
+
+	if _, err := AuthenticateDooTask("unknown-token"); !errors.Is(err, ErrDooTaskTokenInvalid) {
+		t.Errorf("无效令牌 err = %v, want %v", err, ErrDooTaskTokenInvalid)
+	}
+}
+
+func TestAuthenticateDooTaskCacheExpiry(t *testing.T) {
+	stub := setupDooTaskTest(t, map[string]DooTaskUser{
+		"token": {UserID: 1, Nickname: "张三"},
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := AuthenticateDooTask("token"); err != nil {
+			t.Fatalf("校验令牌失败: %v", err)
+		}
+	}
+	if hits := stub.hits.Load(); hits != 1 {
+		t.Fatalf("缓存有效期内请求DooTask %d 次, want 1", hits)
+	}
+
+	// 令缓存过期后应重新请求DooTask
+	value, exists := dooTaskCache.Load(hashDooTaskToken("token"))
+	if !exists {
+		t.Fatal("校验结果未缓存")
+	}
+	value.(*dooTaskCacheEntry).expiresAt = time.Now().Add(-time.Second)
+
+	if _, err := AuthenticateDooTask("token"); err != nil {
+		t.Fatalf("校验令牌失败: %v", err)
+	}
+	if hits := stub.hits.Load(); hits != 2 {
+		t.Errorf("缓存过期后请求DooTask %d 次, want 2", hits)
+	}
+
+	// 缓存时长为0时不缓存
+	global.AppConfig.DooTaskCacheSeconds = 0
+	dooTaskCache.Delete(hashDooTaskToken("token"))
+	for i := 0; i < 2; i++ {
+		if _, err := AuthenticateDooTask("token"); err != nil {
+			t.Fatalf("校验令牌失败: %v", err)
+		}
+	}
+	if hits := stub.hits.Load(); hits != 4 {
+		t.Errorf("禁用缓存后请求DooTask %d 次, want 4", hits)
+	}
+}
+
+func TestAuthenticateDooTaskDisabledUser(t *testing.T) {
+	setupDooTaskTest(t, map[string]DooTaskUser{
+		"token": {UserID: 1, Nickname: "张三"},
+	})
+
+	identity, err := AuthenticateDooTask("token")
+	if err != nil {
+		t.Fatalf("校验令牌失败: %v", err)
+	}
+
+	if err := global.DB.Model(&models.User{}).Where("id = ?", identity.UserID).Update("is_active", false).Error; err != nil {
+		t.Fatalf("禁用用户失败: %v", err)
+	}
+
+	// 禁用后清除缓存，下次校验立即拒绝
+	forgetDooTaskUser(identity.UserID)
+	if _, exists := dooTaskCache.Load(hashDooTaskToken("token")); exists {
+		t.Fatal("清除缓存后仍存在缓存条目")
+	}
+	if _, err := AuthenticateDooTask("token"); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("禁用用户 err = %v, want %v", err, ErrUserDisabled)
+	}
+	if _, exists := dooTaskCache.Load(hashDooTaskToken("token")); exists {
+		t.Error("禁用用户的校验结果不应缓存")
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"asset-management-system/server/global"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenType 令牌类型
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"  // 访问令牌
+	TokenTypeRefresh TokenType = "refresh" // 刷新令牌
+)
+
+var (
+	ErrTokenInvalid   = errors.New("无效的访问令牌")
+	ErrTokenExpired   = errors.New("访问令牌已过期")
+	ErrTokenTypeWrong = errors.New("令牌类型不正确")
+)
+
+// Claims JWT声明
+type Claims struct {
+	UserID       uint      `json:"uid"`
+	UserName     string    `json:"name"`
+	Roles        []string  `json:"roles"`
+	DepartmentID *uint     `json:"dept_id,omitempty"`
+	SessionID    string    `json:"sid,omitempty"`
+	TokenType    TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken 生成指定类型的令牌
+func GenerateToken(identity *Identity, tokenType TokenType) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(tokenLifetime(tokenType))
+
+	claims := Claims{
+		UserID:       identity.UserID,
+		UserName:     identity.UserName,
+		Roles:        identity.Roles,
+		DepartmentID: identity.DepartmentID,
+		SessionID:    identity.SessionID,
+		TokenType:    tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    global.AppConfig.JWTIssuer,
+			Subject:   strconv.FormatUint(uint64(identity.UserID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(global.AppConfig.JWTSecret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("签名令牌失败: %v", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// ParseToken 解析并校验令牌（签名算法、签发者、有效期、令牌类型）
+func ParseToken(tokenString string, tokenType TokenType) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(global.AppConfig.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(global.AppConfig.JWTIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, ErrTokenTypeWrong
+	}
+
+	return claims, nil
+}
+
+// tokenLifetime 获取令牌有效期
+func tokenLifetime(tokenType TokenType) time.Duration {
+	if tokenType == TokenTypeRefresh {
+		return time.Duration(global.AppConfig.JWTRefreshExpireHours) * time.Hour
+	}
+	return time.Duration(global.AppConfig.JWTExpireHours) * time.Hour
+}
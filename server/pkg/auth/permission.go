@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// 角色
+const (
+	RoleAdmin             = "admin"              // 系统管理员
+	RoleAssetManager      = "asset_manager"      // 资产管理员
+	RoleDepartmentManager = "department_manager" // 部门负责人
+	RoleViewer            = "viewer"             // 只读用户
+)
+
+// 操作
+const (
+	ActionRead   = "read"   // 查看
+	ActionCreate = "create" // 新增
+	ActionUpdate = "update" // 修改
+	ActionDelete = "delete" // 删除
+	ActionExport = "export" // 导出
+	ActionImport = "import" // 导入
+)
+
+// ValidRoles 所有可用角色
+var ValidRoles = []string{RoleAdmin, RoleAssetManager, RoleDepartmentManager, RoleViewer}
+
+// allActions 全部操作
+var allActions = []string{ActionRead, ActionCreate, ActionUpdate, ActionDelete, ActionExport, ActionImport}
+
+// selfServiceResources 所有已登录用户都可访问的资源
+var selfServiceResources = []string{"auth", "test"}
+
+// PermissionMatrix 权限矩阵：路由分组 -> 角色 -> 允许的操作
+// 管理员拥有全部权限，未在矩阵中出现的路由分组仅管理员可访问
+var PermissionMatrix = map[string]map[string][]string{
+	"assets": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionUpdate, ActionExport},
+		RoleViewer:            {ActionRead},
+	},
+	"attachments": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionDelete, ActionExport},
+		RoleViewer:            {ActionRead, ActionExport},
+	},
+	"categories": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"departments": {
+		RoleAssetManager:      {ActionRead},
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"borrow": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionUpdate},
+		RoleViewer:            {ActionRead},
+	},
+	"jobs": {
+		RoleAssetManager:      {ActionRead, ActionUpdate},
+		RoleDepartmentManager: {ActionRead, ActionUpdate},
+	},
+	"reservations": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionUpdate},
+		RoleViewer:            {ActionRead},
+	},
+	"transfers": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate},
+		RoleViewer:            {ActionRead},
+	},
+	"scan": {
+		RoleAssetManager:      {ActionRead, ActionCreate},
+		RoleDepartmentManager: {ActionRead, ActionCreate},
+		RoleViewer:            {ActionRead},
+	},
+	"search": {
+		RoleAssetManager:      {ActionRead},
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"asset-no-rules": {
+		RoleAssetManager:      {ActionRead, ActionUpdate},
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"maintenance": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionUpdate},
+		RoleViewer:            {ActionRead},
+	},
+	"disposals": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate},
+		RoleViewer:            {ActionRead},
+	},
+	"consumables": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"licenses": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"inventory": {
+		RoleAssetManager:      allActions,
+		RoleDepartmentManager: {ActionRead, ActionCreate, ActionUpdate},
+		RoleViewer:            {ActionRead},
+	},
+	"reports": {
+		RoleAssetManager:      {ActionRead, ActionExport},
+		RoleDepartmentManager: {ActionRead, ActionExport},
+		RoleViewer:            {ActionRead},
+	},
+	"logs": {
+		RoleAssetManager: {ActionRead},
+	},
+	"dashboard": {
+		RoleAssetManager:      {ActionRead},
+		RoleDepartmentManager: {ActionRead},
+		RoleViewer:            {ActionRead},
+	},
+	"upload": {
+		RoleAssetManager:      {ActionCreate},
+		RoleDepartmentManager: {ActionCreate},
+	},
+	"trash": {
+		RoleAssetManager: {ActionRead, ActionUpdate},
+	},
+}
+
+// IsValidRole 判断角色是否有效
+func IsValidRole(role string) bool {
+	for _, validRole := range ValidRoles {
+		if role == validRole {
+			return true
+		}
+	}
+	return false
+}
+
+// actionOverrides 与请求方法语义不一致的接口，按"方法 路径"指定操作
+var actionOverrides = map[string]string{
+	"POST /api/reports/custom":  ActionRead,
+	"POST /api/reports/monthly": ActionExport,
+}
+
+// ResolvePermission 根据请求方法和路径解析路由分组和操作
+func ResolvePermission(method, path string) (string, string) {
+	resource := strings.TrimPrefix(path, "/api/")
+	if index := strings.Index(resource, "/"); index >= 0 {
+		resource = resource[:index]
+	}
+
+	if action, exists := actionOverrides[method+" "+path]; exists {
+		return resource, action
+	}
+
+	// 导出、导入接口单独授权
+	if strings.Contains(path, "/export") || strings.Contains(path, "/download") {
+		return resource, ActionExport
+	}
+	if method == http.MethodPost && strings.Contains(path, "/import") {
+		return resource, ActionImport
+	}
+
+	var action string
+	switch method {
+	case http.MethodPost:
+		action = ActionCreate
+	case http.MethodPut, http.MethodPatch:
+		action = ActionUpdate
+	case http.MethodDelete:
+		action = ActionDelete
+	default:
+		action = ActionRead
+	}
+
+	return resource, action
+}
+
+// HasPermission 判断角色列表是否拥有路由分组的指定操作权限
+func HasPermission(roles []string, resource, action string) bool {
+	for _, selfService := range selfServiceResources {
+		if resource == selfService {
+			return true
+		}
+	}
+
+	rolePermissions := PermissionMatrix[resource]
+	for _, role := range roles {
+		if role == RoleAdmin {
+			return true
+		}
+		for _, allowed := range rolePermissions[role] {
+			if allowed == action {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"strings"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContextKeyDataScope 数据范围上下文键
+const ContextKeyDataScope = "data_scope"
+
+// dataScopeSettingKey 数据范围在gorm会话中的键
+const dataScopeSettingKey = "auth:data_scope"
+
+// dataScopeAppliedKey 标记语句已追加数据范围条件，避免重复追加
+const dataScopeAppliedKey = "auth:data_scope_applied"
+
+// GlobalScopeRoles 拥有全局数据视图的角色
+var GlobalScopeRoles = []string{RoleAdmin, RoleAssetManager}
+
+// ScopedTables 受数据范围约束的表，返回以别名引用的过滤条件
+// 新增带部门归属的业务表时在此登记即可被自动过滤
+var ScopedTables = map[string]func(alias string, departmentIDs []uint) clause.Expr{
+	"assets": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{SQL: alias + ".department_id IN ?", Vars: []interface{}{departmentIDs}}
+	},
+	"asset_component_logs": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  "(" + alias + ".parent_id IN (SELECT id FROM assets WHERE department_id IN ?) OR " + alias + ".component_id IN (SELECT id FROM assets WHERE department_id IN ?))",
+			Vars: []interface{}{departmentIDs, departmentIDs},
+		}
+	},
+	"borrow_records": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  "(" + alias + ".department_id IN ? OR " + alias + ".asset_id IN (SELECT id FROM assets WHERE department_id IN ?))",
+			Vars: []interface{}{departmentIDs, departmentIDs},
+		}
+	},
+	"asset_reservations": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  "(" + alias + ".department_id IN ? OR " + alias + ".asset_id IN (SELECT id FROM assets WHERE department_id IN ?))",
+			Vars: []interface{}{departmentIDs, departmentIDs},
+		}
+	},
+	"asset_transfers": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  "(" + alias + ".from_department_id IN ? OR " + alias + ".to_department_id IN ?)",
+			Vars: []interface{}{departmentIDs, departmentIDs},
+		}
+	},
+	"maintenance_orders": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  alias + ".asset_id IN (SELECT id FROM assets WHERE department_id IN ?)",
+			Vars: []interface{}{departmentIDs},
+		}
+	},
+	"asset_disposals": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  alias + ".asset_id IN (SELECT id FROM assets WHERE department_id IN ?)",
+			Vars: []interface{}{departmentIDs},
+		}
+	},
+	"asset_write_offs": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{SQL: alias + ".department_id IN ?", Vars: []interface{}{departmentIDs}}
+	},
+	"inventory_tasks": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{SQL: alias + ".department_id IN ?", Vars: []interface{}{departmentIDs}}
+	},
+	"inventory_records": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL:  alias + ".task_id IN (SELECT id FROM inventory_tasks WHERE department_id IN ?)",
+			Vars: []interface{}{departmentIDs},
+		}
+	},
+	"attachments": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL: "(" + alias + ".asset_id IN (SELECT id FROM assets WHERE department_id IN ?) OR (" +
+				alias + ".owner_type = ? AND " + alias + ".owner_id IN (SELECT id FROM borrow_records WHERE department_id IN ?)))",
+			Vars: []interface{}{departmentIDs, models.AttachmentOwnerBorrow, departmentIDs},
+		}
+	},
+	"search_documents": func(alias string, departmentIDs []uint) clause.Expr {
+		return clause.Expr{
+			SQL: "(" + alias + ".kind = ? OR (" + alias + ".kind = ? AND " + alias + ".ref_id IN (SELECT id FROM assets WHERE department_id IN ?)) OR (" +
+				alias + ".kind = ? AND " + alias + ".ref_id IN (SELECT id FROM borrow_records WHERE department_id IN ? OR asset_id IN (SELECT id FROM assets WHERE department_id IN ?))))",
+			Vars: []interface{}{models.SearchKindCategory, models.SearchKindAsset, departmentIDs, models.SearchKindBorrow, departmentIDs, departmentIDs},
+		}
+	},
+}
+
+// DataScope 数据可见范围
+type DataScope struct {
+	All           bool   `json:"all"`            // 全局可见
+	DepartmentID  *uint  `json:"department_id"`  // 所属部门
+	DepartmentIDs []uint `json:"department_ids"` // 可见部门（含子部门）
+}
+
+// ResolveDataScope 根据身份信息计算数据范围
+// 管理员和资产管理员拥有全局视图；部门负责人只能看到本部门及子部门；
+// 其余角色有所属部门时按部门过滤，未分配部门时保持全局只读视图
+func ResolveDataScope(identity *Identity) (*DataScope, error) {
+	if identity == nil {
+		return &DataScope{All: true}, nil
+	}
+
+	if identity.HasRole(GlobalScopeRoles...) {
+		return &DataScope{All: true, DepartmentID: identity.DepartmentID}, nil
+	}
+
+	if identity.DepartmentID == nil {
+		if identity.HasRole(RoleDepartmentManager) {
+			return &DataScope{}, nil
+		}
+		return &DataScope{All: true}, nil
+	}
+
+	departmentIDs, err := models.GetDepartmentDescendantIDs(global.DB, *identity.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataScope{DepartmentID: identity.DepartmentID, DepartmentIDs: departmentIDs}, nil
+}
+
+// GetDataScope 获取当前请求的数据范围（按请求缓存）
+func GetDataScope(c *gin.Context) (*DataScope, error) {
+	if value, exists := c.Get(ContextKeyDataScope); exists {
+		if scope, ok := value.(*DataScope); ok {
+			return scope, nil
+		}
+	}
+
+	scope, err := ResolveDataScope(GetIdentity(c))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(ContextKeyDataScope, scope)
+	return scope, nil
+}
+
+// DB 返回按数据范围自动过滤的数据库会话
+func (s *DataScope) DB() *gorm.DB {
+	if s.All {
+		return global.DB
+	}
+	return global.DB.Set(dataScopeSettingKey, s).Session(&gorm.Session{})
+}
+
+// Apply 为已构建的查询附加数据范围
+func (s *DataScope) Apply(query *gorm.DB) *gorm.DB {
+	if s.All {
+		return query
+	}
+	return query.Set(dataScopeSettingKey, s)
+}
+
+// ScopeOf 获取查询会话携带的数据范围，未携带时视为全局范围
+func ScopeOf(query *gorm.DB) *DataScope {
+	if value, exists := query.Get(dataScopeSettingKey); exists {
+		if scope, ok := value.(*DataScope); ok {
+			return scope
+		}
+	}
+	return &DataScope{All: true}
+}
+
+// CanAccessDepartment 判断部门是否在可见范围内，未分配部门的数据仅全局视图可见
+func (s *DataScope) CanAccessDepartment(departmentID *uint) bool {
+	if s.All {
+		return true
+	}
+	if departmentID == nil {
+		return false
+	}
+	for _, id := range s.DepartmentIDs {
+		if id == *departmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition 获取指定表的过滤条件，供原生SQL使用
+func (s *DataScope) Condition(table, alias string) (string, []interface{}) {
+	if s.All {
+		return "1 = 1", nil
+	}
+	builder, exists := ScopedTables[table]
+	if !exists {
+		return "1 = 1", nil
+	}
+	if len(s.DepartmentIDs) == 0 {
+		return "1 = 0", nil
+	}
+	expr := builder(alias, s.DepartmentIDs)
+	return expr.SQL, expr.Vars
+}
+
+// RegisterDataScopeCallbacks 注册数据范围查询回调
+func RegisterDataScopeCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("auth:data_scope_query", applyDataScope); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register("auth:data_scope_row", applyDataScope)
+}
+
+// applyDataScope 为受约束的表追加部门过滤条件
+func applyDataScope(db *gorm.DB) {
+	value, exists := db.Get(dataScopeSettingKey)
+	if !exists || db.Statement.SQL.Len() > 0 {
+		return
+	}
+	scope, ok := value.(*DataScope)
+	if !ok || scope.All {
+		return
+	}
+	if _, applied := db.Statement.Settings.Load(dataScopeAppliedKey); applied {
+		return
+	}
+
+	table, alias := resolveStatementTable(db.Statement)
+	builder, scoped := ScopedTables[table]
+	if !scoped {
+		return
+	}
+
+	var expr clause.Expression
+	if len(scope.DepartmentIDs) == 0 {
+		expr = clause.Expr{SQL: "1 = 0"}
+	} else {
+		expr = builder(alias, scope.DepartmentIDs)
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{expr}})
+	db.Statement.Settings.Store(dataScopeAppliedKey, true)
+}
+
+// resolveStatementTable 解析语句的真实表名和别名
+func resolveStatementTable(stmt *gorm.Statement) (string, string) {
+	if stmt.TableExpr != nil {
+		fields := strings.Fields(stmt.TableExpr.SQL)
+		if len(fields) == 0 {
+			return stmt.Table, stmt.Table
+		}
+		table := strings.Trim(fields[0], "`\"")
+		return table, strings.Trim(fields[len(fields)-1], "`\"")
+	}
+	return stmt.Table, stmt.Table
+}
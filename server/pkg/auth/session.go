@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// sessionKeyPrefix 会话在Redis中的键前缀
+const sessionKeyPrefix = "session:"
+
+// sessionTouchInterval 会话活跃时间写库的最小间隔，避免每次请求都写库
+const sessionTouchInterval = time.Minute
+
+// ErrSessionInvalid 会话不存在、已过期或已被注销
+var ErrSessionInvalid = errors.New("会话已失效，请重新登录")
+
+// CreateSession 为用户创建会话，同时清理该用户已过期的会话
+func CreateSession(userID uint, ipAddress, userAgent string) (*models.UserSession, error) {
+	now := time.Now()
+	if err := global.DB.Where("user_id = ? AND expires_at < ?", userID, now).Delete(&models.UserSession{}).Error; err != nil {
+		return nil, fmt.Errorf("清理过期会话失败: %v", err)
+	}
+
+	session := models.UserSession{
+		UserID:       userID,
+		SessionToken: uuid.NewString(),
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
+		ExpiresAt:    now.Add(sessionIdleDuration()),
+		LastActiveAt: now,
+	}
+	if err := global.DB.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("创建会话失败: %v", err)
+	}
+
+	cacheSession(&session)
+	return &session, nil
+}
+
+// ValidateSession 校验会话归属和有效期，并顺延空闲过期时间
+// 启用Redis时优先读取缓存，未命中或Redis异常时回退到数据库
+func ValidateSession(sessionToken string, userID uint) error {
+	if sessionToken == "" {
+		return ErrSessionInvalid
+	}
+
+	if global.Redis != nil {
+		if hit, err := touchCachedSession(sessionToken, userID); err != nil || hit {
+			return err
+		}
+	}
+
+	var session models.UserSession
+	if err := global.DB.Where("session_token = ?", sessionToken).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrSessionInvalid
+		}
+		return fmt.Errorf("查询会话失败: %v", err)
+	}
+
+	if session.UserID != userID || session.IsExpired() {
+		return ErrSessionInvalid
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastActiveAt) >= sessionTouchInterval {
+		updates := map[string]interface{}{
+			"expires_at":     now.Add(sessionIdleDuration()),
+			"last_active_at": now,
+		}
+		if err := global.DB.Model(&session).UpdateColumns(updates).Error; err != nil {
+			return fmt.Errorf("更新会话失败: %v", err)
+		}
+		session.ExpiresAt = now.Add(sessionIdleDuration())
+		session.LastActiveAt = now
+	}
+
+	cacheSession(&session)
+	return nil
+}
+
+// LoadSessionIdentity 按令牌中的用户加载身份，角色和部门以数据库中的用户为准，不信任令牌声明
+// 用户已删除时返回 ErrSessionInvalid，已禁用时返回 ErrUserDisabled
+func LoadSessionIdentity(claims *Claims) (*Identity, error) {
+	var user models.User
+	if err := global.DB.First(&user, claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSessionInvalid
+		}
+		return nil, fmt.Errorf("查询用户失败: %v", err)
+	}
+	if !user.IsActive {
+		return nil, ErrUserDisabled
+	}
+
+	identity := NewIdentityFromUser(&user)
+	identity.SessionID = claims.SessionID
+	return identity, nil
+}
+
+// GetUserSessions 获取用户的有效会话
+func GetUserSessions(userID uint) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	if err := global.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_active_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("查询会话失败: %v", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession 注销单个会话
+func RevokeSession(session *models.UserSession) error {
+	if err := global.DB.Delete(session).Error; err != nil {
+		return fmt.Errorf("注销会话失败: %v", err)
+	}
+	uncacheSessions(session.SessionToken)
+	return nil
+}
+
+// RevokeUserSessions 注销用户的全部会话，exceptToken 不为空时保留该会话
+func RevokeUserSessions(userID uint, exceptToken string) (int64, error) {
+	if exceptToken == "" {
+		forgetDooTaskUser(userID)
+	}
+
+	query := global.DB.Model(&models.UserSession{}).Where("user_id = ?", userID)
+	if exceptToken != "" {
+		query = query.Where("session_token <> ?", exceptToken)
+	}
+
+	var sessionTokens []string
+	if err := query.Pluck("session_token", &sessionTokens).Error; err != nil {
+		return 0, fmt.Errorf("查询会话失败: %v", err)
+	}
+	if len(sessionTokens) == 0 {
+		return 0, nil
+	}
+
+	result := global.DB.Where("session_token IN ?", sessionTokens).Delete(&models.UserSession{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("注销会话失败: %v", result.Error)
+	}
+
+	uncacheSessions(sessionTokens...)
+	return result.RowsAffected, nil
+}
+
+// touchCachedSession 从Redis校验会话并顺延过期时间，返回是否命中缓存
+func touchCachedSession(sessionToken string, userID uint) (bool, error) {
+	ctx := context.Background()
+	key := sessionKeyPrefix + sessionToken
+
+	values, err := global.Redis.HGetAll(ctx, key).Result()
+	if err != nil || len(values) == 0 {
+		return false, nil
+	}
+	if values["user_id"] != strconv.FormatUint(uint64(userID), 10) {
+		return false, nil
+	}
+
+	now := time.Now()
+	persistedAt, _ := strconv.ParseInt(values["persisted_at"], 10, 64)
+	if now.Sub(time.Unix(persistedAt, 0)) >= sessionTouchInterval {
+		updates := map[string]interface{}{
+			"expires_at":     now.Add(sessionIdleDuration()),
+			"last_active_at": now,
+		}
+		result := global.DB.Model(&models.UserSession{}).Where("session_token = ?", sessionToken).UpdateColumns(updates)
+		if result.Error != nil {
+			return false, fmt.Errorf("更新会话失败: %v", result.Error)
+		}
+		// 数据库中已不存在说明会话已被注销
+		if result.RowsAffected == 0 {
+			uncacheSessions(sessionToken)
+			return true, ErrSessionInvalid
+		}
+		global.Redis.HSet(ctx, key, "persisted_at", now.Unix())
+	}
+
+	global.Redis.Expire(ctx, key, sessionIdleDuration())
+	return true, nil
+}
+
+// cacheSession 将会话写入Redis，过期时间与会话剩余有效期一致
+func cacheSession(session *models.UserSession) {
+	if global.Redis == nil {
+		return
+	}
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	key := sessionKeyPrefix + session.SessionToken
+	pipe := global.Redis.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", session.UserID, "persisted_at", session.LastActiveAt.Unix())
+	pipe.Expire(ctx, key, ttl)
+	_, _ = pipe.Exec(ctx)
+}
+
+// uncacheSessions 从Redis删除会话
+func uncacheSessions(sessionTokens ...string) {
+	if global.Redis == nil || len(sessionTokens) == 0 {
+		return
+	}
+	keys := make([]string, len(sessionTokens))
+	for i, sessionToken := range sessionTokens {
+		keys[i] = sessionKeyPrefix + sessionToken
+	}
+	global.Redis.Del(context.Background(), keys...)
+}
+
+// sessionIdleDuration 会话空闲超时时长
+func sessionIdleDuration() time.Duration {
+	if global.AppConfig.SessionIdleHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(global.AppConfig.SessionIdleHours) * time.Hour
+}
@@ -7,6 +7,16 @@ import (
 	"strconv"
 )
 
+const (
+	// DefaultJWTSecret 未配置 JWT_SECRET 时的默认密钥
+	DefaultJWTSecret = "default-jwt-secret"
+	// DefaultAdminPassword 未配置 ADMIN_PASSWORD 时初始管理员的默认密码
+	DefaultAdminPassword = "admin123"
+)
+
+// insecureJWTSecrets 默认值和示例配置文件中的JWT密钥，使用这些密钥时任何人都能伪造令牌
+var insecureJWTSecrets = []string{"", DefaultJWTSecret, "dev-jwt-secret-key", "your-jwt-secret-key-change-in-production"}
+
 // LoadConfig 加载应用配置
 func LoadConfig() *global.Config {
 	config := &global.Config{
@@ -22,6 +32,14 @@ func LoadConfig() *global.Config {
 		
 		UploadDir:          utils.GetEnvWithDefault("UPLOAD_DIR", "./uploads"),
 		UploadAllowedTypes: utils.GetEnvWithDefault("UPLOAD_ALLOWED_TYPES", "jpg,jpeg,png,gif,pdf,doc,docx,xlsx,xls"),
+		UploadSweepIntervalHours: getIntEnv("UPLOAD_SWEEP_INTERVAL_HOURS", 24),
+		UploadSweepGraceHours:    getIntEnv("UPLOAD_SWEEP_GRACE_HOURS", 24),
+
+		TrashRetentionDays: getIntEnv("TRASH_RETENTION_DAYS", 30),
+
+		ReservationPickupGraceHours: getIntEnv("RESERVATION_PICKUP_GRACE_HOURS", 24),
+		
+		LabelFontPath: utils.GetEnvWithDefault("LABEL_FONT_PATH", ""),
 		
 		LogLevel:      utils.GetEnvWithDefault("LOG_LEVEL", "info"),
 		EnableDebug:   getBoolEnv("ENABLE_DEBUG", false),
@@ -30,8 +48,23 @@ func LoadConfig() *global.Config {
 		NextOutputMode:       utils.GetEnvWithDefault("NEXT_OUTPUT_MODE", ""),
 		NextPublicAPIBaseURL: utils.GetEnvWithDefault("NEXT_PUBLIC_API_BASE_URL", ""),
 		
-		JWTSecret:   utils.GetEnvWithDefault("JWT_SECRET", "default-jwt-secret"),
-		CORSOrigins: utils.GetEnvWithDefault("CORS_ORIGINS", "*"),
+		JWTSecret:             utils.GetEnvWithDefault("JWT_SECRET", DefaultJWTSecret),
+		JWTIssuer:             utils.GetEnvWithDefault("JWT_ISSUER", "asset-management-system"),
+		JWTExpireHours:        getIntEnv("JWT_EXPIRE_HOURS", 2),
+		JWTRefreshExpireHours: getIntEnv("JWT_REFRESH_EXPIRE_HOURS", 168),
+		CORSOrigins:           utils.GetEnvWithDefault("CORS_ORIGINS", "*"),
+		SessionIdleHours:      getIntEnv("SESSION_IDLE_HOURS", 24),
+		
+		RedisEnabled: getBoolEnv("REDIS_ENABLED", false),
+		
+		DooTaskSSOEnabled:   getBoolEnv("DOOTASK_SSO_ENABLED", false),
+		DooTaskUserInfoURL:  utils.GetEnvWithDefault("DOOTASK_USER_INFO_URL", "http://nginx/api/users/info"),
+		DooTaskTokenHeader:  utils.GetEnvWithDefault("DOOTASK_TOKEN_HEADER", "X-DooTask-Token"),
+		DooTaskCacheSeconds: getIntEnv("DOOTASK_CACHE_SECONDS", 300),
+		DooTaskDefaultRole:  utils.GetEnvWithDefault("DOOTASK_DEFAULT_ROLE", "viewer"),
+		
+		AdminUsername: utils.GetEnvWithDefault("ADMIN_USERNAME", "admin"),
+		AdminPassword: utils.GetEnvWithDefault("ADMIN_PASSWORD", DefaultAdminPassword),
 		
 		MaxConcurrentRequests:   getIntEnv("MAX_CONCURRENT_REQUESTS", 100),
 		RequestTimeout:          getIntEnv("REQUEST_TIMEOUT", 30),
@@ -97,4 +130,14 @@ func IsProduction() bool {
 // IsDevelopment 判断是否为开发环境
 func IsDevelopment() bool {
 	return global.AppConfig.NodeEnv == "development"
-}
\ No newline at end of file
+}
+
+// IsInsecureJWTSecret 判断JWT密钥是否为空、默认值或示例配置中的值
+func IsInsecureJWTSecret() bool {
+	for _, secret := range insecureJWTSecrets {
+		if global.AppConfig.JWTSecret == secret {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package depreciation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"asset-management-system/server/models"
+)
+
+// PeriodLayout 折旧期间格式
+const PeriodLayout = "2006-01"
+
+var (
+	ErrNotDepreciable  = errors.New("资产缺少采购价格或采购日期，无法计算折旧")
+	ErrSettingsMissing = errors.New("资产及其分类均未配置折旧参数")
+	ErrSettingsInvalid = errors.New("折旧参数无效")
+)
+
+// IsSettingsError 判断是否为资产数据或折旧参数导致的无法计算（而非数据库等系统错误）
+func IsSettingsError(err error) bool {
+	return errors.Is(err, ErrNotDepreciable) || errors.Is(err, ErrSettingsMissing) || errors.Is(err, ErrSettingsInvalid)
+}
+
+// Settings 折旧参数
+type Settings struct {
+	Method           models.DepreciationMethod `json:"method"`
+	UsefulLifeMonths int                       `json:"useful_life_months"`
+	SalvageRate      float64                   `json:"salvage_rate"`
+}
+
+// Validate 校验折旧参数
+func (s Settings) Validate() error {
+	if !models.IsValidDepreciationMethod(s.Method) {
+		return fmt.Errorf("%w: 不支持的折旧方法 %s", ErrSettingsInvalid, s.Method)
+	}
+	if s.UsefulLifeMonths <= 0 {
+		return fmt.Errorf("%w: 使用寿命必须大于0", ErrSettingsInvalid)
+	}
+	if s.SalvageRate < 0 || s.SalvageRate >= 1 {
+		return fmt.Errorf("%w: 残值率必须在0到1之间", ErrSettingsInvalid)
+	}
+	// 加速折旧法按年计算，使用寿命须为整年
+	if s.Method != models.DepreciationMethodStraightLine && s.UsefulLifeMonths%12 != 0 {
+		return fmt.Errorf("%w: %s 的使用寿命必须为12的整数倍", ErrSettingsInvalid, s.Method)
+	}
+	return nil
+}
+
+// CheckPartial 校验分类或资产上配置的部分折旧参数，未配置的参数由上级继承，只校验已给出参数之间的约束
+func CheckPartial(method models.DepreciationMethod, usefulLifeMonths *int) error {
+	if method == "" || usefulLifeMonths == nil {
+		return nil
+	}
+	return Settings{Method: method, UsefulLifeMonths: *usefulLifeMonths}.Validate()
+}
+
+// Entry 月度折旧明细
+type Entry struct {
+	Period       string  `json:"period"`       // 折旧期间（YYYY-MM）
+	Depreciation float64 `json:"depreciation"` // 本期折旧额
+	Accumulated  float64 `json:"accumulated"`  // 累计折旧
+	NetValue     float64 `json:"net_value"`    // 期末净值
+}
+
+// Schedule 折旧计划
+type Schedule struct {
+	Settings
+	OriginalValue float64 `json:"original_value"` // 原值
+	SalvageValue  float64 `json:"salvage_value"`  // 预计净残值
+	StartPeriod   string  `json:"start_period"`   // 首个折旧期间
+	EndPeriod     string  `json:"end_period"`     // 最后一个折旧期间
+	Entries       []Entry `json:"entries"`
+}
+
+// Calculate 生成月度折旧计划
+// 按会计惯例，当月购入的资产从次月开始计提折旧，最后一期补齐尾差使净值等于净残值
+func Calculate(originalValue float64, purchaseDate time.Time, settings Settings) (*Schedule, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if originalValue <= 0 {
+		return nil, ErrNotDepreciable
+	}
+
+	salvageValue := round(originalValue * settings.SalvageRate)
+	monthly := monthlyAmounts(originalValue, salvageValue, settings)
+
+	start := time.Date(purchaseDate.Year(), purchaseDate.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+	schedule := &Schedule{
+		Settings:      settings,
+		OriginalValue: round(originalValue),
+		SalvageValue:  salvageValue,
+		StartPeriod:   start.Format(PeriodLayout),
+		EndPeriod:     start.AddDate(0, settings.UsefulLifeMonths-1, 0).Format(PeriodLayout),
+		Entries:       make([]Entry, 0, settings.UsefulLifeMonths),
+	}
+
+	depreciable := round(originalValue - salvageValue)
+	accumulated := 0.0
+	for i, amount := range monthly {
+		amount = round(amount)
+		if i == len(monthly)-1 || accumulated+amount > depreciable {
+			amount = round(depreciable - accumulated)
+		}
+		accumulated = round(accumulated + amount)
+		schedule.Entries = append(schedule.Entries, Entry{
+			Period:       start.AddDate(0, i, 0).Format(PeriodLayout),
+			Depreciation: amount,
+			Accumulated:  accumulated,
+			NetValue:     round(schedule.OriginalValue - accumulated),
+		})
+	}
+
+	return schedule, nil
+}
+
+// ParsePeriod 校验折旧期间（YYYY-MM），为空时返回当前期间
+func ParsePeriod(period string) (string, error) {
+	if period == "" {
+		return time.Now().Format(PeriodLayout), nil
+	}
+	parsed, err := time.Parse(PeriodLayout, period)
+	if err != nil {
+		return "", fmt.Errorf("无效的折旧期间: %s，格式应为 YYYY-MM", period)
+	}
+	return parsed.Format(PeriodLayout), nil
+}
+
+// PeriodSummary 指定期间的折旧情况
+type PeriodSummary struct {
+	Period       string  `json:"period"`
+	Depreciation float64 `json:"depreciation"` // 本期折旧额
+	Accumulated  float64 `json:"accumulated"`  // 截至本期的累计折旧
+	NetValue     float64 `json:"net_value"`    // 期末净值
+}
+
+// At 获取指定期间（YYYY-MM）的折旧情况，早于首期时净值为原值，晚于末期时净值为净残值
+func (s *Schedule) At(period string) PeriodSummary {
+	summary := PeriodSummary{Period: period, NetValue: s.OriginalValue}
+	if len(s.Entries) == 0 || period < s.StartPeriod {
+		return summary
+	}
+
+	last := s.Entries[len(s.Entries)-1]
+	if period > s.EndPeriod {
+		summary.Accumulated = last.Accumulated
+		summary.NetValue = last.NetValue
+		return summary
+	}
+
+	for _, entry := range s.Entries {
+		if entry.Period == period {
+			summary.Depreciation = entry.Depreciation
+			summary.Accumulated = entry.Accumulated
+			summary.NetValue = entry.NetValue
+			break
+		}
+	}
+	return summary
+}
+
+// monthlyAmounts 按折旧方法计算每月折旧额（未取整）
+func monthlyAmounts(originalValue, salvageValue float64, settings Settings) []float64 {
+	months := settings.UsefulLifeMonths
+	amounts := make([]float64, months)
+	depreciable := originalValue - salvageValue
+
+	switch settings.Method {
+	case models.DepreciationMethodDoubleDeclining:
+		// 双倍余额递减法：年折旧率为 2/使用年限，最后两年改为直线法摊销剩余可折旧额
+		years := months / 12
+		rate := 2.0 / float64(years)
+		bookValue := originalValue
+		switchAmount := 0.0
+		for year := 0; year < years; year++ {
+			var annual float64
+			if year >= years-2 {
+				if year == years-2 || years < 2 {
+					switchAmount = (bookValue - salvageValue) / float64(years-year)
+				}
+				annual = switchAmount
+			} else {
+				annual = math.Min(bookValue*rate, bookValue-salvageValue)
+			}
+			bookValue -= annual
+			for m := 0; m < 12; m++ {
+				amounts[year*12+m] = annual / 12
+			}
+		}
+	case models.DepreciationMethodSumOfYears:
+		// 年数总和法：年折旧额 = 可折旧额 × 尚可使用年数 / 年数总和
+		years := months / 12
+		total := float64(years*(years+1)) / 2
+		for year := 0; year < years; year++ {
+			annual := depreciable * float64(years-year) / total
+			for m := 0; m < 12; m++ {
+				amounts[year*12+m] = annual / 12
+			}
+		}
+	default:
+		// 直线法：按月平均分摊
+		for i := range amounts {
+			amounts[i] = depreciable / float64(months)
+		}
+	}
+
+	return amounts
+}
+
+// round 保留两位小数
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,92 @@
+package depreciation
+
+import (
+	"fmt"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// Resolver 折旧参数解析器，按 资产 -> 分类 -> 上级分类 的顺序逐项取值，并缓存已加载的分类
+type Resolver struct {
+	db         *gorm.DB
+	categories map[uint]*models.Category
+}
+
+// NewResolver 创建折旧参数解析器
+func NewResolver(db *gorm.DB) *Resolver {
+	return &Resolver{db: db, categories: make(map[uint]*models.Category)}
+}
+
+// Resolve 解析资产的折旧参数
+func (r *Resolver) Resolve(asset *models.Asset) (*Settings, error) {
+	method := asset.DepreciationMethod
+	usefulLife := asset.UsefulLifeMonths
+	salvageRate := asset.SalvageRate
+
+	categoryID := &asset.CategoryID
+	for depth := 0; categoryID != nil && depth < 20; depth++ {
+		if method != "" && usefulLife != nil && salvageRate != nil {
+			break
+		}
+		category, err := r.category(*categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			break
+		}
+		if method == "" {
+			method = category.DepreciationMethod
+		}
+		if usefulLife == nil {
+			usefulLife = category.UsefulLifeMonths
+		}
+		if salvageRate == nil {
+			salvageRate = category.SalvageRate
+		}
+		categoryID = category.ParentID
+	}
+
+	if method == "" || usefulLife == nil {
+		return nil, ErrSettingsMissing
+	}
+
+	settings := &Settings{Method: method, UsefulLifeMonths: *usefulLife}
+	if salvageRate != nil {
+		settings.SalvageRate = *salvageRate
+	}
+	return settings, nil
+}
+
+// Schedule 生成资产的折旧计划
+func (r *Resolver) Schedule(asset *models.Asset) (*Schedule, error) {
+	if asset.PurchasePrice == nil || *asset.PurchasePrice <= 0 || asset.PurchaseDate == nil {
+		return nil, ErrNotDepreciable
+	}
+	settings, err := r.Resolve(asset)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(*asset.PurchasePrice, *asset.PurchaseDate, *settings)
+}
+
+// category 获取分类（带缓存），分类不存在时返回nil
+func (r *Resolver) category(id uint) (*models.Category, error) {
+	if category, exists := r.categories[id]; exists {
+		return category, nil
+	}
+
+	var category models.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			r.categories[id] = nil
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	}
+
+	r.categories[id] = &category
+	return &category, nil
+}
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrDepartmentNotFound 调入部门已不存在
+var ErrDepartmentNotFound = errors.New("调入部门不存在")
+
+// processAssetUpdate 批量更新一批资产，已删除或不满足更新条件的资产记为失败
+func processAssetUpdate(tx *gorm.DB, job *models.BulkJob, ids []uint) (int, []models.BulkJobError, error) {
+	var update models.AssetBatchUpdate
+	if err := json.Unmarshal(job.Params, &update); err != nil {
+		return 0, nil, err
+	}
+
+	// 部门可能在任务排队期间被删除
+	if update.DepartmentID != nil {
+		var count int64
+		if err := tx.Model(&models.Department{}).Where("id = ?", *update.DepartmentID).Count(&count).Error; err != nil {
+			return 0, nil, err
+		}
+		if count == 0 {
+			return 0, nil, ErrDepartmentNotFound
+		}
+	}
+
+	assets, err := loadAssets(tx, ids)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	failures := make([]models.BulkJobError, 0)
+	valid := make([]models.Asset, 0, len(ids))
+	for _, id := range ids {
+		asset, exists := assets[id]
+		if !exists {
+			failures = append(failures, models.BulkJobError{AssetID: id, Error: "资产不存在"})
+			continue
+		}
+		reason, err := models.AssetUpdateBlockedReason(tx, &asset, &update)
+		if err != nil {
+			return 0, nil, err
+		}
+		if reason != "" {
+			failures = append(failures, models.BulkJobError{AssetID: id, AssetNo: asset.AssetNo, Error: reason})
+			continue
+		}
+		valid = append(valid, asset)
+	}
+
+	reason := fmt.Sprintf("批量任务 #%d 直接变更部门", job.ID)
+	if err := models.ApplyAssetBatchUpdate(tx, valid, &update, job.CreatedBy, reason); err != nil {
+		return 0, nil, err
+	}
+	return len(valid), failures, nil
+}
+
+// processAssetDelete 批量删除一批资产，已删除或不满足删除条件的资产记为失败
+func processAssetDelete(tx *gorm.DB, job *models.BulkJob, ids []uint) (int, []models.BulkJobError, error) {
+	assets, err := loadAssets(tx, ids)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	grace := time.Duration(global.AppConfig.ReservationPickupGraceHours) * time.Hour
+	succeeded := 0
+	failures := make([]models.BulkJobError, 0)
+	for _, id := range ids {
+		asset, exists := assets[id]
+		if !exists {
+			failures = append(failures, models.BulkJobError{AssetID: id, Error: "资产不存在"})
+			continue
+		}
+		if err := models.CheckAssetDeletable(tx, &asset, grace); err != nil {
+			if !models.IsAssetDeleteBlocked(err) {
+				return 0, nil, err
+			}
+			failures = append(failures, models.BulkJobError{AssetID: id, AssetNo: asset.AssetNo, Error: err.Error()})
+			continue
+		}
+
+		// 拆下全部组件，并从所属父资产拆下
+		if err := models.DetachAllComponents(tx, &asset, job.CreatedBy, "资产已删除"); err != nil {
+			return 0, nil, err
+		}
+		if err := tx.Delete(&asset).Error; err != nil {
+			return 0, nil, err
+		}
+		succeeded++
+	}
+	return succeeded, failures, nil
+}
+
+// loadAssets 按ID加载资产
+func loadAssets(tx *gorm.DB, ids []uint) (map[uint]models.Asset, error) {
+	var assets []models.Asset
+	if err := tx.Where("id IN ?", ids).Find(&assets).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]models.Asset, len(assets))
+	for _, asset := range assets {
+		result[asset.ID] = asset
+	}
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// ChunkSize 每批处理的资产数，每批在一个事务中完成
+const ChunkSize = 100
+
+// MaxTargets 单个任务最多处理的资产数
+const MaxTargets = 50000
+
+// pollInterval 没有新任务通知时检查排队任务的间隔
+const pollInterval = 30 * time.Second
+
+// chunkPause 批次之间的间隔，让出写锁给前台请求
+const chunkPause = 50 * time.Millisecond
+
+// wake 提交任务后唤醒执行器
+var wake = make(chan struct{}, 1)
+
+// processor 处理一批资产，返回成功数和失败记录
+type processor func(tx *gorm.DB, job *models.BulkJob, ids []uint) (int, []models.BulkJobError, error)
+
+// processors 各类型任务的批次处理函数
+var processors = map[models.BulkJobType]processor{
+	models.BulkJobTypeAssetUpdate: processAssetUpdate,
+	models.BulkJobTypeAssetDelete: processAssetDelete,
+}
+
+// Submit 创建排队中的任务并唤醒执行器
+// ids 为待处理的资产ID，failures 为提交时已确定失败的资产（如不存在或不在数据范围内），计入总数和已处理数
+func Submit(db *gorm.DB, job *models.BulkJob, ids []uint, failures []models.BulkJobError) error {
+	if _, ok := processors[job.Type]; !ok {
+		return fmt.Errorf("不支持的任务类型: %s", job.Type)
+	}
+	assetIDs, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	job.AssetIDs = assetIDs
+	job.Status = models.BulkJobStatusPending
+	job.Total = len(ids) + len(failures)
+	job.Processed = len(failures)
+	job.FailedCount = len(failures)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		if len(failures) == 0 {
+			return nil
+		}
+		for i := range failures {
+			failures[i].JobID = job.ID
+		}
+		return tx.CreateInBatches(failures, ChunkSize).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Cancel 取消任务：排队中的任务直接取消，执行中的任务在当前批次完成后停止
+func Cancel(db *gorm.DB, job *models.BulkJob) error {
+	result := db.Model(&models.BulkJob{}).
+		Where("id = ? AND status = ?", job.ID, models.BulkJobStatusPending).
+		Updates(map[string]interface{}{"status": models.BulkJobStatusCancelled, "finished_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	return db.Model(&models.BulkJob{}).
+		Where("id = ? AND status = ?", job.ID, models.BulkJobStatusRunning).
+		Update("cancel_requested", true).Error
+}
+
+// Start 启动后台执行器，按提交顺序逐个执行任务
+// 服务重启时中断的任务从已处理的位置继续执行
+func Start(db *gorm.DB) {
+	if err := db.Model(&models.BulkJob{}).
+		Where("status = ?", models.BulkJobStatusRunning).
+		Update("status", models.BulkJobStatusPending).Error; err != nil {
+		log.Printf("恢复中断的批量任务失败: %v", err)
+	}
+
+	go func() {
+		for {
+			for runNext(db) {
+			}
+			select {
+			case <-wake:
+			case <-time.After(pollInterval):
+			}
+		}
+	}()
+}
+
+// runNext 执行下一个排队中的任务，没有任务时返回 false
+func runNext(db *gorm.DB) bool {
+	var job models.BulkJob
+	err := db.Where("status = ?", models.BulkJobStatusPending).Order("id ASC").First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return false
+	}
+	if err != nil {
+		log.Printf("查询排队中的批量任务失败: %v", err)
+		return false
+	}
+
+	if err := run(db, &job); err != nil {
+		log.Printf("批量任务 #%d 执行失败: %v", job.ID, err)
+		if err := finish(db, &job, models.BulkJobStatusFailed, err.Error()); err != nil {
+			log.Printf("更新批量任务 #%d 状态失败: %v", job.ID, err)
+		}
+	}
+	return true
+}
+
+// run 按批次执行任务，每批开始前检查是否已请求取消
+func run(db *gorm.DB, job *models.BulkJob) error {
+	process, ok := processors[job.Type]
+	if !ok {
+		return fmt.Errorf("不支持的任务类型: %s", job.Type)
+	}
+	var ids []uint
+	if err := json.Unmarshal(job.AssetIDs, &ids); err != nil {
+		return err
+	}
+
+	// 以条件更新领取任务，避免与取消操作冲突
+	startedAt := time.Now()
+	if job.StartedAt != nil {
+		startedAt = *job.StartedAt
+	}
+	result := db.Model(&models.BulkJob{}).
+		Where("id = ? AND status = ?", job.ID, models.BulkJobStatusPending).
+		Updates(map[string]interface{}{"status": models.BulkJobStatusRunning, "started_at": startedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	job.Status = models.BulkJobStatusRunning
+
+	// 已处理数包含提交时已确定失败的资产，待处理资产从其后开始
+	offset := job.Processed - (job.Total - len(ids))
+	for offset < len(ids) {
+		var current models.BulkJob
+		if err := db.Select("cancel_requested").First(&current, job.ID).Error; err != nil {
+			return err
+		}
+		if current.CancelRequested {
+			return finish(db, job, models.BulkJobStatusCancelled, "")
+		}
+
+		end := offset + ChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := runChunk(db, job, process, ids[offset:end]); err != nil {
+			return err
+		}
+		offset = end
+		time.Sleep(chunkPause)
+	}
+
+	return finish(db, job, models.BulkJobStatusCompleted, "")
+}
+
+// runChunk 在一个事务中处理一批资产并更新进度
+func runChunk(db *gorm.DB, job *models.BulkJob, process processor, ids []uint) (err error) {
+	// 开始事务
+	tx := db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			err = fmt.Errorf("批次执行异常: %v", r)
+		}
+	}()
+
+	succeeded, failures, err := process(tx, job, ids)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(failures) > 0 {
+		for i := range failures {
+			failures[i].JobID = job.ID
+		}
+		if err := tx.Create(&failures).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	progress := map[string]interface{}{
+		"processed":       job.Processed + len(ids),
+		"succeeded_count": job.SucceededCount + succeeded,
+		"failed_count":    job.FailedCount + len(failures),
+	}
+	if err := tx.Model(&models.BulkJob{}).Where("id = ?", job.ID).Updates(progress).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	job.Processed += len(ids)
+	job.SucceededCount += succeeded
+	job.FailedCount += len(failures)
+	return nil
+}
+
+// finish 结束任务
+func finish(db *gorm.DB, job *models.BulkJob, status models.BulkJobStatus, reason string) error {
+	now := time.Now()
+	job.Status = status
+	job.Error = reason
+	job.FinishedAt = &now
+	return db.Model(&models.BulkJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       reason,
+		"finished_at": now,
+	}).Error
+}
@@ -0,0 +1,400 @@
+package labels
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// ConfigKeyQRURL 二维码内容模板的配置键，支持 {id} 和 {asset_no} 占位符，未配置时二维码内容为资产编号
+const ConfigKeyQRURL = "label_qr_url"
+
+// 码制
+const (
+	CodeQR      = "qr"      // 二维码
+	CodeCode128 = "code128" // Code128 条形码
+)
+
+// DefaultSize 默认标签尺寸（毫米）
+const DefaultSize = "60x40"
+
+const (
+	dpi          = 300   // 标签渲染分辨率
+	mmPerInch    = 25.4  // 每英寸毫米数
+	minWidthMM   = 30.0  // 标签最小宽度
+	maxWidthMM   = 190.0 // 标签最大宽度，须能放入 A4 纸
+	minHeightMM  = 15.0  // 标签最小高度
+	maxHeightMM  = 270.0 // 标签最大高度
+	sheetWidth   = 210.0 // A4 宽度（毫米）
+	sheetHeight  = 297.0 // A4 高度（毫米）
+	sheetMargin  = 8.0   // A4 页边距（毫米）
+	sheetSpacing = 2.0   // 标签间距（毫米）
+	minFontScale = 0.4   // 文本缩小字号的下限（相对行高）
+)
+
+// ErrCode128Charset Code128 只能编码 ASCII 字符
+var ErrCode128Charset = errors.New("资产编号包含无法编码为 Code128 的字符，请改用二维码")
+
+// Size 标签尺寸（毫米）
+type Size struct {
+	WidthMM  float64 `json:"width_mm"`
+	HeightMM float64 `json:"height_mm"`
+}
+
+// Options 标签渲染选项
+type Options struct {
+	Code string // qr 或 code128
+	Size Size
+}
+
+// Label 标签内容
+type Label struct {
+	Payload    string // 二维码编码的内容，条形码始终编码资产编号
+	AssetNo    string
+	Name       string
+	Department string
+}
+
+// ParseSize 解析 宽x高 形式的标签尺寸，如 60x40，为空时使用默认尺寸
+func ParseSize(value string) (Size, error) {
+	if value == "" {
+		value = DefaultSize
+	}
+	parts := strings.Split(strings.ToLower(value), "x")
+	if len(parts) != 2 {
+		return Size{}, fmt.Errorf("标签尺寸格式应为 宽x高（毫米），如 %s", DefaultSize)
+	}
+	width, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return Size{}, fmt.Errorf("标签尺寸格式应为 宽x高（毫米），如 %s", DefaultSize)
+	}
+	height, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return Size{}, fmt.Errorf("标签尺寸格式应为 宽x高（毫米），如 %s", DefaultSize)
+	}
+	if width < minWidthMM || width > maxWidthMM || height < minHeightMM || height > maxHeightMM {
+		return Size{}, fmt.Errorf("标签宽度须在%.0f到%.0f毫米之间，高度须在%.0f到%.0f毫米之间", minWidthMM, maxWidthMM, minHeightMM, maxHeightMM)
+	}
+	return Size{WidthMM: width, HeightMM: height}, nil
+}
+
+// NewLabel 根据资产生成标签内容，urlTemplate 为空时二维码内容为资产编号
+func NewLabel(asset *models.Asset, urlTemplate string) Label {
+	label := Label{
+		Payload: asset.AssetNo,
+		AssetNo: asset.AssetNo,
+		Name:    asset.Name,
+	}
+	if asset.Department != nil {
+		label.Department = asset.Department.Name
+	}
+	if urlTemplate != "" {
+		label.Payload = strings.NewReplacer(
+			"{id}", strconv.FormatUint(uint64(asset.ID), 10),
+			"{asset_no}", url.PathEscape(asset.AssetNo),
+		).Replace(urlTemplate)
+	}
+	return label
+}
+
+// ParsePayload 按二维码内容模板解析扫码得到的内容，返回其中的资产ID或资产编号；不符合模板时 ok 为 false
+func ParsePayload(payload, urlTemplate string) (id uint, assetNo string, ok bool) {
+	if urlTemplate == "" {
+		return 0, "", false
+	}
+
+	pattern := regexp.QuoteMeta(urlTemplate)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{id}"), `(?P<id>\d+)`, 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{asset_no}"), `(?P<asset_no>[^/?#&]+)`, 1)
+	re, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return 0, "", false
+	}
+	match := re.FindStringSubmatch(payload)
+	if match == nil {
+		return 0, "", false
+	}
+
+	for i, name := range re.SubexpNames() {
+		switch name {
+		case "id":
+			if value, err := strconv.ParseUint(match[i], 10, 32); err == nil {
+				id = uint(value)
+			}
+		case "asset_no":
+			if value, err := url.PathUnescape(match[i]); err == nil {
+				assetNo = value
+			}
+		}
+	}
+	return id, assetNo, id != 0 || assetNo != ""
+}
+
+// Render 渲染单个标签图片
+func Render(label Label, opts Options) (image.Image, error) {
+	width, height := toPixels(opts.Size.WidthMM), toPixels(opts.Size.HeightMM)
+	padding := height / 16
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	switch opts.Code {
+	case CodeCode128:
+		// 条形码编码资产编号，在上；编号、名称和部门在下方居中
+		barHeight := height * 45 / 100
+		code, err := encodeCode128(label.AssetNo, width-2*padding, barHeight)
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(img, code.Bounds().Add(image.Pt((width-code.Bounds().Dx())/2, padding)), code, image.Point{}, draw.Src)
+
+		lineHeight := (height - barHeight - 2*padding) / 3
+		top := padding + barHeight
+		drawText(img, label.AssetNo, boldFont(), lineHeight, padding, top, width-2*padding, true)
+		drawText(img, label.Name, regularFont(), lineHeight, padding, top+lineHeight, width-2*padding, true)
+		drawText(img, label.Department, regularFont(), lineHeight, padding, top+2*lineHeight, width-2*padding, true)
+	default:
+		// 二维码在左上，名称和部门在右，资产编号横跨底部
+		lineHeight := (height - 2*padding) / 5
+		side := height - 2*padding - lineHeight
+		if side > width*45/100 {
+			side = width * 45 / 100
+		}
+		code, err := encodeQR(label.Payload, side)
+		if err != nil {
+			return nil, err
+		}
+		offset := (side - code.Bounds().Dx()) / 2
+		draw.Draw(img, code.Bounds().Add(image.Pt(padding+offset, padding+offset)), code, image.Point{}, draw.Src)
+
+		left := 2*padding + side
+		textWidth := width - left - padding
+		top := padding + side/2 - lineHeight
+		drawText(img, label.Name, boldFont(), lineHeight, left, top, textWidth, false)
+		drawText(img, label.Department, regularFont(), lineHeight, left, top+lineHeight, textWidth, false)
+		drawText(img, label.AssetNo, boldFont(), lineHeight, padding, height-padding-lineHeight, width-2*padding, true)
+	}
+
+	return img, nil
+}
+
+// WritePNG 渲染单个标签并输出 PNG
+func WritePNG(w io.Writer, label Label, opts Options) error {
+	img, err := Render(label, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSheet 将多个标签排版到 A4 纸并输出 PDF
+func WriteSheet(w io.Writer, labels []Label, opts Options) error {
+	columns := int((sheetWidth - 2*sheetMargin + sheetSpacing) / (opts.Size.WidthMM + sheetSpacing))
+	rows := int((sheetHeight - 2*sheetMargin + sheetSpacing) / (opts.Size.HeightMM + sheetSpacing))
+	if columns < 1 || rows < 1 {
+		return fmt.Errorf("标签尺寸超出 A4 纸可打印范围")
+	}
+	perPage := columns * rows
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.SetLineWidth(0.1)
+
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		img, err := Render(label, opts)
+		if err != nil {
+			return fmt.Errorf("资产 %s: %v", label.AssetNo, err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+
+		name := "label-" + strconv.Itoa(i)
+		pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, &buf)
+
+		position := i % perPage
+		x := sheetMargin + float64(position%columns)*(opts.Size.WidthMM+sheetSpacing)
+		y := sheetMargin + float64(position/columns)*(opts.Size.HeightMM+sheetSpacing)
+		pdf.ImageOptions(name, x, y, opts.Size.WidthMM, opts.Size.HeightMM, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		// 裁切参考线
+		pdf.Rect(x, y, opts.Size.WidthMM, opts.Size.HeightMM, "D")
+	}
+
+	return pdf.Output(w)
+}
+
+// encodeQR 生成不超过 side 像素的二维码
+func encodeQR(content string, side int) (barcode.Barcode, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("生成二维码失败: %v", err)
+	}
+	modules := code.Bounds().Dx()
+	if side < modules {
+		return nil, fmt.Errorf("标签尺寸过小，无法容纳二维码")
+	}
+	return barcode.Scale(code, side/modules*modules, side/modules*modules)
+}
+
+// encodeCode128 生成宽度不超过 width 像素的 Code128 条形码
+func encodeCode128(content string, width, height int) (barcode.Barcode, error) {
+	for _, r := range content {
+		if r > 127 {
+			return nil, ErrCode128Charset
+		}
+	}
+	code, err := code128.Encode(content)
+	if err != nil {
+		return nil, fmt.Errorf("生成条形码失败: %v", err)
+	}
+	modules := code.Bounds().Dx()
+	if width < modules {
+		return nil, fmt.Errorf("标签尺寸过小，无法容纳条形码")
+	}
+	return barcode.Scale(code, width/modules*modules, height)
+}
+
+// drawText 在 (left, top) 开始的一行内绘制文本，超出宽度时先缩小字号，仍放不下再截断并加省略号
+func drawText(img draw.Image, text string, face *opentype.Font, lineHeight, left, top, maxWidth int, center bool) {
+	if text == "" || face == nil {
+		return
+	}
+
+	size := float64(lineHeight) * 0.75
+	var fontFace font.Face
+	var drawer *font.Drawer
+	for {
+		var err error
+		fontFace, err = opentype.NewFace(face, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return
+		}
+		drawer = &font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: fontFace}
+		if drawer.MeasureString(text).Ceil() <= maxWidth || size <= float64(lineHeight)*minFontScale {
+			break
+		}
+		fontFace.Close()
+		size *= 0.9
+	}
+	defer fontFace.Close()
+	text = truncate(drawer, text, maxWidth)
+
+	x := left
+	if center {
+		x += (maxWidth - drawer.MeasureString(text).Ceil()) / 2
+	}
+	ascent := fontFace.Metrics().Ascent.Ceil()
+	descent := fontFace.Metrics().Descent.Ceil()
+	drawer.Dot = fixed.P(x, top+(lineHeight+ascent-descent)/2)
+	drawer.DrawString(text)
+}
+
+// truncate 截断文本使其宽度不超过 maxWidth
+func truncate(drawer *font.Drawer, text string, maxWidth int) string {
+	if drawer.MeasureString(text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "…"
+		if drawer.MeasureString(candidate).Ceil() <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// toPixels 毫米转换为像素
+func toPixels(mm float64) int {
+	return int(mm / mmPerInch * dpi)
+}
+
+var (
+	fontsOnce   sync.Once
+	fontRegular *opentype.Font
+	fontBold    *opentype.Font
+	fontErr     error
+)
+
+// loadFonts 加载标签字体，配置了 LABEL_FONT_PATH 时正文和标题都使用该字体，否则使用内置字体（不含中文字形）
+func loadFonts() {
+	fontsOnce.Do(func() {
+		fontRegular, _ = opentype.Parse(goregular.TTF)
+		fontBold, _ = opentype.Parse(gobold.TTF)
+		if global.AppConfig == nil || global.AppConfig.LabelFontPath == "" {
+			fontErr = errors.New("未配置 LABEL_FONT_PATH，内置字体不含中文字形")
+			return
+		}
+		data, err := os.ReadFile(global.AppConfig.LabelFontPath)
+		if err != nil {
+			fontErr = fmt.Errorf("加载标签字体失败: %v", err)
+			return
+		}
+		custom, err := parseFont(data)
+		if err != nil {
+			fontErr = fmt.Errorf("解析标签字体失败: %v", err)
+			return
+		}
+		fontRegular, fontBold = custom, custom
+		if index, err := custom.GlyphIndex(nil, '资'); err != nil || index == 0 {
+			fontErr = fmt.Errorf("标签字体 %s 不含中文字形", global.AppConfig.LabelFontPath)
+		}
+	})
+}
+
+// CheckFont 检查标签字体是否支持中文，未配置、加载失败或缺少中文字形时返回原因，此时标签中的中文显示为方框
+func CheckFont() error {
+	loadFonts()
+	return fontErr
+}
+
+// parseFont 解析 TTF/OTF 字体，字体集合（TTC/OTC）取第一个字体
+func parseFont(data []byte) (*opentype.Font, error) {
+	if collection, err := opentype.ParseCollection(data); err == nil && collection.NumFonts() > 0 {
+		return collection.Font(0)
+	}
+	return opentype.Parse(data)
+}
+
+// regularFont 正文字体
+func regularFont() *opentype.Font {
+	loadFonts()
+	return fontRegular
+}
+
+// boldFont 标题字体
+func boldFont() *opentype.Font {
+	loadFonts()
+	return fontBold
+}
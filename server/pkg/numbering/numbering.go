@@ -0,0 +1,318 @@
+package numbering
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 编号规则配置键，对应 system_configs.config_key
+const (
+	ConfigKeyPrefix  = "asset_no_prefix"  // 编号前缀，对应 {PREFIX}
+	ConfigKeyPattern = "asset_no_pattern" // 全局编号规则，分类规则键为 asset_no_pattern.分类编码
+)
+
+// 内置默认值，未配置时使用
+const (
+	DefaultPrefix  = "ASSET"
+	DefaultPattern = "{PREFIX}-{CATEGORY}-{YYYY}-{SEQ:4}"
+)
+
+const (
+	defaultSeqWidth  = 4   // 流水号默认位数
+	maxSeqWidth      = 12  // 流水号最大位数
+	maxPatternLength = 100 // 与资产编号长度上限一致
+	maxAttempts      = 1000
+)
+
+// tokenPattern 编号规则占位符，如 {CATEGORY}、{SEQ:5}
+var tokenPattern = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+
+// ErrDepartmentRequired 编号规则包含部门编码但资产未指定部门
+var ErrDepartmentRequired = errors.New("编号规则包含部门编码，请指定部门")
+
+// ErrSequenceExhausted 流水号已用尽
+var ErrSequenceExhausted = errors.New("没有可用的资产编号，请调整编号规则")
+
+// Context 生成编号所需的资产信息
+type Context struct {
+	Category   *models.Category
+	Department *models.Department // 未指定部门时为空
+	Date       time.Time          // 为空时取当前时间
+}
+
+// Rule 编号规则
+type Rule struct {
+	ConfigKey    string `json:"config_key"`
+	CategoryCode string `json:"category_code,omitempty"` // 为空表示全局规则
+	Pattern      string `json:"pattern"`
+}
+
+// PatternKey 获取分类编号规则的配置键
+func PatternKey(categoryCode string) string {
+	return ConfigKeyPattern + "." + categoryCode
+}
+
+// CheckPattern 校验编号规则：只能使用支持的占位符，且必须包含一个流水号
+func CheckPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("编号规则不能为空")
+	}
+	if len(pattern) > maxPatternLength {
+		return fmt.Errorf("编号规则不能超过%d个字符", maxPatternLength)
+	}
+
+	seqCount := 0
+	for _, match := range tokenPattern.FindAllStringSubmatch(pattern, -1) {
+		switch match[1] {
+		case "SEQ":
+			seqCount++
+			if match[2] != "" {
+				width, _ := strconv.Atoi(match[2])
+				if width < 1 || width > maxSeqWidth {
+					return fmt.Errorf("流水号位数须在1到%d之间", maxSeqWidth)
+				}
+			}
+		case "PREFIX", "CATEGORY", "DEPT", "YYYY", "YY", "MM":
+			if match[2] != "" {
+				return fmt.Errorf("占位符 {%s} 不支持位数", match[1])
+			}
+		default:
+			return fmt.Errorf("不支持的占位符: {%s}", match[1])
+		}
+	}
+	if seqCount != 1 {
+		return fmt.Errorf("编号规则必须包含且只能包含一个流水号 {SEQ} 或 {SEQ:位数}")
+	}
+	if strings.ContainsAny(tokenPattern.ReplaceAllString(pattern, ""), "{}") {
+		return fmt.Errorf("编号规则中存在不完整的占位符")
+	}
+	return nil
+}
+
+// ResolvePattern 获取分类适用的编号规则，按 分类 -> 上级分类 -> 全局配置 -> 内置默认规则 的顺序取值
+func ResolvePattern(db *gorm.DB, category *models.Category) (string, error) {
+	current := category
+	for depth := 0; current != nil && depth < 20; depth++ {
+		pattern, err := models.GetSystemConfigValue(db, PatternKey(current.Code))
+		if err != nil {
+			return "", err
+		}
+		if pattern != "" {
+			return pattern, nil
+		}
+		if current.ParentID == nil {
+			break
+		}
+		var parent models.Category
+		if err := db.First(&parent, *current.ParentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				break
+			}
+			return "", err
+		}
+		current = &parent
+	}
+
+	pattern, err := models.GetSystemConfigValue(db, ConfigKeyPattern)
+	if err != nil {
+		return "", err
+	}
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	return pattern, nil
+}
+
+// Rules 获取全局和各分类单独配置的编号规则
+func Rules(db *gorm.DB) ([]Rule, error) {
+	var configs []models.SystemConfig
+	if err := db.Where("config_key = ? OR config_key LIKE ?", ConfigKeyPattern, ConfigKeyPattern+".%").
+		Order("config_key").
+		Find(&configs).Error; err != nil {
+		return nil, err
+	}
+
+	rules := []Rule{{ConfigKey: ConfigKeyPattern, Pattern: DefaultPattern}}
+	for _, config := range configs {
+		if config.ConfigKey == ConfigKeyPattern {
+			if config.ConfigValue != "" {
+				rules[0].Pattern = config.ConfigValue
+			}
+			continue
+		}
+		if config.ConfigValue == "" {
+			continue
+		}
+		rules = append(rules, Rule{
+			ConfigKey:    config.ConfigKey,
+			CategoryCode: strings.TrimPrefix(config.ConfigKey, ConfigKeyPattern+"."),
+			Pattern:      config.ConfigValue,
+		})
+	}
+	return rules, nil
+}
+
+// SaveRule 保存编号规则，categoryCode 为空时保存全局规则，pattern 为空时删除分类规则
+func SaveRule(db *gorm.DB, categoryCode, pattern string) error {
+	key, description := ConfigKeyPattern, "资产编号规则"
+	if categoryCode != "" {
+		key, description = PatternKey(categoryCode), "分类 "+categoryCode+" 的资产编号规则"
+	}
+
+	if pattern == "" {
+		if categoryCode == "" {
+			return fmt.Errorf("全局编号规则不能为空")
+		}
+		return db.Where("config_key = ?", key).Delete(&models.SystemConfig{}).Error
+	}
+	if err := CheckPattern(pattern); err != nil {
+		return err
+	}
+
+	config := models.SystemConfig{ConfigKey: key, ConfigValue: pattern, Description: description}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "config_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"config_value", "description", "updated_at", "deleted_at"}),
+	}).Create(&config).Error
+}
+
+// Next 分配下一个资产编号，流水号递增并跳过已被手工占用的编号
+// 须在调用方的写事务中调用，流水号随调用方事务一起提交或回滚，并发分配由事务的写锁串行化
+func Next(tx *gorm.DB, ctx Context) (string, error) {
+	seqKey, width, err := prepare(tx, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sequence := models.AssetNoSequence{SeqKey: seqKey, Value: 1}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "seq_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":      gorm.Expr("value + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&sequence).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Where("seq_key = ?", seqKey).First(&sequence).Error; err != nil {
+		return "", err
+	}
+
+	value, err := firstFree(tx, seqKey, width, sequence.Value)
+	if err != nil {
+		return "", err
+	}
+	if value != sequence.Value {
+		if err := tx.Model(&sequence).Update("value", value).Error; err != nil {
+			return "", err
+		}
+	}
+	return format(seqKey, width, value), nil
+}
+
+// Preview 预览下一个资产编号，不占用流水号
+func Preview(db *gorm.DB, ctx Context) (string, error) {
+	seqKey, width, err := prepare(db, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sequence models.AssetNoSequence
+	if err := db.Where("seq_key = ?", seqKey).First(&sequence).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+
+	value, err := firstFree(db, seqKey, width, sequence.Value+1)
+	if err != nil {
+		return "", err
+	}
+	return format(seqKey, width, value), nil
+}
+
+// prepare 解析编号规则并替换流水号以外的占位符，返回编号范围和流水号位数
+func prepare(db *gorm.DB, ctx Context) (string, int, error) {
+	pattern, err := ResolvePattern(db, ctx.Category)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := CheckPattern(pattern); err != nil {
+		return "", 0, fmt.Errorf("编号规则配置错误: %v", err)
+	}
+
+	prefix, err := models.GetSystemConfigValue(db, ConfigKeyPrefix)
+	if err != nil {
+		return "", 0, err
+	}
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	date := ctx.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	width := defaultSeqWidth
+	var renderErr error
+	seqKey := tokenPattern.ReplaceAllStringFunc(pattern, func(token string) string {
+		match := tokenPattern.FindStringSubmatch(token)
+		switch match[1] {
+		case "SEQ":
+			if match[2] != "" {
+				width, _ = strconv.Atoi(match[2])
+			}
+			return "{SEQ}"
+		case "PREFIX":
+			return prefix
+		case "CATEGORY":
+			return ctx.Category.Code
+		case "DEPT":
+			if ctx.Department == nil {
+				renderErr = ErrDepartmentRequired
+				return ""
+			}
+			return ctx.Department.Code
+		case "YYYY":
+			return date.Format("2006")
+		case "YY":
+			return date.Format("06")
+		case "MM":
+			return date.Format("01")
+		}
+		return token
+	})
+	if renderErr != nil {
+		return "", 0, renderErr
+	}
+	return seqKey, width, nil
+}
+
+// firstFree 从 value 开始查找第一个未被占用的流水号（含已删除资产）
+func firstFree(db *gorm.DB, seqKey string, width int, value int64) (int64, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		assetNo := format(seqKey, width, value)
+		var count int64
+		if err := db.Unscoped().Model(&models.Asset{}).Where("asset_no = ?", assetNo).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		if count == 0 {
+			return value, nil
+		}
+		value++
+	}
+	return 0, ErrSequenceExhausted
+}
+
+// format 生成资产编号
+func format(seqKey string, width int, value int64) string {
+	return strings.Replace(seqKey, "{SEQ}", fmt.Sprintf("%0*d", width, value), 1)
+}
@@ -0,0 +1,32 @@
+package reservations
+
+import (
+	"log"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// expireInterval 过期检查的执行间隔
+const expireInterval = 15 * time.Minute
+
+// StartExpirer 启动后台定时任务，将超过取用截止时间仍未取用的预约置为已过期
+// 有效预约的判断本身已按时间过滤，定时任务只负责更新状态便于查询统计
+func StartExpirer(db *gorm.DB, grace time.Duration) {
+	go func() {
+		ticker := time.NewTicker(expireInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			expired, err := models.ExpireReservations(db, grace)
+			if err != nil {
+				log.Printf("更新过期预约失败: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("已将 %d 条未取用的预约置为过期", expired)
+			}
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// 高亮标记
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+const (
+	snippetRadius = 30 // 摘要在命中位置前后保留的字符数
+	snippetLength = 80 // 内容未命中时摘要的长度
+)
+
+// Highlight 转义 HTML 并以 <mark> 标记命中的关键词（不区分大小写）
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	return markRanges(runes, matchRanges(runes, terms), 0, len(runes))
+}
+
+// Snippet 截取内容中第一个命中位置附近的文字作为摘要，多行内容以 " · " 连接
+func Snippet(content string, terms []string) string {
+	runes := []rune(strings.ReplaceAll(content, "\n", " · "))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	ranges := matchRanges(runes, terms)
+	start, end := 0, min(len(runes), snippetLength)
+	if len(ranges) > 0 {
+		start = max(0, ranges[0][0]-snippetRadius)
+		end = min(len(runes), ranges[0][1]+snippetRadius)
+	}
+
+	snippet := markRanges(runes, ranges, start, end)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchRanges 查找关键词在文本中出现的位置（按字符计），重叠的位置合并
+func matchRanges(runes []rune, terms []string) [][2]int {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matched := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := range needle {
+			needle[i] = unicode.ToLower(needle[i])
+		}
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if slices.Equal(lower[i:i+len(needle)], needle) {
+				for j := i; j < i+len(needle); j++ {
+					matched[j] = true
+				}
+			}
+		}
+	}
+
+	var ranges [][2]int
+	for i := 0; i < len(matched); i++ {
+		if !matched[i] {
+			continue
+		}
+		j := i
+		for j < len(matched) && matched[j] {
+			j++
+		}
+		ranges = append(ranges, [2]int{i, j})
+		i = j
+	}
+	return ranges
+}
+
+// markRanges 输出 [start, end) 范围内的文字，命中部分以 <mark> 包裹
+func markRanges(runes []rune, ranges [][2]int, start, end int) string {
+	var builder strings.Builder
+	position := start
+	for _, r := range ranges {
+		from, to := max(r[0], start), min(r[1], end)
+		if from >= to {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(runes[position:from])))
+		builder.WriteString(markOpen)
+		builder.WriteString(html.EscapeString(string(runes[from:to])))
+		builder.WriteString(markClose)
+		position = to
+	}
+	builder.WriteString(html.EscapeString(string(runes[position:end])))
+	return builder.String()
+}
@@ -0,0 +1,277 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// 检索引擎名称
+const (
+	EngineFTS5 = "fts5" // SQLite FTS5 全文索引（trigram 分词）
+	EngineLike = "like" // 通用 LIKE 匹配，用于不支持 FTS5 的数据库
+)
+
+const (
+	maxTerms      = 8   // 单次查询最多使用的关键词数量
+	maxTermLength = 100 // 单个关键词最大长度
+	trigramLength = 3   // trigram 分词可索引的最短关键词长度
+)
+
+// ftsStatements 创建 FTS5 外部内容表及同步触发器，索引内容随 search_documents 自动更新
+var ftsStatements = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS search_fts USING fts5(title, subtitle, content, content='search_documents', content_rowid='id', tokenize='trigram')`,
+	`CREATE TRIGGER IF NOT EXISTS search_documents_ai AFTER INSERT ON search_documents BEGIN
+		INSERT INTO search_fts(rowid, title, subtitle, content) VALUES (new.id, new.title, new.subtitle, new.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS search_documents_ad AFTER DELETE ON search_documents BEGIN
+		INSERT INTO search_fts(search_fts, rowid, title, subtitle, content) VALUES ('delete', old.id, old.title, old.subtitle, old.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS search_documents_au AFTER UPDATE ON search_documents BEGIN
+		INSERT INTO search_fts(search_fts, rowid, title, subtitle, content) VALUES ('delete', old.id, old.title, old.subtitle, old.content);
+		INSERT INTO search_fts(rowid, title, subtitle, content) VALUES (new.id, new.title, new.subtitle, new.content);
+	END`,
+}
+
+// ftsTriggers FTS5 同步触发器
+var ftsTriggers = []string{"search_documents_ai", "search_documents_ad", "search_documents_au"}
+
+// engine 当前使用的检索引擎，Init 之前为通用 LIKE 匹配
+var engine = EngineLike
+
+// Query 检索条件
+type Query struct {
+	Keyword string
+	Kinds   []string // 为空时检索全部类型
+	Offset  int
+	Limit   int
+}
+
+// Hit 检索结果
+type Hit struct {
+	Kind      string    `json:"kind"`       // 文档类型：asset、borrow、category
+	ID        uint      `json:"id"`         // 来源记录ID
+	Title     string    `json:"title"`      // 标题，命中部分以 <mark> 标记
+	Subtitle  string    `json:"subtitle"`   // 副标题，命中部分以 <mark> 标记
+	Snippet   string    `json:"snippet"`    // 内容摘要，命中部分以 <mark> 标记
+	Score     float64   `json:"score"`      // 相关度，越大越相关
+	UpdatedAt time.Time `json:"updated_at"` // 文档更新时间
+}
+
+// Result 检索结果列表
+type Result struct {
+	Engine string `json:"engine"`
+	Total  int64  `json:"total"`
+	Hits   []Hit  `json:"hits"`
+}
+
+// hitRow 检索查询的结果行
+type hitRow struct {
+	Kind      string
+	RefID     uint
+	Title     string
+	Subtitle  string
+	Content   string
+	Score     float64
+	UpdatedAt time.Time
+}
+
+// Prepare 在数据库迁移前调用：运行环境未编译 FTS5 时移除此前建立的同步触发器，
+// 否则写入 search_documents 时会因找不到 fts5 模块而失败
+func Prepare(db *gorm.DB) error {
+	if db.Dialector.Name() != "sqlite" {
+		return nil
+	}
+	available, err := ftsAvailable(db)
+	if err != nil || available {
+		return err
+	}
+	for _, trigger := range ftsTriggers {
+		if err := db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Init 在数据库迁移后调用：SQLite 支持 FTS5 时建立全文索引，否则使用 LIKE 匹配；
+// 检索文档为空时按现有数据建立索引
+func Init(db *gorm.DB) error {
+	engine = EngineLike
+	if db.Dialector.Name() == "sqlite" {
+		available, err := ftsAvailable(db)
+		if err != nil {
+			return err
+		}
+		if available {
+			if err := initFTS(db); err != nil {
+				return err
+			}
+			engine = EngineFTS5
+		}
+	}
+
+	var count int64
+	if err := db.Model(&models.SearchDocument{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return models.RebuildSearchDocuments(db)
+	}
+	return nil
+}
+
+// Engine 获取当前使用的检索引擎名称
+func Engine() string {
+	return engine
+}
+
+// Rebuild 按来源数据重建全部检索文档
+func Rebuild(db *gorm.DB) error {
+	return models.RebuildSearchDocuments(db)
+}
+
+// ftsAvailable 判断 SQLite 是否编译了 FTS5（go-sqlite3 需使用 sqlite_fts5 构建标签）
+func ftsAvailable(db *gorm.DB) (bool, error) {
+	var enabled int
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error; err != nil {
+		return false, err
+	}
+	return enabled == 1, nil
+}
+
+// initFTS 创建 FTS5 索引和同步触发器
+func initFTS(db *gorm.DB) error {
+	var existing int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ?", ftsTriggers).Scan(&existing).Error; err != nil {
+		return err
+	}
+
+	for _, statement := range ftsStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("创建全文索引失败: %v", err)
+		}
+	}
+
+	// 此前未同步过（新建索引或曾回退到 LIKE 匹配）时按检索文档重建索引
+	if existing < int64(len(ftsTriggers)) {
+		if err := db.Exec("INSERT INTO search_fts(search_fts) VALUES ('rebuild')").Error; err != nil {
+			return fmt.Errorf("重建全文索引失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// Search 检索文档并按相关度排序，db 可携带数据范围
+func Search(db *gorm.DB, q Query) (*Result, error) {
+	terms := splitTerms(q.Keyword)
+	result := &Result{Engine: engine, Hits: make([]Hit, 0)}
+	if len(terms) == 0 {
+		return result, nil
+	}
+
+	query := db.Table("search_documents AS d")
+	if len(q.Kinds) > 0 {
+		query = query.Where("d.kind IN ?", q.Kinds)
+	}
+
+	// trigram 索引只能匹配不少于3个字符的关键词，较短的关键词按 LIKE 匹配
+	var indexed []string
+	for _, term := range terms {
+		if engine == EngineFTS5 && utf8.RuneCountInString(term) >= trigramLength {
+			indexed = append(indexed, term)
+			continue
+		}
+		pattern := likePattern(term)
+		query = query.Where("(d.title LIKE ? ESCAPE '\\' OR d.subtitle LIKE ? ESCAPE '\\' OR d.content LIKE ? ESCAPE '\\')", pattern, pattern, pattern)
+	}
+
+	var score string
+	var scoreVars []interface{}
+	if len(indexed) > 0 {
+		query = query.Joins("JOIN search_fts ON search_fts.rowid = d.id").Where("search_fts MATCH ?", matchExpression(indexed))
+		score = "-bm25(search_fts, 10.0, 5.0, 1.0)"
+	} else {
+		score, scoreVars = likeScore(terms)
+	}
+
+	if err := query.Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []hitRow
+	if err := query.
+		Select("d.kind, d.ref_id, d.title, d.subtitle, d.content, d.updated_at, "+score+" AS score", scoreVars...).
+		Order("score DESC, d.updated_at DESC").
+		Offset(q.Offset).
+		Limit(q.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result.Hits = append(result.Hits, Hit{
+			Kind:      row.Kind,
+			ID:        row.RefID,
+			Title:     Highlight(row.Title, terms),
+			Subtitle:  Highlight(row.Subtitle, terms),
+			Snippet:   Snippet(row.Content, terms),
+			Score:     row.Score,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// splitTerms 按空白拆分关键词，去重并限制数量和长度
+func splitTerms(keyword string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.Fields(keyword) {
+		if runes := []rune(term); len(runes) > maxTermLength {
+			term = string(runes[:maxTermLength])
+		}
+		key := strings.ToLower(term)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, term)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// matchExpression 生成 FTS5 查询表达式，每个关键词作为短语匹配，全部关键词都须命中
+func matchExpression(terms []string) string {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(phrases, " AND ")
+}
+
+// likePattern 生成包含匹配的 LIKE 模式，转义通配符
+func likePattern(term string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(term) + "%"
+}
+
+// likeScore LIKE 匹配的相关度：关键词出现在标题、副标题、内容中分别计 10、5、1 分
+func likeScore(terms []string) (string, []interface{}) {
+	parts := make([]string, len(terms))
+	var vars []interface{}
+	for i, term := range terms {
+		pattern := likePattern(term)
+		parts[i] = "(CASE WHEN d.title LIKE ? ESCAPE '\\' THEN 10 WHEN d.subtitle LIKE ? ESCAPE '\\' THEN 5 ELSE 1 END)"
+		vars = append(vars, pattern, pattern)
+	}
+	return "(" + strings.Join(parts, " + ") + ")", vars
+}
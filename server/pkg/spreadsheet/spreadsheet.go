@@ -0,0 +1,153 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// Extensions 支持读取的文件扩展名
+var Extensions = []string{".xlsx", ".xlsm", ".csv"}
+
+// ErrUnsupportedFormat 不支持的文件格式
+var ErrUnsupportedFormat = errors.New("不支持的文件格式，请上传 .xlsx 或 .csv 文件")
+
+// maxExcelSerial Excel 日期序列号上限（9999-12-31）
+const maxExcelSerial = 2958465
+
+// dateLayouts 文本日期可接受的格式
+var dateLayouts = []string{
+	"2006-01-02", "2006/01/02", "2006-1-2", "2006/1/2", "2006.1.2", "2006年1月2日",
+	"2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006/1/2 15:04", time.RFC3339, "20060102",
+}
+
+// Read 读取表格文件的全部行，按文件名后缀区分 xlsx 和 csv
+// xlsx 读取指定工作表（为空时读取当前活动工作表），单元格取原始值，日期为 Excel 序列号
+func Read(filename string, r io.Reader, sheet string) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx", ".xlsm":
+		rows, err = readExcel(r, sheet)
+	case ".csv":
+		rows, err = readCSV(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+	return rows, nil
+}
+
+// ColumnName 获取列序号（从0开始）对应的列名，如 0 -> A
+func ColumnName(index int) string {
+	name, _ := excelize.ColumnNumberToName(index + 1)
+	return name
+}
+
+// ColumnIndex 解析列名（如 A、AB），返回从0开始的列序号
+func ColumnIndex(name string) (int, bool) {
+	if name == "" || len(name) > 3 {
+		return 0, false
+	}
+	for _, r := range name {
+		if r < 'A' || r > 'Z' {
+			return 0, false
+		}
+	}
+	number, err := excelize.ColumnNameToNumber(name)
+	if err != nil {
+		return 0, false
+	}
+	return number - 1, true
+}
+
+// ParseDate 解析日期单元格，支持 Excel 日期序列号和常见文本格式
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 && serial <= maxExcelSerial {
+		date, err := excelize.ExcelDateToTime(serial, false)
+		if err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), nil
+		}
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法识别的日期: %s", value)
+}
+
+// ParseNumber 解析数字单元格，忽略千分位和货币符号
+func ParseNumber(value string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", "，", "", "¥", "", "￥", "", "$", "", " ", "").Replace(value)
+	number, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法识别的数字: %s", value)
+	}
+	return number, nil
+}
+
+// readExcel 读取 xlsx 工作表
+func readExcel(r io.Reader, sheet string) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 Excel 文件: %v", err)
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheet = f.GetSheetName(f.GetActiveSheetIndex())
+	} else if index, err := f.GetSheetIndex(sheet); err != nil || index < 0 {
+		return nil, fmt.Errorf("工作表不存在: %s", sheet)
+	}
+
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("无法读取工作表 %s: %v", sheet, err)
+	}
+	return rows, nil
+}
+
+// readCSV 读取 csv 文件，兼容带 BOM 的 UTF-8 和 Excel 默认保存的 GBK 编码
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		decoded, err := simplifiedchinese.GB18030.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("无法识别 CSV 文件编码，请保存为 UTF-8 格式")
+		}
+		data = decoded
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 CSV 文件: %v", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,159 @@
+package trash
+
+import (
+	"strings"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// purgeAsset 彻底删除资产及其借用、预约、盘点、维修、调拨记录和附件；有处置记录的资产须保留以备审计
+func purgeAsset(tx *gorm.DB, id uint) error {
+	disposed, err := anyExists(tx, &models.AssetDisposal{}, "asset_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if !disposed {
+		disposed, err = anyExists(tx, &models.AssetWriteOff{}, "asset_id = ?", id)
+		if err != nil {
+			return err
+		}
+	}
+	if disposed {
+		return conflict("资产有处置记录，不能彻底删除")
+	}
+	referenced, err := anyExists(tx, &models.Asset{}, "parent_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return conflict("资产仍挂载有组件，不能彻底删除")
+	}
+
+	var borrowIDs []uint
+	if err := tx.Unscoped().Model(&models.BorrowRecord{}).Where("asset_id = ?", id).Pluck("id", &borrowIDs).Error; err != nil {
+		return err
+	}
+	for _, borrowID := range borrowIDs {
+		if err := models.RemoveSearchDocument(tx, models.SearchKindBorrow, borrowID); err != nil {
+			return err
+		}
+	}
+
+	// 随整机借用、盘点的组件记录保留，只解除与整机记录的关联
+	if err := tx.Unscoped().Model(&models.BorrowRecord{}).
+		Where("parent_record_id IN (?)", tx.Unscoped().Model(&models.BorrowRecord{}).Select("id").Where("asset_id = ?", id)).
+		UpdateColumn("parent_record_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.InventoryRecord{}).
+		Where("kit_record_id IN (?)", tx.Unscoped().Model(&models.InventoryRecord{}).Select("id").Where("asset_id = ?", id)).
+		UpdateColumn("kit_record_id", nil).Error; err != nil {
+		return err
+	}
+
+	related := []interface{}{
+		&models.Attachment{},
+		&models.InventoryRecord{},
+		&models.BorrowRecord{},
+		&models.MaintenanceOrder{},
+		&models.AssetTransfer{},
+		&models.AssetReservation{},
+		&models.LicenseSeat{},
+	}
+	for _, model := range related {
+		if err := tx.Unscoped().Where("asset_id = ?", id).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("parent_id = ? OR component_id = ?", id, id).Delete(&models.AssetComponentLog{}).Error; err != nil {
+		return err
+	}
+	if err := models.RemoveSearchDocument(tx, models.SearchKindAsset, id); err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.Asset{}, id).Error
+}
+
+// purgeCategory 彻底删除分类，仍被资产或子分类（含回收站中的记录）引用时拒绝
+func purgeCategory(tx *gorm.DB, id uint) error {
+	referenced, err := anyExists(tx, &models.Asset{}, "category_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return conflict("分类仍被资产引用（含回收站中的资产），不能彻底删除")
+	}
+	referenced, err = anyExists(tx, &models.Category{}, "parent_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return conflict("分类仍有子分类（含回收站中的分类），不能彻底删除")
+	}
+
+	if err := models.RemoveSearchDocument(tx, models.SearchKindCategory, id); err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.Category{}, id).Error
+}
+
+// departmentReferences 引用部门的记录及说明
+var departmentReferences = []struct {
+	model interface{}
+	query string
+	label string
+}{
+	{&models.Asset{}, "department_id = ?", "资产"},
+	{&models.Department{}, "parent_id = ?", "子部门"},
+	{&models.User{}, "department_id = ?", "用户"},
+	{&models.BorrowRecord{}, "department_id = ?", "借用记录"},
+	{&models.AssetTransfer{}, "from_department_id = ? OR to_department_id = ?", "调拨记录"},
+	{&models.AssetReservation{}, "department_id = ?", "预约记录"},
+	{&models.InventoryTask{}, "department_id = ?", "盘点任务"},
+	{&models.AssetWriteOff{}, "department_id = ?", "核销记录"},
+}
+
+// purgeDepartment 彻底删除部门，仍被其他记录（含回收站中的记录）引用时拒绝
+func purgeDepartment(tx *gorm.DB, id uint) error {
+	for _, reference := range departmentReferences {
+		args := make([]interface{}, strings.Count(reference.query, "?"))
+		for i := range args {
+			args[i] = id
+		}
+		referenced, err := anyExists(tx, reference.model, reference.query, args...)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return conflict("部门仍被%s引用（含回收站中的记录），不能彻底删除", reference.label)
+		}
+	}
+	return tx.Unscoped().Delete(&models.Department{}, id).Error
+}
+
+// purgeBorrow 彻底删除借用记录及其附件
+func purgeBorrow(tx *gorm.DB, id uint) error {
+	if err := tx.Where("owner_type = ? AND owner_id = ?", models.AttachmentOwnerBorrow, id).Delete(&models.Attachment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.BorrowRecord{}).Where("parent_record_id = ?", id).UpdateColumn("parent_record_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.AssetReservation{}).Where("borrow_record_id = ?", id).UpdateColumn("borrow_record_id", nil).Error; err != nil {
+		return err
+	}
+	if err := models.RemoveSearchDocument(tx, models.SearchKindBorrow, id); err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.BorrowRecord{}, id).Error
+}
+
+// purgeInventoryTask 彻底删除盘点任务及其盘点记录
+func purgeInventoryTask(tx *gorm.DB, id uint) error {
+	if err := tx.Unscoped().Where("task_id = ?", id).Delete(&models.InventoryRecord{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.InventoryTask{}, id).Error
+}
@@ -0,0 +1,152 @@
+package trash
+
+import (
+	"errors"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// restoreAsset 恢复资产：资产编号不能与现有资产重复，分类和部门须未删除
+func restoreAsset(tx *gorm.DB, id uint) error {
+	var asset models.Asset
+	if err := tx.Unscoped().First(&asset, id).Error; err != nil {
+		return err
+	}
+
+	duplicated, err := liveExists(tx, &models.Asset{}, "asset_no = ?", asset.AssetNo)
+	if err != nil {
+		return err
+	}
+	if duplicated {
+		return conflict("资产编号 %s 已被其他资产使用", asset.AssetNo)
+	}
+	if err := requireLive(tx, &models.Category{}, &asset.CategoryID, "所属分类已删除，请先恢复分类"); err != nil {
+		return err
+	}
+	if err := requireLive(tx, &models.Department{}, asset.DepartmentID, "所属部门已删除，请先恢复部门"); err != nil {
+		return err
+	}
+
+	return undelete(tx, &models.Asset{}, asset.ID, models.SearchKindAsset)
+}
+
+// restoreCategory 恢复分类：编码不能与现有分类重复，上级分类须未删除
+func restoreCategory(tx *gorm.DB, id uint) error {
+	var category models.Category
+	if err := tx.Unscoped().First(&category, id).Error; err != nil {
+		return err
+	}
+
+	duplicated, err := liveExists(tx, &models.Category{}, "code = ?", category.Code)
+	if err != nil {
+		return err
+	}
+	if duplicated {
+		return conflict("分类编码 %s 已被其他分类使用", category.Code)
+	}
+	if err := requireLive(tx, &models.Category{}, category.ParentID, "上级分类已删除，请先恢复上级分类"); err != nil {
+		return err
+	}
+
+	return undelete(tx, &models.Category{}, category.ID, models.SearchKindCategory)
+}
+
+// restoreDepartment 恢复部门：编码不能与现有部门重复，上级部门须未删除
+func restoreDepartment(tx *gorm.DB, id uint) error {
+	var department models.Department
+	if err := tx.Unscoped().First(&department, id).Error; err != nil {
+		return err
+	}
+
+	duplicated, err := liveExists(tx, &models.Department{}, "code = ?", department.Code)
+	if err != nil {
+		return err
+	}
+	if duplicated {
+		return conflict("部门编码 %s 已被其他部门使用", department.Code)
+	}
+	if err := requireLive(tx, &models.Department{}, department.ParentID, "上级部门已删除，请先恢复上级部门"); err != nil {
+		return err
+	}
+
+	return undelete(tx, &models.Department{}, department.ID, "")
+}
+
+// restoreBorrow 恢复借用记录：资产和部门须未删除；未归还的记录恢复后资产重新变为借用中，此时资产不能有其他未归还的借用
+func restoreBorrow(tx *gorm.DB, id uint) error {
+	var record models.BorrowRecord
+	if err := tx.Unscoped().First(&record, id).Error; err != nil {
+		return err
+	}
+
+	if err := requireLive(tx, &models.Asset{}, &record.AssetID, "借用的资产已删除，请先恢复资产"); err != nil {
+		return err
+	}
+	if err := requireLive(tx, &models.Department{}, record.DepartmentID, "借用部门已删除，请先恢复部门"); err != nil {
+		return err
+	}
+
+	open := record.Status == models.BorrowStatusBorrowed || record.Status == models.BorrowStatusOverdue
+	if open {
+		borrowed, err := liveExists(tx, &models.BorrowRecord{}, "asset_id = ? AND status IN ?",
+			record.AssetID, []models.BorrowStatus{models.BorrowStatusBorrowed, models.BorrowStatusOverdue})
+		if err != nil {
+			return err
+		}
+		if borrowed {
+			return conflict("资产已有其他未归还的借用记录")
+		}
+	}
+
+	if err := undelete(tx, &models.BorrowRecord{}, record.ID, models.SearchKindBorrow); err != nil {
+		return err
+	}
+	if !open {
+		return nil
+	}
+
+	err := models.ChangeAssetStatus(tx, record.AssetID, models.AssetStatusBorrowed)
+	var transitionErr *models.AssetStatusTransitionError
+	if errors.As(err, &transitionErr) {
+		return conflict("%s", transitionErr.Error())
+	}
+	return err
+}
+
+// restoreInventoryTask 恢复盘点任务及随任务删除的盘点记录，资产已删除的盘点记录留在回收站
+func restoreInventoryTask(tx *gorm.DB, id uint) error {
+	var task models.InventoryTask
+	if err := tx.Unscoped().First(&task, id).Error; err != nil {
+		return err
+	}
+
+	if err := requireLive(tx, &models.Department{}, task.DepartmentID, "任务所属部门已删除，请先恢复部门"); err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Model(&models.InventoryRecord{}).
+		Where("task_id = ? AND deleted_at IS NOT NULL", task.ID).
+		Where("asset_id IN (?)", tx.Model(&models.Asset{}).Select("id")).
+		UpdateColumn("deleted_at", nil).Error; err != nil {
+		return err
+	}
+
+	return undelete(tx, &models.InventoryTask{}, task.ID, "")
+}
+
+// requireLive 关联记录已删除时返回校验失败，id 为空时不校验
+func requireLive(tx *gorm.DB, model interface{}, id *uint, reason string) error {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	exists, err := liveExists(tx, model, "id = ?", *id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return conflict("%s", reason)
+	}
+	return nil
+}
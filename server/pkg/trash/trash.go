@@ -0,0 +1,354 @@
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// 回收站记录类型
+const (
+	TypeAsset         = "asset"          // 资产
+	TypeCategory      = "category"       // 分类
+	TypeDepartment    = "department"     // 部门
+	TypeBorrow        = "borrow"         // 借用记录
+	TypeInventoryTask = "inventory_task" // 盘点任务
+)
+
+// Types 全部回收站记录类型，按到期清除的顺序排列：先清除依附于资产的记录，再清除资产，最后清除分类和部门
+var Types = []string{TypeBorrow, TypeInventoryTask, TypeAsset, TypeCategory, TypeDepartment}
+
+// purgeInterval 到期清除的执行间隔
+const purgeInterval = 24 * time.Hour
+
+// ErrNotFound 回收站中没有该记录
+var ErrNotFound = errors.New("回收站中没有该记录")
+
+// ConflictError 恢复或彻底删除时的校验失败，Reason 说明原因
+type ConflictError struct {
+	Reason string
+}
+
+// Error 实现 error 接口
+func (e *ConflictError) Error() string {
+	return e.Reason
+}
+
+// conflict 生成校验失败错误
+func conflict(format string, args ...interface{}) error {
+	return &ConflictError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// kind 回收站记录类型定义
+type kind struct {
+	table    string                                       // 表名，同时用于查找删除操作日志
+	resource string                                       // 对应的权限路由分组
+	label    string                                       // 显示名称
+	selects  string                                       // 列表查询字段：id, name, code, deleted_at
+	restore  func(tx *gorm.DB, id uint) error             // 校验并恢复
+	purge    func(tx *gorm.DB, id uint) error             // 校验并彻底删除
+	model    func() interface{}                           // 模型实例
+	search   func(keyword string) (string, []interface{}) // 关键词条件
+}
+
+// kinds 回收站记录类型定义
+var kinds = map[string]kind{
+	TypeAsset: {
+		table:    "assets",
+		resource: "assets",
+		label:    "资产",
+		selects:  "id, name, asset_no AS code, deleted_at",
+		restore:  restoreAsset,
+		purge:    purgeAsset,
+		model:    func() interface{} { return &models.Asset{} },
+		search:   likeSearch("name", "asset_no"),
+	},
+	TypeCategory: {
+		table:    "categories",
+		resource: "categories",
+		label:    "分类",
+		selects:  "id, name, code, deleted_at",
+		restore:  restoreCategory,
+		purge:    purgeCategory,
+		model:    func() interface{} { return &models.Category{} },
+		search:   likeSearch("name", "code"),
+	},
+	TypeDepartment: {
+		table:    "departments",
+		resource: "departments",
+		label:    "部门",
+		selects:  "id, name, code, deleted_at",
+		restore:  restoreDepartment,
+		purge:    purgeDepartment,
+		model:    func() interface{} { return &models.Department{} },
+		search:   likeSearch("name", "code"),
+	},
+	TypeBorrow: {
+		table:    "borrow_records",
+		resource: "borrow",
+		label:    "借用记录",
+		selects:  "id, borrower_name AS name, (SELECT asset_no FROM assets WHERE assets.id = borrow_records.asset_id) AS code, deleted_at",
+		restore:  restoreBorrow,
+		purge:    purgeBorrow,
+		model:    func() interface{} { return &models.BorrowRecord{} },
+		search:   likeSearch("borrower_name"),
+	},
+	TypeInventoryTask: {
+		table:    "inventory_tasks",
+		resource: "inventory",
+		label:    "盘点任务",
+		selects:  "id, task_name AS name, '' AS code, deleted_at",
+		restore:  restoreInventoryTask,
+		purge:    purgeInventoryTask,
+		model:    func() interface{} { return &models.InventoryTask{} },
+		search:   likeSearch("task_name"),
+	},
+}
+
+// Item 回收站记录
+type Item struct {
+	Type      string     `json:"type"`
+	TypeLabel string     `json:"type_label" gorm:"-"`
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"` // 资产名称、分类或部门名称、借用人、盘点任务名称
+	Code      string     `json:"code"` // 资产编号、分类或部门编码，借用记录为所借资产编号
+	DeletedAt time.Time  `json:"deleted_at"`
+	DeletedBy string     `json:"deleted_by" gorm:"-"`           // 删除操作人，取自操作日志，批量删除时为空
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"-"` // 到期自动清除的时间，永久保留时为空
+}
+
+// ListOptions 回收站列表查询条件
+type ListOptions struct {
+	Types         []string // 记录类型，为空时不返回任何记录
+	Keyword       string
+	Page          int
+	PageSize      int
+	RetentionDays int
+}
+
+// IsValidType 判断回收站记录类型是否有效
+func IsValidType(recordType string) bool {
+	_, ok := kinds[recordType]
+	return ok
+}
+
+// Resource 获取记录类型对应的权限路由分组
+func Resource(recordType string) string {
+	return kinds[recordType].resource
+}
+
+// List 按删除时间倒序分页列出回收站记录
+func List(db *gorm.DB, opts ListOptions) ([]Item, int64, error) {
+	items := make([]Item, 0)
+	if len(opts.Types) == 0 {
+		return items, 0, nil
+	}
+
+	queries := make([]string, 0, len(opts.Types))
+	args := make([]interface{}, 0, len(opts.Types))
+	for _, recordType := range opts.Types {
+		k := kinds[recordType]
+		query := db.Unscoped().Model(k.model()).
+			Select("? AS type, "+k.selects, recordType).
+			Where("deleted_at IS NOT NULL")
+		if opts.Keyword != "" {
+			condition, vars := k.search(opts.Keyword)
+			query = query.Where(condition, vars...)
+		}
+		queries = append(queries, "?")
+		args = append(args, query)
+	}
+	union := db.Table("(?) AS trash", db.Raw(strings.Join(queries, " UNION ALL "), args...))
+
+	var total int64
+	if err := union.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := union.Session(&gorm.Session{}).
+		Order("deleted_at DESC, type, id DESC").
+		Offset((opts.Page - 1) * opts.PageSize).
+		Limit(opts.PageSize).
+		Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := fillDeletedBy(db, items); err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		items[i].TypeLabel = kinds[items[i].Type].label
+		if opts.RetentionDays > 0 {
+			expiresAt := items[i].DeletedAt.AddDate(0, 0, opts.RetentionDays)
+			items[i].ExpiresAt = &expiresAt
+		}
+	}
+	return items, total, nil
+}
+
+// Restore 恢复回收站中的记录，须在事务中调用
+func Restore(tx *gorm.DB, recordType string, id uint) error {
+	k := kinds[recordType]
+	if err := findDeleted(tx, k, id); err != nil {
+		return err
+	}
+	return k.restore(tx, id)
+}
+
+// Purge 彻底删除回收站中的记录，须在事务中调用；文件由上传目录清理任务回收
+func Purge(tx *gorm.DB, recordType string, id uint) error {
+	k := kinds[recordType]
+	if err := findDeleted(tx, k, id); err != nil {
+		return err
+	}
+	return k.purge(tx, id)
+}
+
+// PurgeResult 到期清除结果
+type PurgeResult struct {
+	Purged  map[string]int `json:"purged"`  // 按类型统计的清除数量
+	Skipped []SkippedItem  `json:"skipped"` // 因仍被引用等原因未清除的记录
+}
+
+// SkippedItem 未清除的记录
+type SkippedItem struct {
+	Type   string `json:"type"`
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// PurgeExpired 彻底删除删除时间早于 before 的回收站记录，每条记录单独提交
+func PurgeExpired(db *gorm.DB, before time.Time) (*PurgeResult, error) {
+	result := &PurgeResult{Purged: make(map[string]int), Skipped: make([]SkippedItem, 0)}
+	for _, recordType := range Types {
+		k := kinds[recordType]
+		var ids []uint
+		if err := db.Unscoped().Model(k.model()).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Order("id").
+			Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return k.purge(tx, id)
+			})
+			var conflictErr *ConflictError
+			if errors.As(err, &conflictErr) {
+				result.Skipped = append(result.Skipped, SkippedItem{Type: recordType, ID: id, Reason: conflictErr.Reason})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			result.Purged[recordType]++
+		}
+	}
+	return result, nil
+}
+
+// StartPurger 启动后台定时清除，保留天数不大于0时不启动
+func StartPurger(db *gorm.DB, retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := PurgeExpired(db, time.Now().AddDate(0, 0, -retentionDays))
+			if err != nil {
+				log.Printf("清除回收站到期记录失败: %v", err)
+				continue
+			}
+			for recordType, count := range result.Purged {
+				log.Printf("已清除回收站中到期的%s %d 条", kinds[recordType].label, count)
+			}
+		}
+	}()
+}
+
+// findDeleted 确认记录在回收站中
+func findDeleted(tx *gorm.DB, k kind, id uint) error {
+	var count int64
+	if err := tx.Unscoped().Model(k.model()).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// fillDeletedBy 从删除操作日志中补充删除操作人
+func fillDeletedBy(db *gorm.DB, items []Item) error {
+	ids := make(map[string][]uint)
+	for _, item := range items {
+		table := kinds[item.Type].table
+		ids[table] = append(ids[table], item.ID)
+	}
+
+	operators := make(map[string]string)
+	for table, recordIDs := range ids {
+		var logs []models.OperationLog
+		if err := db.Select("record_id", "operator").
+			Where("table_name = ? AND record_id IN ? AND operation = ? AND operator <> ''", table, recordIDs, models.OperationTypeDelete).
+			Order("created_at ASC, id ASC").
+			Find(&logs).Error; err != nil {
+			return err
+		}
+		// 按时间正序覆盖，保留最近一次删除的操作人
+		for _, entry := range logs {
+			operators[fmt.Sprintf("%s:%d", table, entry.RecordID)] = entry.Operator
+		}
+	}
+
+	for i := range items {
+		items[i].DeletedBy = operators[fmt.Sprintf("%s:%d", kinds[items[i].Type].table, items[i].ID)]
+	}
+	return nil
+}
+
+// likeSearch 生成按字段模糊匹配的关键词条件
+func likeSearch(columns ...string) func(keyword string) (string, []interface{}) {
+	return func(keyword string) (string, []interface{}) {
+		conditions := make([]string, len(columns))
+		vars := make([]interface{}, len(columns))
+		for i, column := range columns {
+			conditions[i] = column + " LIKE ?"
+			vars[i] = "%" + keyword + "%"
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", vars
+	}
+}
+
+// undelete 清除删除时间并重建检索文档；不经过模型钩子，以免借用记录的更新钩子改动资产状态
+func undelete(tx *gorm.DB, model interface{}, id uint, searchKind string) error {
+	if err := tx.Unscoped().Model(model).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	if searchKind == "" {
+		return nil
+	}
+	return models.SyncSearchDocuments(tx, searchKind, id)
+}
+
+// liveExists 判断未删除的记录是否存在
+func liveExists(tx *gorm.DB, model interface{}, query string, args ...interface{}) (bool, error) {
+	var count int64
+	if err := tx.Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// anyExists 判断记录（含回收站中的记录）是否存在
+func anyExists(tx *gorm.DB, model interface{}, query string, args ...interface{}) (bool, error) {
+	return liveExists(tx.Unscoped(), model, query, args...)
+}
@@ -0,0 +1,37 @@
+package uploads
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentDir 附件存储目录（上传目录下按月份分子目录），只能通过鉴权的下载接口访问
+const AttachmentDir = "attachments"
+
+// privateDirs 上传目录中不对外提供静态访问的子目录
+var privateDirs = []string{AttachmentDir}
+
+// StaticFS 上传目录的静态文件系统，不列出目录，私有子目录中的文件返回不存在
+func StaticFS(dir string) http.FileSystem {
+	return staticFS{fs: gin.Dir(dir, false)}
+}
+
+// staticFS 过滤私有子目录的文件系统
+type staticFS struct {
+	fs http.FileSystem
+}
+
+// Open 打开文件，路径统一小写比较，避免在不区分大小写的文件系统上绕过
+func (s staticFS) Open(name string) (http.File, error) {
+	clean := strings.ToLower(path.Clean("/" + name))
+	for _, dir := range privateDirs {
+		if clean == "/"+dir || strings.HasPrefix(clean, "/"+dir+"/") {
+			return nil, os.ErrNotExist
+		}
+	}
+	return s.fs.Open(name)
+}
@@ -0,0 +1,157 @@
+package uploads
+
+import (
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
+)
+
+// staticURLPrefix 上传目录对外提供访问的路径前缀
+const staticURLPrefix = "/uploads/"
+
+// SweepResult 清理结果
+type SweepResult struct {
+	DryRun     bool     `json:"dry_run"`     // 仅预览，未实际删除
+	Scanned    int      `json:"scanned"`     // 扫描的文件数
+	Referenced int      `json:"referenced"`  // 仍被引用的文件数
+	Recent     int      `json:"recent"`      // 在保留期内暂不清理的文件数
+	Removed    []string `json:"removed"`     // 清理（或将清理）的文件，相对上传目录
+	FreedBytes int64    `json:"freed_bytes"` // 释放的空间（字节）
+}
+
+// Sweep 删除上传目录中没有任何记录引用的文件；修改时间在 grace 之内的文件视为刚上传、尚未保存到业务数据，暂不清理
+func Sweep(db *gorm.DB, dir string, grace time.Duration, dryRun bool) (*SweepResult, error) {
+	result := &SweepResult{DryRun: dryRun, Removed: make([]string, 0)}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return result, nil
+	}
+
+	// 引用路径统一为相对上传目录的路径
+	paths, err := models.ReferencedUploadPaths(db)
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if rel := relativePath(root, path); rel != "" {
+			referenced[rel] = true
+		}
+	}
+
+	cutoff := time.Now().Add(-grace)
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// 跳过隐藏文件和目录，如 .gitkeep
+		if strings.HasPrefix(entry.Name(), ".") && path != root {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		result.Scanned++
+
+		if referenced[rel] {
+			result.Referenced++
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(cutoff) {
+			result.Recent++
+			return nil
+		}
+
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		result.Removed = append(result.Removed, rel)
+		result.FreedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StartSweeper 启动后台定时清理，interval 不大于0时不启动
+func StartSweeper(db *gorm.DB, dir string, interval, grace time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := Sweep(db, dir, grace, false)
+			if err != nil {
+				log.Printf("清理未引用的上传文件失败: %v", err)
+				continue
+			}
+			if len(result.Removed) > 0 {
+				log.Printf("已清理 %d 个未引用的上传文件，释放 %d 字节", len(result.Removed), result.FreedBytes)
+			}
+		}
+	}()
+}
+
+// relativePath 将记录中保存的路径或访问地址转换为相对上传目录的路径，不在上传目录内时返回空
+func relativePath(root, reference string) string {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return ""
+	}
+
+	// 完整访问地址只取路径部分
+	if strings.Contains(reference, "://") {
+		parsed, err := url.Parse(reference)
+		if err != nil {
+			return ""
+		}
+		reference = parsed.Path
+	}
+
+	// 静态文件访问地址，如 /uploads/images/a.png
+	if rel, ok := strings.CutPrefix(reference, staticURLPrefix); ok {
+		return filepath.ToSlash(filepath.Clean(rel))
+	}
+
+	// 文件路径，如 uploads/images/a.png 或绝对路径
+	path, err := filepath.Abs(filepath.FromSlash(reference))
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"errors"
+
+	"asset-management-system/server/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusTransitionError 资产状态流转错误响应，附带允许的下一状态；其他错误按内部错误处理
+func StatusTransitionError(c *gin.Context, err error) {
+	var transitionErr *models.AssetStatusTransitionError
+	if errors.As(err, &transitionErr) {
+		ErrorWithMessage(c, ASSET_INVALID_STATUS_TRANSITION, transitionErr.Error(), transitionErr)
+		return
+	}
+	InternalError(c, err)
+}
@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -27,22 +32,39 @@ var DefaultImageUploadConfig = FileUploadConfig{
 	UploadDir:    "./uploads/images",
 }
 
+// StoredFile 已保存的上传文件信息
+type StoredFile struct {
+	Path     string // 相对路径
+	Size     int64  // 文件大小（字节）
+	MimeType string // MIME类型
+	Checksum string // SHA-256 校验和（十六进制）
+}
+
 // UploadFile 上传文件
 func UploadFile(file *multipart.FileHeader, config FileUploadConfig) (string, error) {
+	stored, err := StoreUploadedFile(file, config)
+	if err != nil {
+		return "", err
+	}
+	return stored.Path, nil
+}
+
+// StoreUploadedFile 保存上传文件，同时计算校验和并识别MIME类型
+func StoreUploadedFile(file *multipart.FileHeader, config FileUploadConfig) (*StoredFile, error) {
 	// 检查文件大小
 	if file.Size > config.MaxSize {
-		return "", fmt.Errorf("文件大小超出限制，最大允许 %d 字节", config.MaxSize)
+		return nil, fmt.Errorf("文件大小超出限制，最大允许 %d 字节", config.MaxSize)
 	}
 
 	// 检查文件类型
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if !contains(config.AllowedTypes, ext) {
-		return "", fmt.Errorf("不支持的文件类型: %s", ext)
+		return nil, fmt.Errorf("不支持的文件类型: %s", ext)
 	}
 
 	// 确保上传目录存在
 	if err := os.MkdirAll(config.UploadDir, 0755); err != nil {
-		return "", fmt.Errorf("创建上传目录失败: %v", err)
+		return nil, fmt.Errorf("创建上传目录失败: %v", err)
 	}
 
 	// 生成唯一文件名
@@ -52,24 +74,54 @@ func UploadFile(file *multipart.FileHeader, config FileUploadConfig) (string, er
 	// 打开上传的文件
 	src, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("打开上传文件失败: %v", err)
+		return nil, fmt.Errorf("打开上传文件失败: %v", err)
 	}
 	defer src.Close()
 
 	// 创建目标文件
 	dst, err := os.Create(filepath)
 	if err != nil {
-		return "", fmt.Errorf("创建目标文件失败: %v", err)
+		return nil, fmt.Errorf("创建目标文件失败: %v", err)
 	}
 	defer dst.Close()
 
-	// 复制文件内容
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("保存文件失败: %v", err)
+	// 复制文件内容，同时计算校验和并保留文件头用于识别类型
+	hash := sha256.New()
+	head := &headBuffer{limit: 512}
+	size, err := io.Copy(io.MultiWriter(dst, hash, head), src)
+	if err != nil {
+		dst.Close()
+		os.Remove(filepath)
+		return nil, fmt.Errorf("保存文件失败: %v", err)
+	}
+
+	// 按扩展名识别MIME类型，无法识别时按文件内容判断
+	mimeType := mime.TypeByExtension(ext)
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head.Bytes())
 	}
 
 	// 返回相对路径
-	return strings.TrimPrefix(filepath, "./"), nil
+	return &StoredFile{
+		Path:     strings.TrimPrefix(filepath, "./"),
+		Size:     size,
+		MimeType: mimeType,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// headBuffer 只保留写入内容的前 limit 个字节
+type headBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write 写入内容，超出部分丢弃
+func (b *headBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.Len(); remain > 0 {
+		b.Buffer.Write(p[:min(len(p), remain)])
+	}
+	return len(p), nil
 }
 
 // generateUniqueFilename 生成唯一文件名
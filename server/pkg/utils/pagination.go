@@ -99,6 +99,22 @@ func (p *PaginationRequest) SetDefaultSorts(m map[string]bool) {
 	}
 }
 
+// ParseSortsFromQuery 解析 sorts[0][key]、sorts[0][desc] 形式的排序参数
+func (p *PaginationRequest) ParseSortsFromQuery(c *gin.Context) {
+	var sorts []SortField
+	for i := 0; ; i++ {
+		prefix := "sorts[" + strconv.Itoa(i) + "]"
+		key := c.Query(prefix + "[key]")
+		if key == "" {
+			break
+		}
+		sorts = append(sorts, SortField{Key: key, Desc: c.Query(prefix+"[desc]") == "true"})
+	}
+	if len(sorts) > 0 {
+		p.Sorts = sorts
+	}
+}
+
 // ParseFilters 解析筛选条件
 func (p *PaginationRequest) ParseFiltersFromQuery(c *gin.Context, filtersStructure interface{}) error {
 	filtersMap := map[string]interface{}{}
@@ -24,11 +24,22 @@ const (
 	FORBIDDEN         = "FORBIDDEN"
 	BAD_REQUEST       = "BAD_REQUEST"
 	
+	// 认证相关响应码
+	AUTH_INVALID_CREDENTIALS = "AUTH_001"
+	AUTH_USER_DISABLED = "AUTH_004"
+	AUTH_SSO_UNAVAILABLE = "AUTH_005"
+	
+	// 用户相关响应码
+	USER_NOT_FOUND = "USER_001"
+	USER_USERNAME_EXISTS = "USER_002"
+	
 	// 资产相关响应码
 	ASSET_NOT_FOUND   = "ASSET_001"
 	ASSET_NO_EXISTS   = "ASSET_002"
 	ASSET_IN_USE      = "ASSET_003"
 	ASSET_NOT_AVAILABLE = "ASSET_004"
+	ASSET_INVALID_STATUS_TRANSITION = "ASSET_005"
+	ASSET_INVALID_COMPONENT = "ASSET_006"
 	
 	// 分类相关响应码
 	CATEGORY_NOT_FOUND = "CATEGORY_001"
@@ -40,16 +51,79 @@ const (
 	DEPARTMENT_NOT_FOUND = "DEPARTMENT_001"
 	DEPARTMENT_HAS_ASSETS = "DEPARTMENT_002"
 	DEPARTMENT_CODE_EXISTS = "DEPARTMENT_003"
+	DEPARTMENT_HAS_CHILDREN = "DEPARTMENT_004"
 	
 	// 借用相关响应码
 	BORROW_NOT_FOUND = "BORROW_001"
 	ALREADY_RETURNED = "BORROW_002"
 	ASSET_ALREADY_BORROWED = "BORROW_003"
 	
+	// 调拨相关响应码
+	TRANSFER_NOT_FOUND = "TRANSFER_001"
+	TRANSFER_INVALID_STATUS = "TRANSFER_002"
+	TRANSFER_IN_PROGRESS = "TRANSFER_003"
+	
+	// 维修相关响应码
+	MAINTENANCE_NOT_FOUND = "MAINTENANCE_001"
+	MAINTENANCE_CLOSED = "MAINTENANCE_002"
+	MAINTENANCE_IN_PROGRESS = "MAINTENANCE_003"
+	
+	// 处置相关响应码
+	DISPOSAL_NOT_FOUND = "DISPOSAL_001"
+	DISPOSAL_INVALID_STATUS = "DISPOSAL_002"
+	DISPOSAL_IN_PROGRESS = "DISPOSAL_003"
+	ASSET_FROZEN = "DISPOSAL_004"
+	
 	// 盘点相关响应码
 	INVENTORY_TASK_NOT_FOUND = "INVENTORY_001"
 	INVENTORY_TASK_COMPLETED = "INVENTORY_002"
 	
+	// 附件相关响应码
+	ATTACHMENT_NOT_FOUND = "ATTACHMENT_001"
+	ATTACHMENT_FILE_MISSING = "ATTACHMENT_002"
+	ATTACHMENT_DUPLICATE = "ATTACHMENT_003"
+	
+	// 回收站相关响应码
+	TRASH_NOT_FOUND = "TRASH_001"
+	TRASH_RESTORE_CONFLICT = "TRASH_002"
+	TRASH_PURGE_CONFLICT = "TRASH_003"
+	
+	// 扫码相关响应码
+	SCAN_AMBIGUOUS = "SCAN_001"
+	SCAN_ACTION_NOT_ALLOWED = "SCAN_002"
+	
+	// 预约相关响应码
+	RESERVATION_NOT_FOUND = "RESERVATION_001"
+	RESERVATION_CONFLICT = "RESERVATION_002"
+	RESERVATION_INVALID_STATUS = "RESERVATION_003"
+	RESERVATION_ACTIVE = "RESERVATION_004"
+	
+	// 批量任务相关响应码
+	JOB_NOT_FOUND = "JOB_001"
+	JOB_FINISHED = "JOB_002"
+	
+	// 耗材相关响应码
+	CONSUMABLE_NOT_FOUND = "CONSUMABLE_001"
+	CONSUMABLE_CODE_EXISTS = "CONSUMABLE_002"
+	CONSUMABLE_INSUFFICIENT_STOCK = "CONSUMABLE_003"
+	CONSUMABLE_HAS_STOCK = "CONSUMABLE_004"
+	
+	// 许可证相关响应码
+	LICENSE_NOT_FOUND = "LICENSE_001"
+	LICENSE_SEAT_NOT_FOUND = "LICENSE_002"
+	LICENSE_NO_SEATS_AVAILABLE = "LICENSE_003"
+	LICENSE_SEAT_ASSIGNED = "LICENSE_004"
+	LICENSE_SEAT_REVOKED = "LICENSE_005"
+	LICENSE_HAS_SEATS = "LICENSE_006"
+	LICENSE_SEAT_COUNT_TOO_LOW = "LICENSE_007"
+	LICENSE_EXPIRED = "LICENSE_008"
+	
+	// 会话相关响应码
+	SESSION_NOT_FOUND = "SESSION_001"
+	
+	// API密钥相关响应码
+	API_KEY_NOT_FOUND = "API_KEY_001"
+	
 	// 文件上传相关响应码
 	FILE_TOO_LARGE = "FILE_001"
 	FILE_TYPE_NOT_ALLOWED = "FILE_002"
@@ -66,10 +140,19 @@ var responseMessages = map[string]string{
 	FORBIDDEN:         "禁止访问",
 	BAD_REQUEST:       "请求参数错误",
 	
+	AUTH_INVALID_CREDENTIALS: "用户名或密码错误",
+	AUTH_USER_DISABLED: "用户已被禁用",
+	AUTH_SSO_UNAVAILABLE: "单点登录服务不可用",
+	
+	USER_NOT_FOUND: "用户不存在",
+	USER_USERNAME_EXISTS: "用户名已存在",
+	
 	ASSET_NOT_FOUND:   "资产不存在",
 	ASSET_NO_EXISTS:   "资产编号已存在",
 	ASSET_IN_USE:      "资产正在使用中",
 	ASSET_NOT_AVAILABLE: "资产不可用",
+	ASSET_INVALID_STATUS_TRANSITION: "资产状态流转不合法",
+	ASSET_INVALID_COMPONENT: "资产组件关系不合法",
 	
 	CATEGORY_NOT_FOUND: "分类不存在",
 	CATEGORY_HAS_ASSETS: "分类下存在资产，无法删除",
@@ -79,14 +162,61 @@ var responseMessages = map[string]string{
 	DEPARTMENT_NOT_FOUND: "部门不存在",
 	DEPARTMENT_HAS_ASSETS: "部门下存在资产，无法删除",
 	DEPARTMENT_CODE_EXISTS: "部门编码已存在",
+	DEPARTMENT_HAS_CHILDREN: "部门下存在子部门，无法删除",
 	
 	BORROW_NOT_FOUND: "借用记录不存在",
 	ALREADY_RETURNED: "资产已归还",
 	ASSET_ALREADY_BORROWED: "资产已被借用",
 	
+	TRANSFER_NOT_FOUND: "调拨单不存在",
+	TRANSFER_INVALID_STATUS: "调拨单当前状态不允许该操作",
+	TRANSFER_IN_PROGRESS: "资产存在进行中的调拨单",
+	
+	MAINTENANCE_NOT_FOUND: "维修工单不存在",
+	MAINTENANCE_CLOSED: "维修工单已关闭",
+	MAINTENANCE_IN_PROGRESS: "资产存在未关闭的维修工单",
+	
+	DISPOSAL_NOT_FOUND: "处置申请不存在",
+	DISPOSAL_INVALID_STATUS: "处置申请当前状态不允许该操作",
+	DISPOSAL_IN_PROGRESS: "资产存在进行中的处置申请",
+	ASSET_FROZEN: "资产处置已批准，不能借用、编辑或删除",
+	
 	INVENTORY_TASK_NOT_FOUND: "盘点任务不存在",
 	INVENTORY_TASK_COMPLETED: "盘点任务已完成",
 	
+	ATTACHMENT_NOT_FOUND: "附件不存在",
+	ATTACHMENT_FILE_MISSING: "附件文件不存在",
+	ATTACHMENT_DUPLICATE: "相同文件已上传",
+	
+	TRASH_NOT_FOUND: "回收站中没有该记录",
+	TRASH_RESTORE_CONFLICT: "记录无法恢复",
+	TRASH_PURGE_CONFLICT: "记录无法彻底删除",
+	
+	SCAN_AMBIGUOUS: "扫码内容匹配到多个资产",
+	SCAN_ACTION_NOT_ALLOWED: "资产当前状态不允许该操作",
+	RESERVATION_NOT_FOUND: "预约不存在",
+	RESERVATION_CONFLICT: "该时间段内资产已被预约或借用",
+	RESERVATION_INVALID_STATUS: "预约当前状态不允许该操作",
+	RESERVATION_ACTIVE: "资产有未取用的预约，请先取消预约",
+	JOB_NOT_FOUND: "批量任务不存在",
+	JOB_FINISHED: "批量任务已结束",
+	CONSUMABLE_NOT_FOUND: "耗材不存在",
+	CONSUMABLE_CODE_EXISTS: "耗材编码已存在",
+	CONSUMABLE_INSUFFICIENT_STOCK: "库存不足",
+	CONSUMABLE_HAS_STOCK: "耗材仍有库存，无法删除",
+	LICENSE_NOT_FOUND: "许可证不存在",
+	LICENSE_SEAT_NOT_FOUND: "席位分配记录不存在",
+	LICENSE_NO_SEATS_AVAILABLE: "许可证席位已分配完",
+	LICENSE_SEAT_ASSIGNED: "该人员或资产已分配此许可证",
+	LICENSE_SEAT_REVOKED: "席位已回收",
+	LICENSE_HAS_SEATS: "许可证仍有已分配的席位，无法删除",
+	LICENSE_SEAT_COUNT_TOO_LOW: "席位数不能少于已分配的席位数",
+	LICENSE_EXPIRED: "许可证已过期",
+	
+	SESSION_NOT_FOUND: "会话不存在",
+	
+	API_KEY_NOT_FOUND: "API密钥不存在",
+	
 	FILE_TOO_LARGE: "文件大小超出限制",
 	FILE_TYPE_NOT_ALLOWED: "文件类型不允许",
 	FILE_UPLOAD_FAILED: "文件上传失败",
@@ -184,14 +314,16 @@ func getHTTPStatusCode(code string) int {
 		return http.StatusOK
 	case VALIDATION_ERROR, BAD_REQUEST:
 		return http.StatusBadRequest
-	case UNAUTHORIZED:
+	case UNAUTHORIZED, AUTH_INVALID_CREDENTIALS:
 		return http.StatusUnauthorized
-	case FORBIDDEN:
+	case FORBIDDEN, AUTH_USER_DISABLED:
 		return http.StatusForbidden
-	case NOT_FOUND, ASSET_NOT_FOUND, CATEGORY_NOT_FOUND, DEPARTMENT_NOT_FOUND, BORROW_NOT_FOUND, INVENTORY_TASK_NOT_FOUND:
+	case NOT_FOUND, USER_NOT_FOUND, ASSET_NOT_FOUND, CATEGORY_NOT_FOUND, DEPARTMENT_NOT_FOUND, BORROW_NOT_FOUND, INVENTORY_TASK_NOT_FOUND, SESSION_NOT_FOUND, API_KEY_NOT_FOUND, TRANSFER_NOT_FOUND, MAINTENANCE_NOT_FOUND, DISPOSAL_NOT_FOUND, ATTACHMENT_NOT_FOUND, ATTACHMENT_FILE_MISSING, TRASH_NOT_FOUND, RESERVATION_NOT_FOUND, JOB_NOT_FOUND, CONSUMABLE_NOT_FOUND, LICENSE_NOT_FOUND, LICENSE_SEAT_NOT_FOUND:
 		return http.StatusNotFound
-	case ASSET_NO_EXISTS, ASSET_IN_USE, CATEGORY_HAS_ASSETS, CATEGORY_HAS_CHILDREN, CATEGORY_CODE_EXISTS, DEPARTMENT_HAS_ASSETS, DEPARTMENT_CODE_EXISTS, ALREADY_RETURNED, ASSET_ALREADY_BORROWED, INVENTORY_TASK_COMPLETED:
+	case USER_USERNAME_EXISTS, ASSET_NO_EXISTS, ASSET_IN_USE, CATEGORY_HAS_ASSETS, CATEGORY_HAS_CHILDREN, CATEGORY_CODE_EXISTS, DEPARTMENT_HAS_ASSETS, DEPARTMENT_CODE_EXISTS, DEPARTMENT_HAS_CHILDREN, ALREADY_RETURNED, ASSET_ALREADY_BORROWED, INVENTORY_TASK_COMPLETED, ASSET_INVALID_STATUS_TRANSITION, ASSET_INVALID_COMPONENT, TRANSFER_INVALID_STATUS, TRANSFER_IN_PROGRESS, MAINTENANCE_CLOSED, MAINTENANCE_IN_PROGRESS, DISPOSAL_INVALID_STATUS, DISPOSAL_IN_PROGRESS, ASSET_FROZEN, ATTACHMENT_DUPLICATE, TRASH_RESTORE_CONFLICT, TRASH_PURGE_CONFLICT, SCAN_AMBIGUOUS, SCAN_ACTION_NOT_ALLOWED, RESERVATION_CONFLICT, RESERVATION_INVALID_STATUS, RESERVATION_ACTIVE, JOB_FINISHED, CONSUMABLE_CODE_EXISTS, CONSUMABLE_INSUFFICIENT_STOCK, CONSUMABLE_HAS_STOCK, LICENSE_NO_SEATS_AVAILABLE, LICENSE_SEAT_ASSIGNED, LICENSE_SEAT_REVOKED, LICENSE_HAS_SEATS, LICENSE_SEAT_COUNT_TOO_LOW, LICENSE_EXPIRED:
 		return http.StatusConflict
+	case AUTH_SSO_UNAVAILABLE:
+		return http.StatusBadGateway
 	case INTERNAL_ERROR:
 		return http.StatusInternalServerError
 	default:
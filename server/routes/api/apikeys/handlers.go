@@ -0,0 +1,205 @@
+package apikeys
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetAPIKeys 获取API密钥列表
+func GetAPIKeys(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters APIKeyFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"created_at": true,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "name", "expires_at", "last_used_at", "created_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	query := global.DB.Model(&models.APIKey{})
+	query = applyAPIKeyFilters(query, filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var apiKeys []models.APIKey
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&apiKeys).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, apiKeys)
+	utils.Success(c, response)
+}
+
+// GetAPIKey 获取API密钥详情
+func GetAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的API密钥ID")
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := global.DB.First(&apiKey, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.API_KEY_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, apiKey)
+}
+
+// CreateAPIKey 创建API密钥
+func CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := auth.ValidateScopes(req.Scopes); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.ValidationError(c, "过期时间必须晚于当前时间")
+		return
+	}
+
+	key, keyPrefix, keyHash, err := auth.GenerateAPIKey()
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	apiKey := models.APIKey{
+		Name:      req.Name,
+		KeyPrefix: keyPrefix,
+		KeyHash:   keyHash,
+		Scopes:    strings.Join(req.Scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: auth.GetOperator(c),
+	}
+
+	if err := global.DB.Create(&apiKey).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, CreateAPIKeyResponse{APIKey: apiKey, Key: key})
+}
+
+// RevokeAPIKey 吊销API密钥，保留记录用于审计
+func RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的API密钥ID")
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := global.DB.First(&apiKey, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.API_KEY_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if apiKey.IsRevoked() {
+		utils.ValidationError(c, "API密钥已吊销")
+		return
+	}
+
+	now := time.Now()
+	if err := global.DB.Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	apiKey.RevokedAt = &now
+
+	utils.Success(c, apiKey)
+}
+
+// applyAPIKeyFilters 应用API密钥筛选条件
+func applyAPIKeyFilters(query *gorm.DB, filters APIKeyFilters) *gorm.DB {
+	if filters.Keyword != nil && *filters.Keyword != "" {
+		keyword := "%" + *filters.Keyword + "%"
+		query = query.Where("name LIKE ? OR key_prefix LIKE ?", keyword, keyword)
+	}
+	if filters.Status != nil {
+		now := time.Now()
+		switch *filters.Status {
+		case "active":
+			query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now)
+		case "expired":
+			query = query.Where("revoked_at IS NULL AND expires_at <= ?", now)
+		case "revoked":
+			query = query.Where("revoked_at IS NOT NULL")
+		}
+	}
+
+	return query
+}
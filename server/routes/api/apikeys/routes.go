@@ -0,0 +1,16 @@
+package apikeys
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册API密钥管理路由（仅管理员）
+func RegisterRoutes(r *gin.RouterGroup) {
+	apiKeys := r.Group("/api-keys")
+	{
+		apiKeys.GET("", GetAPIKeys)          // 获取API密钥列表
+		apiKeys.POST("", CreateAPIKey)       // 创建API密钥
+		apiKeys.GET("/:id", GetAPIKey)       // 获取API密钥详情
+		apiKeys.DELETE("/:id", RevokeAPIKey) // 吊销API密钥
+	}
+}
@@ -0,0 +1,26 @@
+package apikeys
+
+import (
+	"time"
+
+	"asset-management-system/server/models"
+)
+
+// CreateAPIKeyRequest 创建API密钥请求
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"` // 格式为 资源:操作，如 assets:import、borrow:*
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse 创建API密钥响应，明文密钥仅在创建时返回一次
+type CreateAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyFilters API密钥筛选条件
+type APIKeyFilters struct {
+	Keyword *string `json:"keyword" form:"keyword"`
+	Status  *string `json:"status" form:"status"` // active、expired、revoked
+}
@@ -0,0 +1,124 @@
+package assetnorules
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/numbering"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetAssetNoRules 获取全局和各分类的资产编号规则
+func GetAssetNoRules(c *gin.Context) {
+	rules, err := numbering.Rules(global.DB)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, rules)
+}
+
+// SaveAssetNoRule 保存资产编号规则
+func SaveAssetNoRule(c *gin.Context) {
+	var req SaveAssetNoRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 分类规则按分类编码保存
+	var categoryCode string
+	if req.CategoryID != nil {
+		var category models.Category
+		if err := global.DB.First(&category, *req.CategoryID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.CATEGORY_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+		categoryCode = category.Code
+	}
+
+	if err := numbering.SaveRule(global.DB, categoryCode, req.Pattern); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	rules, err := numbering.Rules(global.DB)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, rules)
+}
+
+// PreviewAssetNo 预览分类下一个资产编号，不占用流水号
+func PreviewAssetNo(c *gin.Context) {
+	var req PreviewAssetNoRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	var category models.Category
+	if err := global.DB.First(&category, req.CategoryID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.CATEGORY_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	ctx := numbering.Context{Category: &category}
+	if req.DepartmentID != nil {
+		var department models.Department
+		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+		ctx.Department = &department
+	}
+
+	pattern, err := numbering.ResolvePattern(global.DB, &category)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	assetNo, err := numbering.Preview(global.DB, ctx)
+	if err != nil {
+		if err == numbering.ErrDepartmentRequired {
+			utils.ValidationError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, PreviewAssetNoResponse{AssetNo: assetNo, Pattern: pattern})
+}
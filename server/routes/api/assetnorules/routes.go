@@ -0,0 +1,15 @@
+package assetnorules
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册资产编号规则路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	rules := r.Group("/asset-no-rules")
+	{
+		rules.GET("", GetAssetNoRules)        // 获取编号规则
+		rules.PUT("", SaveAssetNoRule)        // 保存编号规则
+		rules.GET("/preview", PreviewAssetNo) // 预览下一个资产编号
+	}
+}
@@ -0,0 +1,19 @@
+package assetnorules
+
+// SaveAssetNoRuleRequest 保存编号规则请求
+type SaveAssetNoRuleRequest struct {
+	CategoryID *uint  `json:"category_id"`                // 为空时保存全局规则
+	Pattern    string `json:"pattern" validate:"max=100"` // 分类规则为空时删除，恢复使用上级分类或全局规则
+}
+
+// PreviewAssetNoRequest 预览资产编号请求
+type PreviewAssetNoRequest struct {
+	CategoryID   uint  `form:"category_id" validate:"required"`
+	DepartmentID *uint `form:"department_id"`
+}
+
+// PreviewAssetNoResponse 预览资产编号响应
+type PreviewAssetNoResponse struct {
+	AssetNo string `json:"asset_no"` // 下一个资产编号，实际创建时可能因并发而不同
+	Pattern string `json:"pattern"`  // 适用的编号规则
+}
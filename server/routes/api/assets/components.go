@@ -0,0 +1,198 @@
+package assets
+
+import (
+	"errors"
+	"strconv"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// componentErrors 组件关系校验错误，以 ASSET_INVALID_COMPONENT 返回
+var componentErrors = []error{
+	models.ErrComponentSelf,
+	models.ErrComponentTooDeep,
+	models.ErrComponentAttached,
+	models.ErrComponentNotAttached,
+}
+
+// GetAssetComponents 获取资产的组件树及含组件的原值合计
+func GetAssetComponents(c *gin.Context) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	asset, ok := findScopedAsset(c, scope, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	response, err := buildComponentTree(scope, asset)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// AttachAssetComponent 将资产作为组件挂载到父资产下，组件已属于其他父资产时视为移动
+func AttachAssetComponent(c *gin.Context) {
+	changeAssetComponent(c, models.ComponentActionAttach)
+}
+
+// DetachAssetComponent 将组件从父资产拆卸为独立资产
+func DetachAssetComponent(c *gin.Context) {
+	changeAssetComponent(c, models.ComponentActionDetach)
+}
+
+// GetAssetComponentHistory 获取资产作为父资产或组件的挂载、拆卸历史
+func GetAssetComponentHistory(c *gin.Context) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	asset, ok := findScopedAsset(c, scope, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	// 资产可见即可查看其完整历史，包括已删除或范围外的关联资产
+	var logs []models.AssetComponentLog
+	if err := global.DB.
+		Preload("Parent", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Component", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("parent_id = ? OR component_id = ?", asset.ID, asset.ID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&logs).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, logs)
+}
+
+// changeAssetComponent 挂载或拆卸组件，父资产和组件都须在数据范围内
+func changeAssetComponent(c *gin.Context, action string) {
+	var req ComponentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	parent, ok := findScopedAsset(c, scope, c.Param("id"))
+	if !ok {
+		return
+	}
+	component, ok := findScopedAsset(c, scope, strconv.FormatUint(uint64(req.ComponentID), 10))
+	if !ok {
+		return
+	}
+	if action == models.ComponentActionAttach &&
+		(parent.Status == models.AssetStatusScrapped || component.Status == models.AssetStatusScrapped) {
+		utils.ErrorWithMessage(c, utils.ASSET_INVALID_COMPONENT, "已报废的资产不能挂载组件", nil)
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	operator := auth.GetOperator(c)
+	if action == models.ComponentActionAttach {
+		err = models.AttachComponent(tx, parent.ID, component.ID, operator, req.Notes)
+	} else {
+		err = models.DetachComponent(tx, parent.ID, component.ID, operator, req.Notes)
+	}
+	if err != nil {
+		tx.Rollback()
+		for _, componentErr := range componentErrors {
+			if errors.Is(err, componentErr) {
+				utils.ErrorWithMessage(c, utils.ASSET_INVALID_COMPONENT, err.Error(), nil)
+				return
+			}
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	response, err := buildComponentTree(scope, parent)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// findScopedAsset 查找数据范围内的资产，失败时已写入响应
+func findScopedAsset(c *gin.Context, scope *auth.DataScope, idParam string) (*models.Asset, bool) {
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的资产ID")
+		return nil, false
+	}
+
+	var asset models.Asset
+	if err := scope.DB().First(&asset, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &asset, true
+}
+
+// buildComponentTree 生成资产的组件树响应
+func buildComponentTree(scope *auth.DataScope, asset *models.Asset) (*ComponentTreeResponse, error) {
+	components, err := models.LoadComponentTree(scope.DB(), asset.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ComponentTreeResponse{
+		AssetID:        asset.ID,
+		Components:     components,
+		ComponentValue: models.SumComponentValue(components),
+	}
+	response.TotalValue = response.ComponentValue
+	if asset.PurchasePrice != nil {
+		response.TotalValue += *asset.PurchasePrice
+	}
+	return response, nil
+}
@@ -3,15 +3,27 @@ package assets
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/attributes"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/depreciation"
+	"asset-management-system/server/pkg/labels"
+	"asset-management-system/server/pkg/numbering"
+	"asset-management-system/server/pkg/spreadsheet"
 	"asset-management-system/server/pkg/utils"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/xuri/excelize/v2"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -42,6 +54,13 @@ func GetAssets(c *gin.Context) {
 		utils.ValidationError(c, err.Error())
 		return
 	}
+	attributeFilters, err := parseAttributeFilters(c)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	filters.Attributes = attributeFilters
+	req.ParseSortsFromQuery(c)
 
 	// 设置默认排序
 	req.SetDefaultSorts(map[string]bool{
@@ -49,17 +68,30 @@ func GetAssets(c *gin.Context) {
 		"id":         true,
 	})
 
-	// 验证排序字段
+	// 验证排序字段，attr.字段名 按自定义属性排序
 	allowedSortFields := []string{"id", "asset_no", "name", "category_id", "department_id", "status", "brand", "model", "purchase_date", "purchase_price", "created_at", "updated_at"}
-	for _, sort := range req.Sorts {
+	for i, sort := range req.Sorts {
+		if name, ok := strings.CutPrefix(sort.Key, attributeKeyPrefix); ok {
+			if !attributes.IsValidFieldName(name) {
+				utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+				return
+			}
+			req.Sorts[i].Key = attributeColumn(name)
+			continue
+		}
 		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
 			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
 			return
 		}
 	}
 
-	// 构建查询
-	query := global.DB.Model(&models.Asset{}).
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.Asset{}).
 		Preload("Category").
 		Preload("Department")
 
@@ -107,8 +139,14 @@ func GetAsset(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	var asset models.Asset
-	if err := global.DB.
+	if err := scope.DB().
 		Preload("Category").
 		Preload("Department").
 		Preload("BorrowRecords", func(db *gorm.DB) *gorm.DB {
@@ -123,15 +161,126 @@ func GetAsset(c *gin.Context) {
 		return
 	}
 
+	// 组件树及含组件的原值合计
+	tree, err := buildComponentTree(scope, &asset)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	response := AssetResponse{
 		Asset:           asset,
 		WarrantyEndDate: asset.GetWarrantyEndDate(),
 		IsUnderWarranty: asset.IsUnderWarranty(),
+		Components:      tree.Components,
+		ComponentValue:  tree.ComponentValue,
+		TotalValue:      tree.TotalValue,
+	}
+
+	if asset.ParentID != nil {
+		var parent models.Asset
+		if err := global.DB.Select("id", "asset_no", "name", "status").First(&parent, *asset.ParentID).Error; err == nil {
+			response.Parent = &AssetBrief{ID: parent.ID, AssetNo: parent.AssetNo, Name: parent.Name, Status: parent.Status}
+		} else if err != gorm.ErrRecordNotFound {
+			utils.InternalError(c, err)
+			return
+		}
 	}
 
 	utils.Success(c, response)
 }
 
+// GetAssetDepreciation 获取资产折旧计划及指定期间（默认当前月）的净值
+func GetAssetDepreciation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的资产ID")
+		return
+	}
+
+	period, err := depreciation.ParsePeriod(c.Query("period"))
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var asset models.Asset
+	if err := scope.DB().First(&asset, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	schedule, err := depreciation.NewResolver(global.DB).Schedule(&asset)
+	if err != nil {
+		if depreciation.IsSettingsError(err) {
+			utils.ValidationError(c, err.Error())
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	response := AssetDepreciationResponse{
+		AssetID:  asset.ID,
+		AssetNo:  asset.AssetNo,
+		Name:     asset.Name,
+		Current:  schedule.At(period),
+		Schedule: schedule,
+	}
+
+	utils.Success(c, response)
+}
+
+// GetAssetTransfers 获取资产的调拨历史
+func GetAssetTransfers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的资产ID")
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var asset models.Asset
+	if err := scope.DB().First(&asset, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 资产可见即可查看其完整调拨历史，包括调出到范围外部门的记录
+	var transfers []models.AssetTransfer
+	if err := global.DB.
+		Preload("FromDepartment").
+		Preload("ToDepartment").
+		Where("asset_id = ?", asset.ID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&transfers).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, transfers)
+}
+
 // CreateAsset 创建资产
 func CreateAsset(c *gin.Context) {
 	var req CreateAssetRequest
@@ -145,17 +294,29 @@ func CreateAsset(c *gin.Context) {
 		utils.ValidationError(c, err.Error())
 		return
 	}
-
-	// 检查资产编号是否已存在
-	var existingAsset models.Asset
-	if err := global.DB.Where("asset_no = ?", req.AssetNo).First(&existingAsset).Error; err == nil {
-		utils.Error(c, utils.ASSET_NO_EXISTS, nil)
+	if err := depreciation.CheckPartial(req.DepreciationMethod, req.UsefulLifeMonths); err != nil {
+		utils.ValidationError(c, err.Error())
 		return
-	} else if err != gorm.ErrRecordNotFound {
-		utils.InternalError(c, err)
+	}
+
+	// 校验初始状态
+	if err := models.CheckInitialAssetStatus(req.Status); err != nil {
+		utils.StatusTransitionError(c, err)
 		return
 	}
 
+	// 检查资产编号是否已存在（未填写时稍后自动生成）
+	if req.AssetNo != "" {
+		var existingAsset models.Asset
+		if err := global.DB.Where("asset_no = ?", req.AssetNo).First(&existingAsset).Error; err == nil {
+			utils.Error(c, utils.ASSET_NO_EXISTS, nil)
+			return
+		} else if err != gorm.ErrRecordNotFound {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
 	// 验证分类是否存在
 	var category models.Category
 	if err := global.DB.First(&category, req.CategoryID).Error; err != nil {
@@ -167,10 +328,25 @@ func CreateAsset(c *gin.Context) {
 		return
 	}
 
+	// 部门受限用户未指定部门时默认归属本部门
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if req.DepartmentID == nil && !scope.All {
+		req.DepartmentID = scope.DepartmentID
+	}
+	if !scope.CanAccessDepartment(req.DepartmentID) {
+		utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权在该部门下创建资产", nil)
+		return
+	}
+
 	// 验证部门是否存在（如果提供了部门ID）
+	var department *models.Department
 	if req.DepartmentID != nil {
-		var department models.Department
-		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+		department = &models.Department{}
+		if err := global.DB.First(department, *req.DepartmentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
 				return
@@ -180,15 +356,11 @@ func CreateAsset(c *gin.Context) {
 		}
 	}
 
-	// 转换自定义属性为JSON
-	var customAttributesJSON []byte
-	if req.CustomAttributes != nil {
-		var err error
-		customAttributesJSON, err = json.Marshal(req.CustomAttributes)
-		if err != nil {
-			utils.ValidationError(c, "自定义属性格式错误")
-			return
-		}
+	// 按分类模板校验自定义属性
+	customAttributesJSON, err := buildCustomAttributes(&category, req.CustomAttributes)
+	if err != nil {
+		customAttributesError(c, err)
+		return
 	}
 
 	// 转换采购日期
@@ -197,6 +369,28 @@ func CreateAsset(c *gin.Context) {
 		purchaseDate = &req.PurchaseDate.Time
 	}
 
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 未填写资产编号时按编号规则生成，流水号与资产在同一事务中提交
+	if req.AssetNo == "" {
+		req.AssetNo, err = numbering.Next(tx, numbering.Context{Category: &category, Department: department})
+		if err != nil {
+			tx.Rollback()
+			if err == numbering.ErrDepartmentRequired {
+				utils.ValidationError(c, err.Error())
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
 	// 创建资产
 	asset := models.Asset{
 		AssetNo:           req.AssetNo,
@@ -216,6 +410,10 @@ func CreateAsset(c *gin.Context) {
 		Description:       req.Description,
 		ImageURL:          req.ImageURL,
 		CustomAttributes:  customAttributesJSON,
+
+		DepreciationMethod: req.DepreciationMethod,
+		UsefulLifeMonths:   req.UsefulLifeMonths,
+		SalvageRate:        req.SalvageRate,
 	}
 
 	// 设置默认状态
@@ -223,7 +421,14 @@ func CreateAsset(c *gin.Context) {
 		asset.Status = models.AssetStatusAvailable
 	}
 
-	if err := global.DB.Create(&asset).Error; err != nil {
+	if err := tx.Create(&asset).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -266,9 +471,14 @@ func UpdateAsset(c *gin.Context) {
 		return
 	}
 
-	// 查找资产
+	// 查找资产（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var asset models.Asset
-	if err := global.DB.First(&asset, id).Error; err != nil {
+	if err := scope.DB().First(&asset, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
 			return
@@ -277,6 +487,30 @@ func UpdateAsset(c *gin.Context) {
 		return
 	}
 
+	// 处置已批准或已完成的资产冻结，不允许编辑
+	frozen, err := models.IsAssetFrozen(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if frozen {
+		utils.Error(c, utils.ASSET_FROZEN, nil)
+		return
+	}
+
+	// 校验合并后的折旧参数
+	method, usefulLife := asset.DepreciationMethod, asset.UsefulLifeMonths
+	if req.DepreciationMethod != nil {
+		method = *req.DepreciationMethod
+	}
+	if req.UsefulLifeMonths != nil {
+		usefulLife = req.UsefulLifeMonths
+	}
+	if err := depreciation.CheckPartial(method, usefulLife); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
 	// 检查资产编号是否已被其他资产使用
 	if req.AssetNo != nil && *req.AssetNo != asset.AssetNo {
 		var existingAsset models.Asset
@@ -290,20 +524,27 @@ func UpdateAsset(c *gin.Context) {
 	}
 
 	// 验证分类是否存在
+	categoryID := asset.CategoryID
 	if req.CategoryID != nil {
-		var category models.Category
-		if err := global.DB.First(&category, *req.CategoryID).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				utils.Error(c, utils.CATEGORY_NOT_FOUND, nil)
-				return
-			}
-			utils.InternalError(c, err)
+		categoryID = *req.CategoryID
+	}
+	var category models.Category
+	if err := global.DB.First(&category, categoryID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.CATEGORY_NOT_FOUND, nil)
 			return
 		}
+		utils.InternalError(c, err)
+		return
 	}
 
 	// 验证部门是否存在
 	if req.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权将资产调整到该部门", nil)
+			return
+		}
+
 		var department models.Department
 		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -345,23 +586,81 @@ func UpdateAsset(c *gin.Context) {
 		updates["warranty_period"] = *req.WarrantyPeriod
 	}
 	if req.Status != nil {
+		if err := models.CheckAssetStatusTransition(global.DB, &asset, *req.Status); err != nil {
+			utils.StatusTransitionError(c, err)
+			return
+		}
 		updates["status"] = *req.Status
 	}
 	updates["location"] = req.Location
 	updates["responsible_person"] = req.ResponsiblePerson
 	updates["description"] = req.Description
 	updates["image_url"] = req.ImageURL
-	if req.CustomAttributes != nil {
-		customAttributesJSON, err := json.Marshal(req.CustomAttributes)
+	// 提交了自定义属性或变更了分类时，按分类模板重新校验
+	if req.CustomAttributes != nil || categoryID != asset.CategoryID {
+		values := req.CustomAttributes
+		if values == nil && len(asset.CustomAttributes) > 0 {
+			if err := json.Unmarshal(asset.CustomAttributes, &values); err != nil {
+				utils.InternalError(c, err)
+				return
+			}
+		}
+		customAttributesJSON, err := buildCustomAttributes(&category, values)
 		if err != nil {
-			utils.ValidationError(c, "自定义属性格式错误")
+			customAttributesError(c, err)
 			return
 		}
 		updates["custom_attributes"] = customAttributesJSON
 	}
+	if req.DepreciationMethod != nil {
+		updates["depreciation_method"] = *req.DepreciationMethod
+	}
+	if req.UsefulLifeMonths != nil {
+		updates["useful_life_months"] = *req.UsefulLifeMonths
+	}
+	if req.SalvageRate != nil {
+		updates["salvage_rate"] = *req.SalvageRate
+	}
+
+	// 直接变更部门时须没有进行中的调拨单，并补记调拨记录
+	departmentChanged := req.DepartmentID != nil && (asset.DepartmentID == nil || *asset.DepartmentID != *req.DepartmentID)
+	if departmentChanged {
+		inProgress, err := models.HasOpenTransfer(global.DB, asset.ID)
+		if err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		if inProgress {
+			utils.Error(c, utils.TRANSFER_IN_PROGRESS, nil)
+			return
+		}
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if departmentChanged {
+		if err := models.RecordDirectTransfer(tx, &asset, *req.DepartmentID, req.Location, req.ResponsiblePerson, auth.GetOperator(c), "编辑资产时直接变更部门"); err != nil {
+			tx.Rollback()
+			utils.InternalError(c, err)
+			return
+		}
+	}
 
 	// 执行更新
-	if err := global.DB.Model(&asset).Updates(updates).Error; err != nil {
+	if err := tx.Model(&asset).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -392,9 +691,14 @@ func DeleteAsset(c *gin.Context) {
 		return
 	}
 
-	// 查找资产
+	// 查找资产（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var asset models.Asset
-	if err := global.DB.First(&asset, id).Error; err != nil {
+	if err := scope.DB().First(&asset, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
 			return
@@ -403,22 +707,47 @@ func DeleteAsset(c *gin.Context) {
 		return
 	}
 
-	// 检查是否有未归还的借用记录
-	var borrowCount int64
-	if err := global.DB.Model(&models.BorrowRecord{}).
-		Where("asset_id = ? AND status = ?", id, models.BorrowStatusBorrowed).
-		Count(&borrowCount).Error; err != nil {
-		utils.InternalError(c, err)
+	// 借用中或超期未还、有未取用预约、处置中或已核销的资产不能删除
+	if err := models.CheckAssetDeletable(global.DB, &asset, pickupGrace()); err != nil {
+		switch {
+		case errors.Is(err, models.ErrAssetInUse):
+			utils.Error(c, utils.ASSET_IN_USE, nil)
+		case errors.Is(err, models.ErrAssetReserved):
+			utils.Error(c, utils.RESERVATION_ACTIVE, nil)
+		case errors.Is(err, models.ErrAssetFrozen):
+			utils.Error(c, utils.ASSET_FROZEN, nil)
+		case errors.Is(err, models.ErrAssetInDisposal):
+			utils.Error(c, utils.DISPOSAL_IN_PROGRESS, nil)
+		default:
+			utils.InternalError(c, err)
+		}
 		return
 	}
 
-	if borrowCount > 0 {
-		utils.Error(c, utils.ASSET_IN_USE, nil)
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 拆下全部组件，并从所属父资产拆下
+	if err := models.DetachAllComponents(tx, &asset, auth.GetOperator(c), "资产已删除"); err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
 		return
 	}
 
 	// 删除资产
-	if err := global.DB.Delete(&asset).Error; err != nil {
+	if err := tx.Delete(&asset).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -461,20 +790,241 @@ func ImportAssets(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	response := importAssets(tx, scope, req.Assets)
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// ImportAssetsFile 从 xlsx 或 csv 文件导入资产，支持列映射和试运行
+func ImportAssetsFile(c *gin.Context) {
+	var req ImportAssetFileRequest
+	// 先绑定查询参数，再以表单字段覆盖
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ValidationError(c, "请上传导入文件")
+		return
+	}
+	if file.Size > global.AppConfig.UploadMaxSize {
+		utils.ValidationError(c, fmt.Sprintf("文件大小超出限制，最大允许 %d 字节", global.AppConfig.UploadMaxSize))
+		return
+	}
+
+	mapping, err := parseImportMapping(req.Mapping)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	defer src.Close()
+
+	rows, err := spreadsheet.Read(file.Filename, src, req.Sheet)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	lookup, err := loadImportLookup(global.DB)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	headerIndex, fields, err := lookup.detectImportColumns(rows, req.HeaderRow, mapping)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	header := rows[headerIndex]
+
+	// 逐行转换为创建资产请求，无法解析的行直接记为失败
+	var assetReqs []CreateAssetRequest
+	var rowIndexes []int
+	var parseErrors []ImportAssetError
+	for i, row := range rows[headerIndex+1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		if len(assetReqs)+len(parseErrors) >= maxImportRows {
+			utils.ValidationError(c, fmt.Sprintf("单个文件最多导入 %d 行数据", maxImportRows))
+			return
+		}
+
+		assetReq, errs := lookup.buildImportRequest(header, row, fields)
+		if len(errs) > 0 {
+			parseErrors = append(parseErrors, ImportAssetError{
+				Index:   i,
+				Row:     headerIndex + i + 2,
+				AssetNo: assetReq.AssetNo,
+				Error:   strings.Join(errs, "; "),
+			})
+			continue
+		}
+		assetReqs = append(assetReqs, assetReq)
+		rowIndexes = append(rowIndexes, i)
+	}
+	if len(assetReqs)+len(parseErrors) == 0 {
+		utils.ValidationError(c, "文件中没有可导入的数据")
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	response := importAssets(tx, scope, assetReqs)
+
+	// 错误索引换算为文件中的数据行，与解析错误合并后按行号排序
+	for i := range response.Errors {
+		index := rowIndexes[response.Errors[i].Index]
+		response.Errors[i].Index = index
+		response.Errors[i].Row = headerIndex + index + 2
+	}
+	response.Errors = append(response.Errors, parseErrors...)
+	sort.Slice(response.Errors, func(i, j int) bool {
+		return response.Errors[i].Row < response.Errors[j].Row
+	})
+	response.FailedCount += len(parseErrors)
+
+	for i, field := range fields {
+		text := ""
+		if i < len(header) {
+			text = header[i]
+		}
+		if text == "" && field == "" {
+			continue
+		}
+		response.Columns = append(response.Columns, ImportColumn{Column: spreadsheet.ColumnName(i), Header: text, Field: field})
+	}
+
+	// 试运行时回滚事务，返回与正式导入相同的结果
+	if req.DryRun {
+		tx.Rollback()
+		response.DryRun = true
+		dryRunResponse := ImportDryRunResponse{
+			ImportAssetResponse: response,
+			Assets:              make([]ImportPreviewAsset, len(response.Assets)),
+		}
+		for i := range response.Assets {
+			dryRunResponse.Assets[i].Asset = response.Assets[i]
+			if !response.generatedNos[i] {
+				dryRunResponse.Assets[i].AssetNo = response.Assets[i].AssetNo
+			}
+		}
+		utils.Success(c, dryRunResponse)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// GetImportTemplate 下载资产导入模板，按当前分类及其自定义属性生成
+func GetImportTemplate(c *gin.Context) {
+	var req ImportTemplateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if req.CategoryID != nil {
+		var category models.Category
+		if err := global.DB.First(&category, *req.CategoryID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.CATEGORY_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	lookup, err := loadImportLookup(global.DB)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	f, err := lookup.buildImportTemplate(req.CategoryID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	defer f.Close()
+
+	// 设置响应头
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", "attachment; filename=资产导入模板.xlsx")
+
+	// 写入响应
+	if err := f.Write(c.Writer); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+}
+
+// importAssets 在事务中逐条导入资产，单条失败不影响其他数据，由调用方提交或回滚事务
+func importAssets(tx *gorm.DB, scope *auth.DataScope, reqs []CreateAssetRequest) ImportAssetResponse {
 	response := ImportAssetResponse{
 		Errors: make([]ImportAssetError, 0),
 		Assets: make([]models.Asset, 0),
 	}
 
-	// 开始事务
-	tx := global.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for i, assetReq := range req.Assets {
+	for i, assetReq := range reqs {
 		// 验证单个资产数据
 		if err := validate.Struct(&assetReq); err != nil {
 			response.FailedCount++
@@ -485,23 +1035,45 @@ func ImportAssets(c *gin.Context) {
 			})
 			continue
 		}
-
-		// 检查资产编号是否已存在
-		var existingAsset models.Asset
-		if err := tx.Where("asset_no = ?", assetReq.AssetNo).First(&existingAsset).Error; err == nil {
+		if err := depreciation.CheckPartial(assetReq.DepreciationMethod, assetReq.UsefulLifeMonths); err != nil {
 			response.FailedCount++
 			response.Errors = append(response.Errors, ImportAssetError{
 				Index:   i,
 				AssetNo: assetReq.AssetNo,
-				Error:   "资产编号已存在",
+				Error:   err.Error(),
 			})
 			continue
-		} else if err != gorm.ErrRecordNotFound {
+		}
+
+		// 检查资产编号是否已存在（未填写时稍后自动生成）
+		if assetReq.AssetNo != "" {
+			var existingAsset models.Asset
+			if err := tx.Where("asset_no = ?", assetReq.AssetNo).First(&existingAsset).Error; err == nil {
+				response.FailedCount++
+				response.Errors = append(response.Errors, ImportAssetError{
+					Index:   i,
+					AssetNo: assetReq.AssetNo,
+					Error:   "资产编号已存在",
+				})
+				continue
+			} else if err != gorm.ErrRecordNotFound {
+				response.FailedCount++
+				response.Errors = append(response.Errors, ImportAssetError{
+					Index:   i,
+					AssetNo: assetReq.AssetNo,
+					Error:   "数据库查询错误",
+				})
+				continue
+			}
+		}
+
+		// 校验初始状态
+		if err := models.CheckInitialAssetStatus(assetReq.Status); err != nil {
 			response.FailedCount++
 			response.Errors = append(response.Errors, ImportAssetError{
 				Index:   i,
 				AssetNo: assetReq.AssetNo,
-				Error:   "数据库查询错误",
+				Error:   err.Error(),
 			})
 			continue
 		}
@@ -518,10 +1090,25 @@ func ImportAssets(c *gin.Context) {
 			continue
 		}
 
+		// 部门受限用户未指定部门时默认归属本部门
+		if assetReq.DepartmentID == nil && !scope.All {
+			assetReq.DepartmentID = scope.DepartmentID
+		}
+		if !scope.CanAccessDepartment(assetReq.DepartmentID) {
+			response.FailedCount++
+			response.Errors = append(response.Errors, ImportAssetError{
+				Index:   i,
+				AssetNo: assetReq.AssetNo,
+				Error:   "无权在该部门下创建资产",
+			})
+			continue
+		}
+
 		// 验证部门是否存在（如果提供了部门ID）
+		var department *models.Department
 		if assetReq.DepartmentID != nil {
-			var department models.Department
-			if err := tx.First(&department, *assetReq.DepartmentID).Error; err != nil {
+			department = &models.Department{}
+			if err := tx.First(department, *assetReq.DepartmentID).Error; err != nil {
 				response.FailedCount++
 				response.Errors = append(response.Errors, ImportAssetError{
 					Index:   i,
@@ -532,20 +1119,16 @@ func ImportAssets(c *gin.Context) {
 			}
 		}
 
-		// 转换自定义属性为JSON
-		var customAttributesJSON []byte
-		if assetReq.CustomAttributes != nil {
-			var err error
-			customAttributesJSON, err = json.Marshal(assetReq.CustomAttributes)
-			if err != nil {
-				response.FailedCount++
-				response.Errors = append(response.Errors, ImportAssetError{
-					Index:   i,
-					AssetNo: assetReq.AssetNo,
-					Error:   "自定义属性格式错误",
-				})
-				continue
-			}
+		// 按分类模板校验自定义属性
+		customAttributesJSON, err := buildCustomAttributes(&category, assetReq.CustomAttributes)
+		if err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, ImportAssetError{
+				Index:   i,
+				AssetNo: assetReq.AssetNo,
+				Error:   err.Error(),
+			})
+			continue
 		}
 
 		// 转换采购日期
@@ -554,6 +1137,20 @@ func ImportAssets(c *gin.Context) {
 			purchaseDate = &assetReq.PurchaseDate.Time
 		}
 
+		// 未填写资产编号时按编号规则生成，流水号随导入事务一起提交
+		generatedNo := assetReq.AssetNo == ""
+		if generatedNo {
+			assetReq.AssetNo, err = numbering.Next(tx, numbering.Context{Category: &category, Department: department})
+			if err != nil {
+				response.FailedCount++
+				response.Errors = append(response.Errors, ImportAssetError{
+					Index: i,
+					Error: err.Error(),
+				})
+				continue
+			}
+		}
+
 		// 创建资产
 		asset := models.Asset{
 			AssetNo:           assetReq.AssetNo,
@@ -573,6 +1170,10 @@ func ImportAssets(c *gin.Context) {
 			Description:       assetReq.Description,
 			ImageURL:          assetReq.ImageURL,
 			CustomAttributes:  customAttributesJSON,
+
+			DepreciationMethod: assetReq.DepreciationMethod,
+			UsefulLifeMonths:   assetReq.UsefulLifeMonths,
+			SalvageRate:        assetReq.SalvageRate,
 		}
 
 		// 设置默认状态
@@ -592,39 +1193,17 @@ func ImportAssets(c *gin.Context) {
 
 		response.SuccessCount++
 		response.Assets = append(response.Assets, asset)
+		response.generatedNos = append(response.generatedNos, generatedNo)
 	}
 
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		utils.InternalError(c, err)
-		return
-	}
-
-	utils.Success(c, response)
+	return response
 }
 
 // ExportAssets 导出资产
 func ExportAssets(c *gin.Context) {
-	// 解析筛选条件
-	var filters AssetFilters
-	var req utils.PaginationRequest
-	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
-		utils.ValidationError(c, err.Error())
-		return
-	}
-
-	// 构建查询
-	query := global.DB.Model(&models.Asset{}).
-		Preload("Category").
-		Preload("Department")
-
-	// 应用筛选条件
-	query = applyAssetFilters(query, filters)
-
-	// 获取所有资产
-	var assets []models.Asset
-	if err := query.Find(&assets).Error; err != nil {
-		utils.InternalError(c, err)
+	// 获取筛选后的所有资产
+	assets, ok := findFilteredAssets(c)
+	if !ok {
 		return
 	}
 
@@ -776,6 +1355,12 @@ func BatchUpdateAssets(c *gin.Context) {
 		UpdatedAssets: make([]models.Asset, 0),
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	// 开始事务
 	tx := global.DB.Begin()
 	defer func() {
@@ -784,9 +1369,9 @@ func BatchUpdateAssets(c *gin.Context) {
 		}
 	}()
 
-	// 验证所有资产是否存在
+	// 验证所有资产是否存在（数据范围外的资产视为不存在）
 	var existingAssets []models.Asset
-	if err := tx.Where("id IN ?", req.AssetIDs).Find(&existingAssets).Error; err != nil {
+	if err := scope.Apply(tx.Where("id IN ?", req.AssetIDs)).Find(&existingAssets).Error; err != nil {
 		tx.Rollback()
 		utils.InternalError(c, err)
 		return
@@ -809,15 +1394,18 @@ func BatchUpdateAssets(c *gin.Context) {
 		}
 	}
 
-	// 构建更新数据
-	updates := make(map[string]interface{})
-	if req.Updates.Status != nil {
-		updates["status"] = *req.Updates.Status
-	}
-	if req.Updates.DepartmentID != nil {
+	// 验证调入部门
+	update := req.Updates
+	if update.DepartmentID != nil {
+		if !scope.CanAccessDepartment(update.DepartmentID) {
+			tx.Rollback()
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权将资产调整到该部门", nil)
+			return
+		}
+
 		// 验证部门是否存在
 		var department models.Department
-		if err := tx.First(&department, *req.Updates.DepartmentID).Error; err != nil {
+		if err := tx.First(&department, *update.DepartmentID).Error; err != nil {
 			tx.Rollback()
 			if err == gorm.ErrRecordNotFound {
 				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
@@ -826,31 +1414,44 @@ func BatchUpdateAssets(c *gin.Context) {
 			}
 			return
 		}
-		updates["department_id"] = *req.Updates.DepartmentID
-	}
-	if req.Updates.Location != nil {
-		updates["location"] = *req.Updates.Location
-	}
-	if req.Updates.ResponsiblePerson != nil {
-		updates["responsible_person"] = *req.Updates.ResponsiblePerson
 	}
 
-	// 批量更新存在的资产
-	validAssetIDs := make([]uint, 0)
+	// 批量更新存在的资产，已冻结、调拨中或状态流转不合法的资产单独记录失败
+	validAssets := make([]models.Asset, 0)
 	for _, id := range req.AssetIDs {
-		if _, exists := assetMap[id]; exists {
-			validAssetIDs = append(validAssetIDs, id)
+		asset, exists := assetMap[id]
+		if !exists {
+			continue
+		}
+		reason, err := models.AssetUpdateBlockedReason(tx, &asset, &update)
+		if err != nil {
+			tx.Rollback()
+			utils.InternalError(c, err)
+			return
+		}
+		if reason != "" {
+			response.FailedCount++
+			response.Errors = append(response.Errors, BatchUpdateError{
+				AssetID: id,
+				Error:   reason,
+			})
+			continue
 		}
+		validAssets = append(validAssets, asset)
 	}
 
-	if len(validAssetIDs) > 0 && len(updates) > 0 {
-		if err := tx.Model(&models.Asset{}).Where("id IN ?", validAssetIDs).Updates(updates).Error; err != nil {
+	if len(validAssets) > 0 && len(update.Columns()) > 0 {
+		if err := models.ApplyAssetBatchUpdate(tx, validAssets, &update, auth.GetOperator(c), "批量更新时直接变更部门"); err != nil {
 			tx.Rollback()
 			utils.InternalError(c, err)
 			return
 		}
 
 		// 获取更新后的资产
+		validAssetIDs := make([]uint, len(validAssets))
+		for i := range validAssets {
+			validAssetIDs[i] = validAssets[i].ID
+		}
 		var updatedAssets []models.Asset
 		if err := tx.Preload("Category").Preload("Department").Where("id IN ?", validAssetIDs).Find(&updatedAssets).Error; err != nil {
 			tx.Rollback()
@@ -895,6 +1496,12 @@ func BatchDeleteAssets(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	response := BatchDeleteAssetsResponse{
 		SuccessCount:    0,
 		FailedCount:     0,
@@ -911,9 +1518,9 @@ func BatchDeleteAssets(c *gin.Context) {
 	}()
 
 	for _, assetID := range req.AssetIDs {
-		// 查找资产
+		// 查找资产（数据范围外的资产视为不存在）
 		var asset models.Asset
-		if err := tx.First(&asset, assetID).Error; err != nil {
+		if err := scope.Apply(tx).First(&asset, assetID).Error; err != nil {
 			response.FailedCount++
 			if err == gorm.ErrRecordNotFound {
 				response.Errors = append(response.Errors, BatchDeleteError{
@@ -929,24 +1536,26 @@ func BatchDeleteAssets(c *gin.Context) {
 			continue
 		}
 
-		// 检查是否有未归还的借用记录
-		var borrowCount int64
-		if err := tx.Model(&models.BorrowRecord{}).
-			Where("asset_id = ? AND status = ?", assetID, models.BorrowStatusBorrowed).
-			Count(&borrowCount).Error; err != nil {
+		// 借用中、有未取用预约、处置中或已核销的资产不能删除
+		if err := models.CheckAssetDeletable(tx, &asset, pickupGrace()); err != nil {
+			message := "检查资产状态失败"
+			if models.IsAssetDeleteBlocked(err) {
+				message = err.Error()
+			}
 			response.FailedCount++
 			response.Errors = append(response.Errors, BatchDeleteError{
 				AssetID: assetID,
-				Error:   "检查借用记录失败",
+				Error:   message,
 			})
 			continue
 		}
 
-		if borrowCount > 0 {
+		// 拆下全部组件，并从所属父资产拆下
+		if err := models.DetachAllComponents(tx, &asset, auth.GetOperator(c), "资产已删除"); err != nil {
 			response.FailedCount++
 			response.Errors = append(response.Errors, BatchDeleteError{
 				AssetID: assetID,
-				Error:   "资产正在使用中，无法删除",
+				Error:   "拆卸组件失败",
 			})
 			continue
 		}
@@ -974,6 +1583,160 @@ func BatchDeleteAssets(c *gin.Context) {
 	utils.Success(c, response)
 }
 
+// GetAssetLabel 获取单个资产标签（PNG）
+func GetAssetLabel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的资产ID")
+		return
+	}
+
+	opts, ok := parseLabelOptions(c)
+	if !ok {
+		return
+	}
+
+	// 查找资产（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	var asset models.Asset
+	if err := scope.DB().Preload("Department").First(&asset, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	urlTemplate, err := models.GetSystemConfigValue(global.DB, labels.ConfigKeyQRURL)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := labels.WritePNG(&buf, labels.NewLabel(&asset, urlTemplate), opts); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置响应头
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=label_%d.png", asset.ID))
+	c.Data(http.StatusOK, "image/png", buf.Bytes())
+}
+
+// PrintAssetLabels 按筛选条件批量生成资产标签，排版为 A4 PDF
+func PrintAssetLabels(c *gin.Context) {
+	opts, ok := parseLabelOptions(c)
+	if !ok {
+		return
+	}
+
+	assets, ok := findFilteredAssets(c)
+	if !ok {
+		return
+	}
+	if len(assets) == 0 {
+		utils.ValidationError(c, "没有符合条件的资产")
+		return
+	}
+	if len(assets) > maxLabelBatch {
+		utils.ValidationError(c, fmt.Sprintf("单次最多打印%d个标签，请缩小筛选范围", maxLabelBatch))
+		return
+	}
+
+	urlTemplate, err := models.GetSystemConfigValue(global.DB, labels.ConfigKeyQRURL)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	items := make([]labels.Label, len(assets))
+	for i := range assets {
+		items[i] = labels.NewLabel(&assets[i], urlTemplate)
+	}
+
+	var buf bytes.Buffer
+	if err := labels.WriteSheet(&buf, items, opts); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置响应头
+	c.Header("Content-Disposition", "attachment; filename=资产标签.pdf")
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// maxLabelBatch 单次批量打印的标签数量上限
+const maxLabelBatch = 1000
+
+// parseLabelOptions 解析标签码制和尺寸参数，失败时已写入响应
+func parseLabelOptions(c *gin.Context) (labels.Options, bool) {
+	var req LabelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return labels.Options{}, false
+	}
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return labels.Options{}, false
+	}
+
+	size, err := labels.ParseSize(req.Size)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return labels.Options{}, false
+	}
+
+	code := req.Code
+	if code == "" {
+		code = labels.CodeQR
+	}
+	return labels.Options{Code: code, Size: size}, true
+}
+
+// findFilteredAssets 按查询参数中的筛选条件获取数据范围内的全部资产，失败时已写入响应
+func findFilteredAssets(c *gin.Context) ([]models.Asset, bool) {
+	// 解析筛选条件
+	var filters AssetFilters
+	var req utils.PaginationRequest
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return nil, false
+	}
+	attributeFilters, err := parseAttributeFilters(c)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return nil, false
+	}
+	filters.Attributes = attributeFilters
+
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+	query := scope.DB().Model(&models.Asset{}).
+		Preload("Category").
+		Preload("Department")
+
+	// 应用筛选条件
+	query = applyAssetFilters(query, filters)
+
+	// 获取所有资产
+	var assets []models.Asset
+	if err := query.Order("asset_no").Find(&assets).Error; err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+	return assets, true
+}
+
 // applyAssetFilters 应用资产筛选条件
 func applyAssetFilters(query *gorm.DB, filters AssetFilters) *gorm.DB {
 	// 通用搜索关键词（同时搜索名称和编号）
@@ -1019,6 +1782,102 @@ func applyAssetFilters(query *gorm.DB, filters AssetFilters) *gorm.DB {
 	if filters.PriceHigh != nil {
 		query = query.Where("purchase_price <= ?", *filters.PriceHigh)
 	}
+	for _, filter := range filters.Attributes {
+		path := attributes.JSONPath(filter.Name)
+		switch filter.Operator {
+		case "gte":
+			query = query.Where("json_extract(custom_attributes, ?) >= ?", path, attributeRangeValue(filter.Value))
+		case "lte":
+			query = query.Where("json_extract(custom_attributes, ?) <= ?", path, attributeRangeValue(filter.Value))
+		default:
+			query = query.Where("json_extract(custom_attributes, ?) IN ?", path, attributeMatchValues(filter.Value))
+		}
+	}
 
 	return query
 }
+
+// attributeKeyPrefix 自定义属性在筛选和排序参数中的前缀
+const attributeKeyPrefix = "attr."
+
+// attributeColumn 获取自定义属性的排序表达式，调用前须确认字段名合法
+func attributeColumn(name string) string {
+	return "json_extract(custom_attributes, '" + attributes.JSONPath(name) + "')"
+}
+
+// parseAttributeFilters 解析 filters[attr.字段名]、filters[attr.字段名.gte] 和 filters[attr.字段名.lte] 筛选参数
+func parseAttributeFilters(c *gin.Context) ([]AttributeFilter, error) {
+	var filters []AttributeFilter
+	for key, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(key, "filters[") || !strings.HasSuffix(key, "]") || len(values) == 0 || values[0] == "" {
+			continue
+		}
+		name, ok := strings.CutPrefix(key[len("filters["):len(key)-1], attributeKeyPrefix)
+		if !ok {
+			continue
+		}
+
+		operator := "eq"
+		for _, op := range []string{"gte", "lte"} {
+			if trimmed, found := strings.CutSuffix(name, "."+op); found {
+				name, operator = trimmed, op
+				break
+			}
+		}
+		if !attributes.IsValidFieldName(name) {
+			return nil, fmt.Errorf("不支持的自定义属性筛选: %s", key)
+		}
+		filters = append(filters, AttributeFilter{Name: name, Operator: operator, Value: values[0]})
+	}
+	return filters, nil
+}
+
+// attributeMatchValues 自定义属性等值匹配的候选值：原始文本，以及可能存储的数字或布尔值
+func attributeMatchValues(value string) []interface{} {
+	candidates := []interface{}{value}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		candidates = append(candidates, number)
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		candidates = append(candidates, 1)
+	case "false":
+		candidates = append(candidates, 0)
+	}
+	return candidates
+}
+
+// attributeRangeValue 自定义属性范围比较的值，数字按数值比较，其他（如日期）按文本比较
+func attributeRangeValue(value string) interface{} {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	return value
+}
+
+// buildCustomAttributes 按资产分类的属性模板校验自定义属性并序列化
+func buildCustomAttributes(category *models.Category, values map[string]interface{}) (datatypes.JSON, error) {
+	template, err := attributes.ParseTemplate(category.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return attributes.Marshal(template, values)
+}
+
+// customAttributesError 返回自定义属性校验错误，字段错误逐项返回
+func customAttributesError(c *gin.Context, err error) {
+	var fieldErrs attributes.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		utils.ValidationError(c, fieldErrs)
+		return
+	}
+	utils.ValidationError(c, err.Error())
+}
+
+// pickupGrace 预约开始后的取用宽限时间
+func pickupGrace() time.Duration {
+	return time.Duration(global.AppConfig.ReservationPickupGraceHours) * time.Hour
+}
@@ -0,0 +1,672 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/attributes"
+	"asset-management-system/server/pkg/spreadsheet"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportRows      = 5000 // 单个文件最多导入的数据行数
+	headerDetectRows   = 10   // 自动识别表头时扫描的行数
+	minHeaderMatches   = 2    // 自动识别表头时至少匹配的标准列数
+	importIgnoreColumn = "-"  // 列映射中表示忽略该列
+)
+
+// importField 导入文件中的标准列
+type importField struct {
+	Field    string   // 资产字段名，与 CreateAssetRequest 的 JSON 字段一致
+	Label    string   // 模板表头
+	Required bool     // 是否必须存在该列
+	Aliases  []string // 自动识别表头时可接受的其他写法
+}
+
+// importFields 导入文件支持的标准列，表头与导出文件一致
+var importFields = []importField{
+	{Field: "asset_no", Label: "资产编号", Aliases: []string{"编号", "资产编码"}},
+	{Field: "name", Label: "资产名称", Required: true, Aliases: []string{"名称"}},
+	{Field: "category", Label: "分类", Required: true, Aliases: []string{"资产分类", "分类编码", "分类名称", "category_id"}},
+	{Field: "department", Label: "部门", Aliases: []string{"所属部门", "使用部门", "部门编码", "部门名称", "department_id"}},
+	{Field: "brand", Label: "品牌"},
+	{Field: "model", Label: "型号", Aliases: []string{"规格型号"}},
+	{Field: "serial_number", Label: "序列号", Aliases: []string{"sn"}},
+	{Field: "purchase_date", Label: "采购日期", Aliases: []string{"购买日期", "购置日期"}},
+	{Field: "purchase_price", Label: "采购价格", Aliases: []string{"价格", "购买价格", "原值", "金额"}},
+	{Field: "supplier", Label: "供应商"},
+	{Field: "warranty_period", Label: "保修期(月)", Aliases: []string{"保修期", "保修月数"}},
+	{Field: "status", Label: "状态", Aliases: []string{"资产状态"}},
+	{Field: "location", Label: "位置", Aliases: []string{"存放位置", "存放地点"}},
+	{Field: "responsible_person", Label: "责任人", Aliases: []string{"负责人", "使用人"}},
+	{Field: "description", Label: "描述", Aliases: []string{"备注", "说明"}},
+	{Field: "image_url", Label: "图片地址", Aliases: []string{"图片"}},
+	{Field: "depreciation_method", Label: "折旧方法"},
+	{Field: "useful_life_months", Label: "使用年限(月)", Aliases: []string{"使用寿命(月)", "折旧月数"}},
+	{Field: "salvage_rate", Label: "残值率"},
+}
+
+// importStatusLabels 资产状态的中文名称
+var importStatusLabels = map[string]models.AssetStatus{
+	"可用":  models.AssetStatusAvailable,
+	"借用中": models.AssetStatusBorrowed,
+	"维护中": models.AssetStatusMaintenance,
+	"已报废": models.AssetStatusScrapped,
+}
+
+// importDepreciationLabels 折旧方法的中文名称
+var importDepreciationLabels = map[string]models.DepreciationMethod{
+	"直线法":     models.DepreciationMethodStraightLine,
+	"双倍余额递减法": models.DepreciationMethodDoubleDeclining,
+	"年数总和法":   models.DepreciationMethodSumOfYears,
+}
+
+// attributeHeaderPattern 自定义属性列表头，如 attr.ram 或 内存(attr.ram)
+var attributeHeaderPattern = regexp.MustCompile(`(?i)attr\.([\p{L}\p{N}_-]+)`)
+
+// importLookup 导入时按编码、名称或ID查找分类和部门
+type importLookup struct {
+	categories      []models.Category
+	departments     []models.Department
+	attributeLabels map[string][]string // 自定义属性显示名称 -> 字段名
+}
+
+// loadImportLookup 加载分类、部门和分类属性模板
+func loadImportLookup(db *gorm.DB) (*importLookup, error) {
+	lookup := &importLookup{attributeLabels: make(map[string][]string)}
+	if err := db.Order("id").Find(&lookup.categories).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("id").Find(&lookup.departments).Error; err != nil {
+		return nil, err
+	}
+
+	for _, category := range lookup.categories {
+		template, err := attributes.ParseTemplate(category.Attributes)
+		if err != nil {
+			continue
+		}
+		for _, field := range template.Fields {
+			if field.Label == "" || slices.Contains(lookup.attributeLabels[field.Label], field.Name) {
+				continue
+			}
+			lookup.attributeLabels[field.Label] = append(lookup.attributeLabels[field.Label], field.Name)
+		}
+	}
+	return lookup, nil
+}
+
+// category 按编码、名称或ID查找分类，名称不唯一时要求使用编码
+func (l *importLookup) category(value string) (*models.Category, error) {
+	var matches []*models.Category
+	for i := range l.categories {
+		if strings.EqualFold(l.categories[i].Code, value) {
+			return &l.categories[i], nil
+		}
+		if l.categories[i].Name == value {
+			matches = append(matches, &l.categories[i])
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+			for i := range l.categories {
+				if uint64(l.categories[i].ID) == id {
+					return &l.categories[i], nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("分类不存在: %s", value)
+	default:
+		return nil, fmt.Errorf("分类名称不唯一，请使用分类编码: %s", value)
+	}
+}
+
+// department 按编码、名称或ID查找部门，名称不唯一时要求使用编码
+func (l *importLookup) department(value string) (*models.Department, error) {
+	var matches []*models.Department
+	for i := range l.departments {
+		if strings.EqualFold(l.departments[i].Code, value) {
+			return &l.departments[i], nil
+		}
+		if l.departments[i].Name == value {
+			matches = append(matches, &l.departments[i])
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+			for i := range l.departments {
+				if uint64(l.departments[i].ID) == id {
+					return &l.departments[i], nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("部门不存在: %s", value)
+	default:
+		return nil, fmt.Errorf("部门名称不唯一，请使用部门编码: %s", value)
+	}
+}
+
+// parseImportMapping 解析列映射 JSON，键为表头文字或列名，值为资产字段名、attr.属性名，或 "-"/空值表示忽略该列
+func parseImportMapping(raw string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+		return nil, fmt.Errorf("列映射格式错误，应为 {\"表头或列名\": \"字段名\"}: %v", err)
+	}
+
+	normalized := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		column, field = strings.TrimSpace(column), strings.TrimSpace(field)
+		if field == "" {
+			field = importIgnoreColumn
+		}
+		if field != importIgnoreColumn && !isImportField(field) {
+			return nil, fmt.Errorf("列 %s 映射的字段不存在: %s", column, field)
+		}
+		normalized[column] = field
+	}
+	return normalized, nil
+}
+
+// isImportField 判断是否为可导入的字段名
+func isImportField(field string) bool {
+	if name, ok := strings.CutPrefix(field, attributeKeyPrefix); ok {
+		return attributes.IsValidFieldName(name)
+	}
+	for _, f := range importFields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// importFieldLabel 获取字段的中文名称，自定义属性返回原字段名
+func importFieldLabel(field string) string {
+	for _, f := range importFields {
+		if f.Field == field {
+			return f.Label
+		}
+	}
+	return field
+}
+
+// normalizeHeader 规范化表头文字：去除空白和必填标记，统一括号和大小写
+func normalizeHeader(header string) string {
+	header = strings.NewReplacer(" ", "", "　", "", "*", "", "（", "(", "）", ")").Replace(header)
+	return strings.ToLower(header)
+}
+
+// resolveHeader 按表头文字识别字段，无法识别时返回空字符串
+func (l *importLookup) resolveHeader(header string) string {
+	if header == "" {
+		return ""
+	}
+	if match := attributeHeaderPattern.FindStringSubmatch(header); match != nil {
+		return attributeKeyPrefix + match[1]
+	}
+
+	normalized := normalizeHeader(header)
+	for _, f := range importFields {
+		if normalized == f.Field || normalized == normalizeHeader(f.Label) {
+			return f.Field
+		}
+		for _, alias := range f.Aliases {
+			if normalized == normalizeHeader(alias) {
+				return f.Field
+			}
+		}
+	}
+
+	// 自定义属性显示名称在所有分类中唯一时按显示名称识别
+	if names := l.attributeLabels[strings.TrimSpace(header)]; len(names) == 1 {
+		return attributeKeyPrefix + names[0]
+	}
+	return ""
+}
+
+// mapColumns 确定每一列对应的字段：列映射优先（列名优先于表头文字），其次按表头自动识别
+func (l *importLookup) mapColumns(header []string, width int, mapping map[string]string) []string {
+	fields := make([]string, width)
+	for i := range fields {
+		text := ""
+		if i < len(header) {
+			text = header[i]
+		}
+		if field, ok := mapping[spreadsheet.ColumnName(i)]; ok {
+			fields[i] = field
+		} else if field, ok := mapping[text]; ok && text != "" {
+			fields[i] = field
+		} else {
+			fields[i] = l.resolveHeader(text)
+		}
+		if fields[i] == importIgnoreColumn {
+			fields[i] = ""
+		}
+	}
+	return fields
+}
+
+// detectImportColumns 确定表头所在行（从0开始）及每一列对应的字段
+// headerRow 为0时在前几行中查找第一个能识别出多个标准列的行
+func (l *importLookup) detectImportColumns(rows [][]string, headerRow int, mapping map[string]string) (int, []string, error) {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	headerIndex := -1
+	if headerRow > 0 {
+		if headerRow > len(rows) {
+			return 0, nil, fmt.Errorf("表头行 %d 超出文件行数", headerRow)
+		}
+		headerIndex = headerRow - 1
+	} else {
+		for i := 0; i < len(rows) && i < headerDetectRows; i++ {
+			matched := 0
+			for _, field := range l.mapColumns(rows[i], width, mapping) {
+				if field != "" && !strings.HasPrefix(field, attributeKeyPrefix) {
+					matched++
+				}
+			}
+			if matched >= minHeaderMatches {
+				headerIndex = i
+				break
+			}
+		}
+		if headerIndex < 0 {
+			return 0, nil, fmt.Errorf("未能识别表头行，请通过 header_row 指定表头所在行，或通过 mapping 指定列映射")
+		}
+	}
+
+	fields := l.mapColumns(rows[headerIndex], width, mapping)
+	seen := make(map[string]int)
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+		if previous, exists := seen[field]; exists {
+			return 0, nil, fmt.Errorf("列 %s 和列 %s 都对应字段 %s", spreadsheet.ColumnName(previous), spreadsheet.ColumnName(i), importFieldLabel(field))
+		}
+		seen[field] = i
+	}
+
+	var missing []string
+	for _, f := range importFields {
+		if _, exists := seen[f.Field]; f.Required && !exists {
+			missing = append(missing, f.Label)
+		}
+	}
+	if len(missing) > 0 {
+		return 0, nil, fmt.Errorf("缺少必需的列: %s", strings.Join(missing, "、"))
+	}
+	return headerIndex, fields, nil
+}
+
+// buildImportRequest 将一行数据转换为创建资产请求，返回逐列的错误信息
+func (l *importLookup) buildImportRequest(header, row []string, fields []string) (CreateAssetRequest, []string) {
+	req := CreateAssetRequest{Status: models.AssetStatusAvailable}
+	var category *models.Category
+	var errs []string
+
+	for i, field := range fields {
+		if field == "" || i >= len(row) || row[i] == "" {
+			continue
+		}
+		value := row[i]
+
+		var err error
+		switch field {
+		case "asset_no":
+			req.AssetNo = value
+		case "name":
+			req.Name = value
+		case "brand":
+			req.Brand = value
+		case "model":
+			req.Model = value
+		case "serial_number":
+			req.SerialNumber = value
+		case "supplier":
+			req.Supplier = value
+		case "location":
+			req.Location = value
+		case "responsible_person":
+			req.ResponsiblePerson = value
+		case "description":
+			req.Description = value
+		case "image_url":
+			req.ImageURL = value
+		case "category":
+			if category, err = l.category(value); err == nil {
+				req.CategoryID = category.ID
+			}
+		case "department":
+			var department *models.Department
+			if department, err = l.department(value); err == nil {
+				req.DepartmentID = &department.ID
+			}
+		case "purchase_date":
+			var date FlexibleTime
+			if date.Time, err = spreadsheet.ParseDate(value); err == nil {
+				req.PurchaseDate = &date
+			}
+		case "purchase_price":
+			var price float64
+			if price, err = spreadsheet.ParseNumber(value); err == nil {
+				req.PurchasePrice = &price
+			}
+		case "warranty_period":
+			req.WarrantyPeriod, err = parseImportInt(value)
+		case "useful_life_months":
+			req.UsefulLifeMonths, err = parseImportInt(value)
+		case "salvage_rate":
+			req.SalvageRate, err = parseImportRate(value)
+		case "status":
+			if status, ok := importStatusLabels[value]; ok {
+				req.Status = status
+			} else {
+				req.Status = models.AssetStatus(strings.ToLower(value))
+			}
+		case "depreciation_method":
+			if method, ok := importDepreciationLabels[value]; ok {
+				req.DepreciationMethod = method
+			} else {
+				req.DepreciationMethod = models.DepreciationMethod(strings.ToLower(value))
+			}
+		default:
+			if req.CustomAttributes == nil {
+				req.CustomAttributes = make(map[string]interface{})
+			}
+			req.CustomAttributes[strings.TrimPrefix(field, attributeKeyPrefix)] = value
+		}
+		if err != nil {
+			errs = append(errs, importColumnTitle(header, i)+": "+err.Error())
+		}
+	}
+
+	// Excel 中的日期型自定义属性按日期序列号读取，按分类模板转换为日期文本
+	if category != nil && len(req.CustomAttributes) > 0 {
+		if template, err := attributes.ParseTemplate(category.Attributes); err == nil {
+			for _, field := range template.Fields {
+				text, ok := req.CustomAttributes[field.Name].(string)
+				if !ok || field.Type != models.FieldTypeDate {
+					continue
+				}
+				if date, err := spreadsheet.ParseDate(text); err == nil {
+					req.CustomAttributes[field.Name] = date.Format(attributes.DateLayout)
+				}
+			}
+		}
+	}
+	return req, errs
+}
+
+// importColumnTitle 错误信息中的列名称，如 "C列(分类)"
+func importColumnTitle(header []string, index int) string {
+	title := spreadsheet.ColumnName(index) + "列"
+	if index < len(header) && header[index] != "" {
+		title += "(" + header[index] + ")"
+	}
+	return title
+}
+
+// parseImportInt 解析整数单元格
+func parseImportInt(value string) (*int, error) {
+	number, err := spreadsheet.ParseNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	if number != math.Trunc(number) {
+		return nil, fmt.Errorf("必须为整数: %s", value)
+	}
+	result := int(number)
+	return &result, nil
+}
+
+// parseImportRate 解析比率单元格，支持 5% 和 0.05 两种写法
+func parseImportRate(value string) (*float64, error) {
+	text, percent := strings.CutSuffix(value, "%")
+	rate, err := spreadsheet.ParseNumber(text)
+	if err != nil {
+		return nil, err
+	}
+	if percent {
+		rate /= 100
+	}
+	return &rate, nil
+}
+
+// isBlankRow 判断是否为空行
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// 导入模板的工作表名称
+const (
+	templateSheetAssets      = "资产导入"
+	templateSheetCategories  = "分类"
+	templateSheetAttributes  = "属性字段"
+	templateSheetDepartments = "部门"
+)
+
+// fieldTypeLabels 自定义属性类型的中文名称
+var fieldTypeLabels = map[string]string{
+	models.FieldTypeText:    "文本",
+	models.FieldTypeNumber:  "数字",
+	models.FieldTypeDate:    "日期",
+	models.FieldTypeSelect:  "选项",
+	models.FieldTypeBoolean: "是/否",
+}
+
+// buildImportTemplate 按当前分类及其属性模板生成导入模板，categoryID 不为空时只包含该分类的自定义属性列
+func (l *importLookup) buildImportTemplate(categoryID *uint) (*excelize.File, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", templateSheetAssets); err != nil {
+		return nil, err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E0E0E0"}, Pattern: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 资产导入表：标准列在前，必填列以 * 标记，其后为各分类的自定义属性列
+	var headers []string
+	for _, field := range importFields {
+		if field.Required {
+			headers = append(headers, "*"+field.Label)
+		} else {
+			headers = append(headers, field.Label)
+		}
+	}
+
+	var categoryRows, attributeRows [][]interface{}
+	attributeColumns := make(map[string]int)
+	selectOptions := make(map[string][]string)
+	for _, category := range l.categories {
+		template, err := attributes.ParseTemplate(category.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		parentCode := ""
+		if category.ParentID != nil {
+			for _, parent := range l.categories {
+				if parent.ID == *category.ParentID {
+					parentCode = parent.Code
+				}
+			}
+		}
+		var columnNames []string
+		for _, field := range template.Fields {
+			columnNames = append(columnNames, attributeHeader(field))
+		}
+		categoryRows = append(categoryRows, []interface{}{category.Code, category.Name, parentCode, strings.Join(columnNames, "、")})
+
+		if categoryID != nil && category.ID != *categoryID {
+			continue
+		}
+		for _, field := range template.Fields {
+			required := "否"
+			if field.Required {
+				required = "是"
+			}
+			defaultValue := ""
+			if field.DefaultValue != nil {
+				defaultValue = fmt.Sprint(field.DefaultValue)
+			}
+			attributeRows = append(attributeRows, []interface{}{
+				category.Code, category.Name, attributeHeader(field), field.Name, field.Label,
+				fieldTypeLabels[field.Type], required, strings.Join(field.Options, "、"), defaultValue,
+			})
+
+			if _, exists := attributeColumns[field.Name]; exists {
+				// 多个分类定义了同名字段时不再提供下拉选项
+				delete(selectOptions, field.Name)
+				continue
+			}
+			attributeColumns[field.Name] = len(headers)
+			headers = append(headers, attributeHeader(field))
+			if field.Type == models.FieldTypeSelect {
+				selectOptions[field.Name] = field.Options
+			}
+		}
+	}
+
+	var departmentRows [][]interface{}
+	for _, department := range l.departments {
+		departmentRows = append(departmentRows, []interface{}{department.Code, department.Name})
+	}
+
+	if err := writeTemplateSheet(f, templateSheetAssets, headers, nil, headerStyle); err != nil {
+		return nil, err
+	}
+	if err := writeTemplateSheet(f, templateSheetCategories, []string{"分类编码", "分类名称", "上级分类编码", "自定义属性列"}, categoryRows, headerStyle); err != nil {
+		return nil, err
+	}
+	if err := writeTemplateSheet(f, templateSheetAttributes, []string{"分类编码", "分类名称", "表头", "字段名", "显示名称", "类型", "必填", "选项", "默认值"}, attributeRows, headerStyle); err != nil {
+		return nil, err
+	}
+	if err := writeTemplateSheet(f, templateSheetDepartments, []string{"部门编码", "部门名称"}, departmentRows, headerStyle); err != nil {
+		return nil, err
+	}
+
+	// 下拉选项：分类和部门引用对应工作表中的编码，状态、折旧方法和选择类型属性使用固定选项
+	dropLists := map[int][]string{}
+	ranges := map[int]string{}
+	for i, field := range importFields {
+		switch field.Field {
+		case "category":
+			if len(categoryRows) > 0 {
+				ranges[i] = fmt.Sprintf("'%s'!$A$2:$A$%d", templateSheetCategories, len(categoryRows)+1)
+			}
+		case "department":
+			if len(departmentRows) > 0 {
+				ranges[i] = fmt.Sprintf("'%s'!$A$2:$A$%d", templateSheetDepartments, len(departmentRows)+1)
+			}
+		case "status":
+			// 新建资产只能使用初始状态
+			for _, status := range models.InitialAssetStatuses {
+				for label, value := range importStatusLabels {
+					if value == status {
+						dropLists[i] = append(dropLists[i], label)
+					}
+				}
+			}
+		case "depreciation_method":
+			dropLists[i] = []string{"直线法", "双倍余额递减法", "年数总和法"}
+		}
+	}
+	for name, options := range selectOptions {
+		dropLists[attributeColumns[name]] = options
+	}
+
+	for column := range headers {
+		rangeRef, hasRange := ranges[column]
+		options, hasOptions := dropLists[column]
+		if !hasRange && !hasOptions {
+			continue
+		}
+		name := spreadsheet.ColumnName(column)
+		validation := excelize.NewDataValidation(true)
+		validation.Sqref = fmt.Sprintf("%s2:%s%d", name, name, maxImportRows+1)
+		if hasRange {
+			validation.SetSqrefDropList(rangeRef)
+		} else if err := validation.SetDropList(options); err != nil {
+			// 选项过长时不提供下拉
+			continue
+		}
+		if err := f.AddDataValidation(templateSheetAssets, validation); err != nil {
+			return nil, err
+		}
+	}
+
+	f.SetActiveSheet(0)
+	return f, nil
+}
+
+// attributeHeader 自定义属性列的表头，如 内存(attr.ram)
+func attributeHeader(field models.CategoryField) string {
+	if field.Label == "" {
+		return attributeKeyPrefix + field.Name
+	}
+	return field.Label + "(" + attributeKeyPrefix + field.Name + ")"
+}
+
+// writeTemplateSheet 写入模板工作表：表头、数据行、列宽，并冻结表头
+func writeTemplateSheet(f *excelize.File, sheet string, headers []string, rows [][]interface{}, headerStyle int) error {
+	if index, err := f.GetSheetIndex(sheet); err != nil {
+		return err
+	} else if index < 0 {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+
+	values := make([]interface{}, len(headers))
+	for i, header := range headers {
+		values[i] = header
+	}
+	if err := f.SetSheetRow(sheet, "A1", &values); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if err := f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+2), &row); err != nil {
+			return err
+		}
+	}
+
+	last := spreadsheet.ColumnName(len(headers) - 1)
+	if err := f.SetCellStyle(sheet, "A1", last+"1", headerStyle); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", last, 16); err != nil {
+		return err
+	}
+	return f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+}
@@ -0,0 +1,195 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/jobs"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateBatchUpdateJob 提交后台批量更新任务，按资产ID或筛选条件选取目标，进度通过 /api/jobs/:id 查询
+func CreateBatchUpdateJob(c *gin.Context) {
+	var req BatchUpdateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if len(req.Updates.Columns()) == 0 {
+		utils.ValidationError(c, "更新内容不能为空")
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 验证调入部门
+	if req.Updates.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.Updates.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权将资产调整到该部门", nil)
+			return
+		}
+
+		var department models.Department
+		if err := global.DB.First(&department, *req.Updates.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	params, err := json.Marshal(req.Updates)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	job := models.BulkJob{Type: models.BulkJobTypeAssetUpdate, Params: params}
+	submitBatchJob(c, scope, &job, &req.BatchJobTarget)
+}
+
+// CreateBatchDeleteJob 提交后台批量删除任务，按资产ID或筛选条件选取目标，进度通过 /api/jobs/:id 查询
+func CreateBatchDeleteJob(c *gin.Context) {
+	var req BatchDeleteJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	job := models.BulkJob{Type: models.BulkJobTypeAssetDelete}
+	submitBatchJob(c, scope, &job, &req.BatchJobTarget)
+}
+
+// submitBatchJob 解析目标资产并提交任务
+func submitBatchJob(c *gin.Context, scope *auth.DataScope, job *models.BulkJob, target *BatchJobTarget) {
+	ids, failures, ok := resolveBatchJobTargets(c, scope, target)
+	if !ok {
+		return
+	}
+
+	if target.Filters != nil {
+		filters, err := json.Marshal(target.Filters)
+		if err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		job.Filters = filters
+	}
+	job.CreatedBy = auth.GetOperator(c)
+	if identity := auth.GetIdentity(c); identity != nil {
+		job.CreatedByID = identity.UserID
+	}
+
+	if err := jobs.Submit(global.DB, job, ids, failures); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	middleware.SetAuditTarget(c, "bulk_jobs", job.ID, models.OperationTypeCreate)
+	utils.Success(c, job)
+}
+
+// resolveBatchJobTargets 解析数据范围内的目标资产，不存在或不在数据范围内的资产ID作为失败记录返回，失败时已写入响应
+func resolveBatchJobTargets(c *gin.Context, scope *auth.DataScope, target *BatchJobTarget) ([]uint, []models.BulkJobError, bool) {
+	if (len(target.AssetIDs) > 0) == (target.Filters != nil) {
+		utils.ValidationError(c, "资产ID列表和筛选条件须且只能提供一个")
+		return nil, nil, false
+	}
+
+	if target.Filters != nil {
+		// 不允许以空条件处理全部资产
+		if reflect.ValueOf(*target.Filters).IsZero() {
+			utils.ValidationError(c, "筛选条件不能为空")
+			return nil, nil, false
+		}
+
+		query := applyAssetFilters(scope.DB().Model(&models.Asset{}), *target.Filters)
+		var total int64
+		if err := query.Count(&total).Error; err != nil {
+			utils.InternalError(c, err)
+			return nil, nil, false
+		}
+		if total == 0 {
+			utils.ValidationError(c, "没有符合筛选条件的资产")
+			return nil, nil, false
+		}
+		if total > jobs.MaxTargets {
+			utils.ValidationError(c, fmt.Sprintf("符合条件的资产共 %d 个，单个任务最多支持 %d 个", total, jobs.MaxTargets))
+			return nil, nil, false
+		}
+
+		var ids []uint
+		if err := query.Order("id ASC").Pluck("id", &ids).Error; err != nil {
+			utils.InternalError(c, err)
+			return nil, nil, false
+		}
+		return ids, nil, true
+	}
+
+	// 去重并保持提交顺序
+	requested := make([]uint, 0, len(target.AssetIDs))
+	seen := make(map[uint]bool, len(target.AssetIDs))
+	for _, id := range target.AssetIDs {
+		if !seen[id] {
+			seen[id] = true
+			requested = append(requested, id)
+		}
+	}
+	if len(requested) > jobs.MaxTargets {
+		utils.ValidationError(c, fmt.Sprintf("单个任务最多支持 %d 个资产", jobs.MaxTargets))
+		return nil, nil, false
+	}
+
+	// 分批查询，避免超出数据库参数个数限制
+	found := make(map[uint]bool, len(requested))
+	for start := 0; start < len(requested); start += jobs.ChunkSize {
+		end := start + jobs.ChunkSize
+		if end > len(requested) {
+			end = len(requested)
+		}
+		var ids []uint
+		if err := scope.DB().Model(&models.Asset{}).Where("id IN ?", requested[start:end]).Pluck("id", &ids).Error; err != nil {
+			utils.InternalError(c, err)
+			return nil, nil, false
+		}
+		for _, id := range ids {
+			found[id] = true
+		}
+	}
+
+	ids := make([]uint, 0, len(found))
+	failures := make([]models.BulkJobError, 0)
+	for _, id := range requested {
+		if found[id] {
+			ids = append(ids, id)
+		} else {
+			failures = append(failures, models.BulkJobError{AssetID: id, Error: "资产不存在"})
+		}
+	}
+	return ids, failures, true
+}
@@ -8,15 +8,28 @@ import (
 func RegisterRoutes(r *gin.RouterGroup) {
 	assets := r.Group("/assets")
 	{
-		assets.GET("", GetAssets)                            // 获取资产列表
-		assets.POST("", CreateAsset)                         // 创建资产
-		assets.POST("/import", ImportAssets)                 // 批量导入资产
-		assets.GET("/export", ExportAssets)                  // 导出资产
-		assets.GET("/check-asset-no/:assetNo", CheckAssetNo) // 检查资产编号是否存在
-		assets.PUT("/batch", BatchUpdateAssets)              // 批量更新资产
-		assets.DELETE("/batch", BatchDeleteAssets)           // 批量删除资产
-		assets.GET("/:id", GetAsset)                         // 获取资产详情
-		assets.PUT("/:id", UpdateAsset)                      // 更新资产
-		assets.DELETE("/:id", DeleteAsset)                   // 删除资产
+		assets.GET("", GetAssets)                                       // 获取资产列表
+		assets.POST("", CreateAsset)                                    // 创建资产
+		assets.POST("/import", ImportAssets)                            // 批量导入资产
+		assets.POST("/import/file", ImportAssetsFile)                   // 从 xlsx/csv 文件导入资产
+		assets.GET("/import/template", GetImportTemplate)               // 下载资产导入模板
+		assets.GET("/export", ExportAssets)                             // 导出资产
+		assets.GET("/labels", PrintAssetLabels)                         // 批量打印资产标签（A4 PDF）
+		assets.GET("/check-asset-no/:assetNo", CheckAssetNo)            // 检查资产编号是否存在
+		assets.PUT("/batch", BatchUpdateAssets)                         // 批量更新资产
+		assets.DELETE("/batch", BatchDeleteAssets)                      // 批量删除资产
+		assets.PUT("/batch/async", CreateBatchUpdateJob)                // 提交后台批量更新任务
+		assets.DELETE("/batch/async", CreateBatchDeleteJob)             // 提交后台批量删除任务
+		assets.GET("/:id", GetAsset)                                    // 获取资产详情
+		assets.GET("/:id/depreciation", GetAssetDepreciation)           // 获取资产折旧计划
+		assets.GET("/:id/transfers", GetAssetTransfers)                 // 获取资产调拨历史
+		assets.GET("/:id/timeline", GetAssetTimeline)                   // 获取资产时间线
+		assets.GET("/:id/label", GetAssetLabel)                         // 获取资产标签（PNG）
+		assets.GET("/:id/components", GetAssetComponents)               // 获取资产组件树
+		assets.PUT("/:id/components", AttachAssetComponent)             // 挂载组件
+		assets.PUT("/:id/components/detach", DetachAssetComponent)      // 拆卸组件
+		assets.GET("/:id/components/history", GetAssetComponentHistory) // 获取组件挂载、拆卸历史
+		assets.PUT("/:id", UpdateAsset)                                 // 更新资产
+		assets.DELETE("/:id", DeleteAsset)                              // 删除资产
 	}
 }
@@ -0,0 +1,585 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 时间线事件类型
+const (
+	TimelineEventCreated           = "created"            // 创建
+	TimelineEventUpdated           = "updated"            // 字段变更
+	TimelineEventStatusChanged     = "status_changed"     // 修改资产状态；按此类型筛选时也返回借出、送修等引起状态变化的事件
+	TimelineEventBorrowed          = "borrowed"           // 借出
+	TimelineEventReturned          = "returned"           // 归还
+	TimelineEventInventory         = "inventory"          // 盘点
+	TimelineEventTransferred       = "transferred"        // 调拨完成
+	TimelineEventMaintenanceOpened = "maintenance_opened" // 送修
+	TimelineEventMaintenanceClosed = "maintenance_closed" // 维修完成
+	TimelineEventDisposed          = "disposed"           // 处置完成
+	TimelineEventComponent         = "component"          // 组件挂载、拆卸
+)
+
+// TimelineEventTypes 全部时间线事件类型
+var TimelineEventTypes = []string{
+	TimelineEventCreated,
+	TimelineEventUpdated,
+	TimelineEventStatusChanged,
+	TimelineEventBorrowed,
+	TimelineEventReturned,
+	TimelineEventInventory,
+	TimelineEventTransferred,
+	TimelineEventMaintenanceOpened,
+	TimelineEventMaintenanceClosed,
+	TimelineEventDisposed,
+	TimelineEventComponent,
+}
+
+// timelineIgnoredFields 比较字段变更时忽略的字段
+var timelineIgnoredFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"category":   true,
+	"department": true,
+}
+
+// timelineCollector 收集一类来源记录生成的事件
+type timelineCollector struct {
+	types   []string
+	collect func(asset *models.Asset) ([]TimelineEvent, error)
+}
+
+// GetAssetTimeline 获取资产时间线：合并操作日志、借用、盘点、调拨、维修、处置和组件记录，按时间排序分页返回
+func GetAssetTimeline(c *gin.Context) {
+	var req TimelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	wanted, err := parseTimelineTypes(req.Types)
+	if err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	asset, ok := findScopedAsset(c, scope, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	// 资产可见即可查看其完整时间线
+	// 引起状态变化的事件在按状态变更筛选时也需要收集
+	collectors := []timelineCollector{
+		{[]string{TimelineEventCreated, TimelineEventUpdated, TimelineEventStatusChanged}, collectAuditEvents},
+		{[]string{TimelineEventBorrowed, TimelineEventReturned, TimelineEventStatusChanged}, collectBorrowEvents},
+		{[]string{TimelineEventInventory}, collectInventoryEvents},
+		{[]string{TimelineEventTransferred}, collectTransferEvents},
+		{[]string{TimelineEventMaintenanceOpened, TimelineEventMaintenanceClosed, TimelineEventStatusChanged}, collectMaintenanceEvents},
+		{[]string{TimelineEventDisposed, TimelineEventStatusChanged}, collectDisposalEvents},
+		{[]string{TimelineEventComponent}, collectComponentEvents},
+	}
+
+	events := make([]TimelineEvent, 0)
+	for _, collector := range collectors {
+		needed := false
+		for _, eventType := range collector.types {
+			needed = needed || wanted[eventType]
+		}
+		if !needed {
+			continue
+		}
+
+		collected, err := collector.collect(asset)
+		if err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		for _, event := range collected {
+			if !wanted[event.Type] && !(wanted[TimelineEventStatusChanged] && event.Status != "") {
+				continue
+			}
+			if req.StartDate != nil && event.OccurredAt.Before(*req.StartDate) {
+				continue
+			}
+			if req.EndDate != nil && event.OccurredAt.After(*req.EndDate) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	// 同一时间的事件保持收集顺序
+	sort.SliceStable(events, func(i, j int) bool {
+		if req.Order == "desc" {
+			return events[i].OccurredAt.After(events[j].OccurredAt)
+		}
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	total := int64(len(events))
+	start := (req.Page - 1) * req.PageSize
+	if start > len(events) {
+		start = len(events)
+	}
+	end := start + req.PageSize
+	if end > len(events) {
+		end = len(events)
+	}
+
+	utils.Success(c, utils.NewPaginationResponse(req.Page, req.PageSize, total, events[start:end]))
+}
+
+// parseTimelineTypes 解析事件类型筛选，为空时返回全部类型
+func parseTimelineTypes(value string) (map[string]bool, error) {
+	wanted := make(map[string]bool)
+	for _, eventType := range strings.Split(value, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType == "" {
+			continue
+		}
+		known := false
+		for _, candidate := range TimelineEventTypes {
+			known = known || candidate == eventType
+		}
+		if !known {
+			return nil, fmt.Errorf("未知的事件类型: %s", eventType)
+		}
+		wanted[eventType] = true
+	}
+
+	if len(wanted) == 0 {
+		for _, eventType := range TimelineEventTypes {
+			wanted[eventType] = true
+		}
+	}
+	return wanted, nil
+}
+
+// collectAuditEvents 从资产操作日志生成创建、字段变更和状态变更事件
+// 挂载、拆卸组件等子路径的请求字段不属于资产，比较时自然被忽略
+func collectAuditEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var logs []models.OperationLog
+	if err := global.DB.
+		Where("table_name = ? AND record_id = ?", "assets", asset.ID).
+		Where("operation IN ?", []models.OperationType{models.OperationTypeCreate, models.OperationTypeUpdate}).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	var events []TimelineEvent
+	created := false
+	for _, log := range logs {
+		if log.Operation == models.OperationTypeCreate {
+			created = true
+			events = append(events, TimelineEvent{
+				Type:        TimelineEventCreated,
+				OccurredAt:  log.CreatedAt,
+				Actor:       log.Operator,
+				Summary:     "创建资产",
+				SourceTable: "operation_logs",
+				SourceID:    log.ID,
+			})
+			continue
+		}
+
+		changes := diffAuditData(log.OldData, log.NewData)
+		var fieldChanges []FieldChange
+		for _, change := range changes {
+			if change.Field != "status" {
+				fieldChanges = append(fieldChanges, change)
+				continue
+			}
+			events = append(events, TimelineEvent{
+				Type:        TimelineEventStatusChanged,
+				OccurredAt:  log.CreatedAt,
+				Actor:       log.Operator,
+				Summary:     fmt.Sprintf("状态由 %v 改为 %v", change.Old, change.New),
+				Status:      models.AssetStatus(fmt.Sprint(change.New)),
+				Changes:     []FieldChange{change},
+				SourceTable: "operation_logs",
+				SourceID:    log.ID,
+			})
+		}
+		if len(fieldChanges) > 0 {
+			fields := make([]string, 0, len(fieldChanges))
+			for _, change := range fieldChanges {
+				fields = append(fields, change.Field)
+			}
+			events = append(events, TimelineEvent{
+				Type:        TimelineEventUpdated,
+				OccurredAt:  log.CreatedAt,
+				Actor:       log.Operator,
+				Summary:     "修改 " + strings.Join(fields, "、"),
+				Changes:     fieldChanges,
+				SourceTable: "operation_logs",
+				SourceID:    log.ID,
+			})
+		}
+	}
+
+	// 导入等未经接口创建的资产没有创建日志，以资产创建时间补充
+	if !created {
+		events = append([]TimelineEvent{{
+			Type:        TimelineEventCreated,
+			OccurredAt:  asset.CreatedAt,
+			Summary:     "创建资产",
+			SourceTable: "assets",
+			SourceID:    asset.ID,
+		}}, events...)
+	}
+	return events, nil
+}
+
+// collectBorrowEvents 从借用记录生成借出、归还事件，操作人取借用记录的操作日志
+// 随父资产借出的组件记录没有自己的日志，取父资产借用记录的操作人
+func collectBorrowEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var records []models.BorrowRecord
+	if err := global.DB.Where("asset_id = ?", asset.ID).Order("borrow_date ASC, id ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	logIDs := make([]uint, 0, len(records))
+	for _, record := range records {
+		logIDs = append(logIDs, borrowLogRecordID(&record))
+	}
+	var logs []models.OperationLog
+	if err := global.DB.
+		Where("table_name = ? AND record_id IN ?", "borrow_records", logIDs).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	// 借用记录归还后不能再修改，最后一条更新日志即为归还操作
+	borrowedBy := make(map[uint]string)
+	returnedBy := make(map[uint]string)
+	for _, log := range logs {
+		switch log.Operation {
+		case models.OperationTypeCreate:
+			borrowedBy[log.RecordID] = log.Operator
+		case models.OperationTypeUpdate:
+			returnedBy[log.RecordID] = log.Operator
+		}
+	}
+
+	var events []TimelineEvent
+	for _, record := range records {
+		details := map[string]interface{}{
+			"borrower_name":        record.BorrowerName,
+			"borrower_contact":     record.BorrowerContact,
+			"department_id":        record.DepartmentID,
+			"expected_return_date": record.ExpectedReturnDate,
+			"purpose":              record.Purpose,
+		}
+		if record.ParentRecordID != nil {
+			details["parent_record_id"] = *record.ParentRecordID
+		}
+
+		logID := borrowLogRecordID(&record)
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventBorrowed,
+			OccurredAt:  record.BorrowDate,
+			Actor:       borrowedBy[logID],
+			Summary:     fmt.Sprintf("借给 %s", record.BorrowerName),
+			Status:      models.AssetStatusBorrowed,
+			SourceTable: "borrow_records",
+			SourceID:    record.ID,
+			Details:     details,
+		})
+		if record.Status == models.BorrowStatusReturned && record.ActualReturnDate != nil {
+			events = append(events, TimelineEvent{
+				Type:        TimelineEventReturned,
+				OccurredAt:  *record.ActualReturnDate,
+				Actor:       returnedBy[logID],
+				Summary:     fmt.Sprintf("%s 归还", record.BorrowerName),
+				Status:      models.AssetStatusAvailable,
+				SourceTable: "borrow_records",
+				SourceID:    record.ID,
+				Details:     details,
+			})
+		}
+	}
+	return events, nil
+}
+
+// borrowLogRecordID 借用记录对应的操作日志记录ID
+func borrowLogRecordID(record *models.BorrowRecord) uint {
+	if record.ParentRecordID != nil {
+		return *record.ParentRecordID
+	}
+	return record.ID
+}
+
+// collectInventoryEvents 从盘点记录生成盘点事件
+func collectInventoryEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var records []models.InventoryRecord
+	if err := global.DB.
+		Preload("Task", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("asset_id = ?", asset.ID).
+		Order("checked_at ASC, id ASC").
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]TimelineEvent, 0, len(records))
+	for _, record := range records {
+		occurredAt := record.CreatedAt
+		if record.CheckedAt != nil {
+			occurredAt = *record.CheckedAt
+		}
+		details := map[string]interface{}{
+			"task_id":         record.TaskID,
+			"task_name":       record.Task.TaskName,
+			"result":          record.Result,
+			"expected_status": record.ExpectedStatus,
+			"actual_status":   record.ActualStatus,
+		}
+		if record.KitRecordID != nil {
+			details["kit_record_id"] = *record.KitRecordID
+		}
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventInventory,
+			OccurredAt:  occurredAt,
+			Actor:       record.CheckedBy,
+			Summary:     fmt.Sprintf("盘点任务「%s」结果：%s", record.Task.TaskName, record.Result),
+			SourceTable: "inventory_records",
+			SourceID:    record.ID,
+			Details:     details,
+		})
+	}
+	return events, nil
+}
+
+// collectTransferEvents 从已完成的调拨单生成调拨事件
+func collectTransferEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var transfers []models.AssetTransfer
+	if err := global.DB.
+		Preload("FromDepartment", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ToDepartment", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("asset_id = ? AND status = ?", asset.ID, models.TransferStatusCompleted).
+		Order("completed_at ASC, id ASC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]TimelineEvent, 0, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.CompletedAt == nil {
+			continue
+		}
+		from, to := "无", ""
+		if transfer.FromDepartment != nil {
+			from = transfer.FromDepartment.Name
+		}
+		if transfer.ToDepartment != nil {
+			to = transfer.ToDepartment.Name
+		}
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventTransferred,
+			OccurredAt:  *transfer.CompletedAt,
+			Actor:       transfer.CompletedBy,
+			Summary:     fmt.Sprintf("调拨：%s → %s", from, to),
+			SourceTable: "asset_transfers",
+			SourceID:    transfer.ID,
+			Details: map[string]interface{}{
+				"from_department_id":      transfer.FromDepartmentID,
+				"to_department_id":        transfer.ToDepartmentID,
+				"from_location":           transfer.FromLocation,
+				"to_location":             transfer.ToLocation,
+				"from_responsible_person": transfer.FromResponsiblePerson,
+				"to_responsible_person":   transfer.ToResponsiblePerson,
+				"reason":                  transfer.Reason,
+			},
+		})
+	}
+	return events, nil
+}
+
+// collectMaintenanceEvents 从维修工单生成送修、维修完成事件
+func collectMaintenanceEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var orders []models.MaintenanceOrder
+	if err := global.DB.Where("asset_id = ?", asset.ID).Order("start_date ASC, id ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	var events []TimelineEvent
+	for _, order := range orders {
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventMaintenanceOpened,
+			OccurredAt:  order.StartDate,
+			Actor:       order.CreatedBy,
+			Summary:     "送修：" + order.FaultDescription,
+			Status:      models.AssetStatusMaintenance,
+			SourceTable: "maintenance_orders",
+			SourceID:    order.ID,
+			Details:     map[string]interface{}{"vendor": order.Vendor},
+		})
+		if order.Status == models.MaintenanceStatusClosed && order.FinishDate != nil {
+			events = append(events, TimelineEvent{
+				Type:        TimelineEventMaintenanceClosed,
+				OccurredAt:  *order.FinishDate,
+				Actor:       order.ClosedBy,
+				Summary:     fmt.Sprintf("维修完成：%s", order.Result),
+				Status:      models.AssetStatusAvailable,
+				SourceTable: "maintenance_orders",
+				SourceID:    order.ID,
+				Details: map[string]interface{}{
+					"result":       order.Result,
+					"result_notes": order.ResultNotes,
+					"cost":         order.Cost,
+				},
+			})
+		}
+	}
+	return events, nil
+}
+
+// collectDisposalEvents 从已完成的处置申请生成处置事件
+func collectDisposalEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var disposals []models.AssetDisposal
+	if err := global.DB.
+		Where("asset_id = ? AND status = ?", asset.ID, models.DisposalStatusCompleted).
+		Order("completed_at ASC, id ASC").
+		Find(&disposals).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]TimelineEvent, 0, len(disposals))
+	for _, disposal := range disposals {
+		if disposal.CompletedAt == nil {
+			continue
+		}
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventDisposed,
+			OccurredAt:  *disposal.CompletedAt,
+			Actor:       disposal.CompletedBy,
+			Summary:     fmt.Sprintf("处置完成：%s", disposal.Method),
+			Status:      models.AssetStatusScrapped,
+			SourceTable: "asset_disposals",
+			SourceID:    disposal.ID,
+			Details: map[string]interface{}{
+				"reason":       disposal.Reason,
+				"requested_by": disposal.RequestedBy,
+				"reviewed_by":  disposal.ReviewedBy,
+			},
+		})
+	}
+	return events, nil
+}
+
+// collectComponentEvents 从组件历史生成挂载、拆卸事件，资产可能是父资产也可能是组件
+func collectComponentEvents(asset *models.Asset) ([]TimelineEvent, error) {
+	var logs []models.AssetComponentLog
+	if err := global.DB.
+		Preload("Parent", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Component", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("parent_id = ? OR component_id = ?", asset.ID, asset.ID).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]TimelineEvent, 0, len(logs))
+	for _, log := range logs {
+		var summary string
+		switch {
+		case log.ParentAssetID == asset.ID && log.Action == models.ComponentActionAttach:
+			summary = "挂载组件 " + log.Component.AssetNo
+		case log.ParentAssetID == asset.ID:
+			summary = "拆卸组件 " + log.Component.AssetNo
+		case log.Action == models.ComponentActionAttach:
+			summary = "挂载到 " + log.Parent.AssetNo
+		default:
+			summary = "从 " + log.Parent.AssetNo + " 拆卸"
+		}
+		events = append(events, TimelineEvent{
+			Type:        TimelineEventComponent,
+			OccurredAt:  log.CreatedAt,
+			Actor:       log.Operator,
+			Summary:     summary,
+			SourceTable: "asset_component_logs",
+			SourceID:    log.ID,
+			Details: map[string]interface{}{
+				"action":       log.Action,
+				"parent_id":    log.ParentAssetID,
+				"component_id": log.ComponentID,
+				"notes":        log.Notes,
+			},
+		})
+	}
+	return events, nil
+}
+
+// diffAuditData 比较操作日志的修改前数据与请求数据，只返回修改前数据中存在且取值不同的字段
+func diffAuditData(oldData, newData datatypes.JSON) []FieldChange {
+	var before, after map[string]interface{}
+	if json.Unmarshal(oldData, &before) != nil || json.Unmarshal(newData, &after) != nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var changes []FieldChange
+	for _, field := range fields {
+		old, ok := before[field]
+		if !ok || timelineIgnoredFields[field] || sameAuditValue(old, after[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: old, New: after[field]})
+	}
+	return changes
+}
+
+// sameAuditValue 判断两个 JSON 值是否相同，日期按时间比较
+func sameAuditValue(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return false
+	}
+	at, aerr := parseAuditTime(as)
+	bt, berr := parseAuditTime(bs)
+	return aerr == nil && berr == nil && at.Equal(bt)
+}
+
+// parseAuditTime 解析日志中的日期或时间
+func parseAuditTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -2,6 +2,7 @@ package assets
 
 import (
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/depreciation"
 	"encoding/json"
 	"time"
 )
@@ -82,11 +83,20 @@ type AssetFilters struct {
 	PurchaseDateTo    *time.Time          `json:"purchase_date_to" form:"purchase_date_to"`     // 采购日期结束
 	PriceLow          *float64            `json:"price_low" form:"price_low"`                   // 价格下限
 	PriceHigh         *float64            `json:"price_high" form:"price_high"`                 // 价格上限
+
+	Attributes []AttributeFilter `json:"-"` // 自定义属性筛选，由 filters[attr.字段名] 解析
+}
+
+// AttributeFilter 自定义属性筛选条件
+type AttributeFilter struct {
+	Name     string // 字段名
+	Operator string // eq, gte, lte
+	Value    string
 }
 
 // CreateAssetRequest 创建资产请求
 type CreateAssetRequest struct {
-	AssetNo           string                 `json:"asset_no" validate:"required,max=100"`
+	AssetNo           string                 `json:"asset_no" validate:"omitempty,max=100"` // 为空时按编号规则自动生成
 	Name              string                 `json:"name" validate:"required,max=200"`
 	CategoryID        uint                   `json:"category_id" validate:"required"`
 	DepartmentID      *uint                  `json:"department_id"`
@@ -103,6 +113,11 @@ type CreateAssetRequest struct {
 	Description       string                 `json:"description"`
 	ImageURL          string                 `json:"image_url" validate:"max=500"`
 	CustomAttributes  map[string]interface{} `json:"custom_attributes"`
+
+	// 折旧参数，未设置时沿用分类配置
+	DepreciationMethod models.DepreciationMethod `json:"depreciation_method" validate:"omitempty,oneof=straight_line double_declining sum_of_years"`
+	UsefulLifeMonths   *int                      `json:"useful_life_months" validate:"omitempty,min=1,max=600"`
+	SalvageRate        *float64                  `json:"salvage_rate" validate:"omitempty,min=0,lt=1"`
 }
 
 // UpdateAssetRequest 更新资产请求
@@ -124,13 +139,53 @@ type UpdateAssetRequest struct {
 	Description       string                 `json:"description"`
 	ImageURL          string                 `json:"image_url" validate:"omitempty,max=500"`
 	CustomAttributes  map[string]interface{} `json:"custom_attributes"`
+
+	// 折旧参数
+	DepreciationMethod *models.DepreciationMethod `json:"depreciation_method" validate:"omitempty,oneof=straight_line double_declining sum_of_years"`
+	UsefulLifeMonths   *int                       `json:"useful_life_months" validate:"omitempty,min=1,max=600"`
+	SalvageRate        *float64                   `json:"salvage_rate" validate:"omitempty,min=0,lt=1"`
 }
 
 // AssetResponse 资产响应
 type AssetResponse struct {
 	models.Asset
-	WarrantyEndDate *time.Time `json:"warranty_end_date,omitempty"` // 保修结束日期
-	IsUnderWarranty bool       `json:"is_under_warranty"`           // 是否在保修期内
+	WarrantyEndDate *time.Time                  `json:"warranty_end_date,omitempty"` // 保修结束日期
+	IsUnderWarranty bool                        `json:"is_under_warranty"`           // 是否在保修期内
+	Parent          *AssetBrief                 `json:"parent,omitempty"`            // 父资产
+	Components      []models.AssetComponentNode `json:"components"`                  // 组件树
+	ComponentValue  float64                     `json:"component_value"`             // 全部组件原值合计
+	TotalValue      float64                     `json:"total_value"`                 // 含组件的原值合计
+}
+
+// AssetBrief 资产简要信息
+type AssetBrief struct {
+	ID      uint               `json:"id"`
+	AssetNo string             `json:"asset_no"`
+	Name    string             `json:"name"`
+	Status  models.AssetStatus `json:"status"`
+}
+
+// ComponentRequest 挂载、拆卸组件请求
+type ComponentRequest struct {
+	ComponentID uint   `json:"component_id" validate:"required"`
+	Notes       string `json:"notes" validate:"max=500"`
+}
+
+// ComponentTreeResponse 组件树响应
+type ComponentTreeResponse struct {
+	AssetID        uint                        `json:"asset_id"`
+	Components     []models.AssetComponentNode `json:"components"`
+	ComponentValue float64                     `json:"component_value"` // 全部组件原值合计
+	TotalValue     float64                     `json:"total_value"`     // 含组件的原值合计
+}
+
+// AssetDepreciationResponse 资产折旧计划响应
+type AssetDepreciationResponse struct {
+	AssetID  uint                       `json:"asset_id"`
+	AssetNo  string                     `json:"asset_no"`
+	Name     string                     `json:"name"`
+	Current  depreciation.PeriodSummary `json:"current"`  // 截至指定期间的折旧情况
+	Schedule *depreciation.Schedule     `json:"schedule"` // 完整月度折旧计划
 }
 
 // ImportAssetRequest 批量导入资产请求
@@ -138,19 +193,57 @@ type ImportAssetRequest struct {
 	Assets []CreateAssetRequest `json:"assets" validate:"required,dive"`
 }
 
+// ImportAssetFileRequest 表格文件导入资产请求（multipart/form-data，文件字段为 file）
+type ImportAssetFileRequest struct {
+	Sheet     string `form:"sheet"`                        // 工作表名称，为空时读取活动工作表
+	HeaderRow int    `form:"header_row" validate:"min=0"`  // 表头所在行（从1开始），为0时自动识别
+	Mapping   string `form:"mapping" validate:"max=10000"` // 列映射 JSON，键为表头文字或列名（如 A），值为字段名或 attr.属性名，"-" 表示忽略该列
+	DryRun    bool   `form:"dry_run"`                      // 试运行：只校验并返回导入结果，不写入数据库，可放在查询参数或表单中
+}
+
+// ImportTemplateRequest 下载导入模板请求
+type ImportTemplateRequest struct {
+	CategoryID *uint `form:"category_id"` // 只包含该分类的自定义属性列，为空时包含全部分类
+}
+
 // ImportAssetResponse 批量导入资产响应
 type ImportAssetResponse struct {
-	SuccessCount int                `json:"success_count"` // 成功导入数量
-	FailedCount  int                `json:"failed_count"`  // 失败数量
-	Errors       []ImportAssetError `json:"errors"`        // 错误详情
-	Assets       []models.Asset     `json:"assets"`        // 成功导入的资产
+	SuccessCount int                `json:"success_count"`     // 成功导入数量
+	FailedCount  int                `json:"failed_count"`      // 失败数量
+	Errors       []ImportAssetError `json:"errors"`            // 错误详情
+	Assets       []models.Asset     `json:"assets"`            // 成功导入的资产
+	DryRun       bool               `json:"dry_run,omitempty"` // 试运行，数据未写入数据库
+	Columns      []ImportColumn     `json:"columns,omitempty"` // 文件导入时识别出的列映射
+
+	generatedNos []bool // 与 Assets 一一对应，资产编号是否按编号规则自动生成
+}
+
+// ImportDryRunResponse 试运行导入响应，资产未写入数据库，不返回ID和自动生成的资产编号
+type ImportDryRunResponse struct {
+	ImportAssetResponse
+	Assets []ImportPreviewAsset `json:"assets"`
+}
+
+// ImportPreviewAsset 试运行导入的资产，ID和自动生成的编号在事务回滚后作废，不返回
+type ImportPreviewAsset struct {
+	models.Asset
+	ID      uint   `json:"id,omitempty"`       // 覆盖资产ID，保持为空
+	AssetNo string `json:"asset_no,omitempty"` // 覆盖资产编号，只保留文件中填写的编号
 }
 
 // ImportAssetError 导入错误详情
 type ImportAssetError struct {
-	Index   int    `json:"index"`    // 数据索引
-	AssetNo string `json:"asset_no"` // 资产编号
-	Error   string `json:"error"`    // 错误信息
+	Index   int    `json:"index"`         // 数据索引
+	Row     int    `json:"row,omitempty"` // 文件导入时的行号（从1开始）
+	AssetNo string `json:"asset_no"`      // 资产编号
+	Error   string `json:"error"`         // 错误信息
+}
+
+// ImportColumn 文件导入的列映射
+type ImportColumn struct {
+	Column string `json:"column"` // 列名，如 A
+	Header string `json:"header"` // 表头文字
+	Field  string `json:"field"`  // 对应的资产字段，为空表示忽略该列
 }
 
 // CheckAssetNoResponse 检查资产编号响应
@@ -164,13 +257,8 @@ type BatchUpdateAssetsRequest struct {
 	Updates  BatchUpdateAssetsData `json:"updates" validate:"required"`
 }
 
-// BatchUpdateAssetsData 批量更新数据
-type BatchUpdateAssetsData struct {
-	Status            *models.AssetStatus `json:"status" validate:"omitempty,oneof=available borrowed maintenance scrapped"`
-	DepartmentID      *uint               `json:"department_id"`
-	Location          *string             `json:"location" validate:"omitempty,max=200"`
-	ResponsiblePerson *string             `json:"responsible_person" validate:"omitempty,max=100"`
-}
+// BatchUpdateAssetsData 批量更新数据，与后台批量任务共用
+type BatchUpdateAssetsData = models.AssetBatchUpdate
 
 // BatchUpdateAssetsResponse 批量更新资产响应
 type BatchUpdateAssetsResponse struct {
@@ -204,3 +292,56 @@ type BatchDeleteError struct {
 	AssetID uint   `json:"asset_id"` // 资产ID
 	Error   string `json:"error"`    // 错误信息
 }
+
+// BatchJobTarget 后台批量任务的目标资产，资产ID列表和筛选条件须且只能提供一个
+type BatchJobTarget struct {
+	AssetIDs []uint        `json:"asset_ids"`
+	Filters  *AssetFilters `json:"filters"` // 按筛选条件选取数据范围内的资产，不支持自定义属性筛选
+}
+
+// BatchUpdateJobRequest 提交批量更新任务请求
+type BatchUpdateJobRequest struct {
+	BatchJobTarget
+	Updates BatchUpdateAssetsData `json:"updates" validate:"required"`
+}
+
+// BatchDeleteJobRequest 提交批量删除任务请求
+type BatchDeleteJobRequest struct {
+	BatchJobTarget
+}
+
+// LabelRequest 资产标签请求
+type LabelRequest struct {
+	Code string `form:"code" validate:"omitempty,oneof=qr code128"` // 码制，默认二维码
+	Size string `form:"size"`                                       // 标签尺寸 宽x高（毫米），默认 60x40
+}
+
+// TimelineRequest 资产时间线请求
+type TimelineRequest struct {
+	Types     string     `form:"types"`                                         // 事件类型，逗号分隔，为空时返回全部
+	StartDate *time.Time `form:"start_date"`                                    // 开始时间
+	EndDate   *time.Time `form:"end_date"`                                      // 结束时间
+	Order     string     `form:"order" validate:"omitempty,oneof=asc desc"`     // 排序，默认按时间正序
+	Page      int        `form:"page,default=1" validate:"min=1"`               // 页码
+	PageSize  int        `form:"page_size,default=20" validate:"min=1,max=200"` // 每页条数
+}
+
+// TimelineEvent 资产时间线事件
+type TimelineEvent struct {
+	Type        string                 `json:"type"`              // 事件类型
+	OccurredAt  time.Time              `json:"occurred_at"`       // 发生时间
+	Actor       string                 `json:"actor"`             // 操作人，借用、归还取登记人
+	Summary     string                 `json:"summary"`           // 事件说明
+	Status      models.AssetStatus     `json:"status,omitempty"`  // 事件引起的资产状态变化（变更后的状态）
+	Changes     []FieldChange          `json:"changes,omitempty"` // 字段变更
+	SourceTable string                 `json:"source_table"`      // 来源表
+	SourceID    uint                   `json:"source_id"`         // 来源记录ID
+	Details     map[string]interface{} `json:"details,omitempty"` // 其他信息，如借用人
+}
+
+// FieldChange 字段变更
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
@@ -0,0 +1,327 @@
+package attachments
+
+import (
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/uploads"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetAttachments 获取附件列表
+func GetAttachments(c *gin.Context) {
+	var req ListAttachmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if req.OwnerID != 0 && req.OwnerType == "" {
+		utils.ValidationError(c, "指定所属记录ID时必须同时指定所属记录类型")
+		return
+	}
+
+	// 按数据范围过滤
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.Attachment{})
+	if req.OwnerType != "" {
+		query = query.Where("owner_type = ?", req.OwnerType)
+	}
+	if req.OwnerID != 0 {
+		query = query.Where("owner_id = ?", req.OwnerID)
+	}
+	if req.AssetID != 0 {
+		query = query.Where("asset_id = ?", req.AssetID)
+	}
+	if req.Kind != "" {
+		query = query.Where("kind = ?", req.Kind)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var attachments []models.Attachment
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&attachments).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, utils.NewPaginationResponse(req.Page, req.PageSize, total, attachments))
+}
+
+// GetAttachment 获取附件详情
+func GetAttachment(c *gin.Context) {
+	attachment, ok := findAttachment(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, attachment)
+}
+
+// UploadAttachment 上传附件并关联到资产、借用记录或维修工单
+func UploadAttachment(c *gin.Context) {
+	var req UploadAttachmentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, utils.BAD_REQUEST, gin.H{"error": "获取上传文件失败"})
+		return
+	}
+
+	// 只能为数据范围内的记录上传附件
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	assetID, ok := resolveOwnerAsset(c, scope, req.OwnerType, req.OwnerID)
+	if !ok {
+		return
+	}
+
+	stored, err := utils.StoreUploadedFile(file, utils.FileUploadConfig{
+		MaxSize:      global.AppConfig.UploadMaxSize,
+		AllowedTypes: allowedTypes(),
+		UploadDir:    filepath.Join(global.AppConfig.UploadDir, uploads.AttachmentDir, time.Now().Format("200601")),
+	})
+	if err != nil {
+		utils.Error(c, utils.FILE_UPLOAD_FAILED, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 同一记录不重复保存内容相同的文件
+	var existing models.Attachment
+	err = global.DB.Where("owner_type = ? AND owner_id = ? AND checksum = ?", req.OwnerType, req.OwnerID, stored.Checksum).First(&existing).Error
+	if err == nil {
+		utils.DeleteFile(stored.Path)
+		utils.Error(c, utils.ATTACHMENT_DUPLICATE, gin.H{"id": existing.ID, "file_name": existing.FileName})
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		utils.DeleteFile(stored.Path)
+		utils.InternalError(c, err)
+		return
+	}
+
+	attachment := models.Attachment{
+		OwnerType:   req.OwnerType,
+		OwnerID:     req.OwnerID,
+		AssetID:     assetID,
+		Kind:        models.AttachmentKind(req.Kind),
+		FileName:    filepath.Base(file.Filename),
+		FilePath:    stored.Path,
+		MimeType:    stored.MimeType,
+		FileSize:    stored.Size,
+		Checksum:    stored.Checksum,
+		Description: req.Description,
+		UploadedBy:  auth.GetOperator(c),
+	}
+	if err := global.DB.Create(&attachment).Error; err != nil {
+		utils.DeleteFile(stored.Path)
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, attachment)
+}
+
+// inlineMimeTypes 允许在浏览器中直接打开的附件类型，HTML、SVG 等可执行脚本的类型一律以下载方式返回
+var inlineMimeTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/bmp":       true,
+	"application/pdf": true,
+}
+
+// DownloadAttachment 下载附件，以原始文件名返回，只有图片和 PDF 可在浏览器中直接打开
+func DownloadAttachment(c *gin.Context) {
+	var req DownloadAttachmentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	attachment, ok := findAttachment(c)
+	if !ok {
+		return
+	}
+
+	path := localPath(attachment.FilePath)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		utils.Error(c, utils.ATTACHMENT_FILE_MISSING, nil)
+		return
+	}
+
+	// 禁止浏览器按内容猜测类型，避免上传的文件被当作页面执行
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	mimeType, _, err := mime.ParseMediaType(attachment.MimeType)
+	if err != nil || !inlineMimeTypes[mimeType] {
+		c.Header("Content-Type", "application/octet-stream")
+		c.FileAttachment(path, attachment.FileName)
+		return
+	}
+	if !req.Inline {
+		c.FileAttachment(path, attachment.FileName)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename*=UTF-8''"+url.PathEscape(attachment.FileName))
+	c.Header("Content-Type", mimeType)
+	c.File(path)
+}
+
+// DeleteAttachment 删除附件记录及文件
+func DeleteAttachment(c *gin.Context) {
+	attachment, ok := findAttachment(c)
+	if !ok {
+		return
+	}
+
+	if err := global.DB.Delete(attachment).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 文件删除失败时由定时清理任务兜底
+	utils.DeleteFile(attachment.FilePath)
+
+	utils.Success(c, gin.H{"message": "附件删除成功"})
+}
+
+// Sweep 清理上传目录中没有任何记录引用的文件
+func Sweep(c *gin.Context) {
+	var req SweepRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	grace := time.Duration(global.AppConfig.UploadSweepGraceHours) * time.Hour
+	result, err := uploads.Sweep(global.DB, global.AppConfig.UploadDir, grace, req.DryRun)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// findAttachment 按路径参数查找数据范围内的附件，失败时已写入响应
+func findAttachment(c *gin.Context) (*models.Attachment, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的附件ID")
+		return nil, false
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	var attachment models.Attachment
+	if err := scope.DB().First(&attachment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ATTACHMENT_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &attachment, true
+}
+
+// resolveOwnerAsset 查找数据范围内的所属记录并返回其资产ID，失败时已写入响应
+func resolveOwnerAsset(c *gin.Context, scope *auth.DataScope, ownerType string, ownerID uint) (uint, bool) {
+	var assetID uint
+	var notFound string
+	var err error
+	switch ownerType {
+	case models.AttachmentOwnerAsset:
+		var asset models.Asset
+		err = scope.DB().First(&asset, ownerID).Error
+		assetID, notFound = asset.ID, utils.ASSET_NOT_FOUND
+	case models.AttachmentOwnerBorrow:
+		var record models.BorrowRecord
+		err = scope.DB().First(&record, ownerID).Error
+		assetID, notFound = record.AssetID, utils.BORROW_NOT_FOUND
+	case models.AttachmentOwnerMaintenance:
+		var order models.MaintenanceOrder
+		err = scope.DB().First(&order, ownerID).Error
+		assetID, notFound = order.AssetID, utils.MAINTENANCE_NOT_FOUND
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		utils.Error(c, notFound, nil)
+		return 0, false
+	}
+	if err != nil {
+		utils.InternalError(c, err)
+		return 0, false
+	}
+	return assetID, true
+}
+
+// allowedTypes 按配置获取允许上传的文件扩展名
+func allowedTypes() []string {
+	var types []string
+	for _, ext := range strings.Split(global.AppConfig.UploadAllowedTypes, ",") {
+		if ext = strings.ToLower(strings.TrimSpace(ext)); ext != "" {
+			types = append(types, "."+strings.TrimPrefix(ext, "."))
+		}
+	}
+	return types
+}
+
+// localPath 将存储路径转换为本地文件路径
+func localPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return "./" + path
+}
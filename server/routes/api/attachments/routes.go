@@ -0,0 +1,21 @@
+package attachments
+
+import (
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册附件路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	attachments := r.Group("/attachments")
+	{
+		attachments.GET("", GetAttachments)                                              // 获取附件列表
+		attachments.POST("/upload", UploadAttachment)                                    // 上传附件到资产、借用记录或维修工单
+		attachments.POST("/sweep", middleware.UserRoleMiddleware(auth.RoleAdmin), Sweep) // 清理未引用的上传文件（仅管理员）
+		attachments.GET("/:id", GetAttachment)                                           // 获取附件详情
+		attachments.GET("/:id/download", DownloadAttachment)                             // 下载附件
+		attachments.DELETE("/:id", DeleteAttachment)                                     // 删除附件及文件
+	}
+}
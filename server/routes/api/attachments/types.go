@@ -0,0 +1,29 @@
+package attachments
+
+// ListAttachmentsRequest 附件列表请求
+type ListAttachmentsRequest struct {
+	OwnerType string `form:"owner_type" validate:"omitempty,oneof=asset borrow maintenance"`      // 所属记录类型
+	OwnerID   uint   `form:"owner_id"`                                                            // 所属记录ID，需同时指定 owner_type
+	AssetID   uint   `form:"asset_id"`                                                            // 资产ID，包含该资产借用记录和维修工单的附件
+	Kind      string `form:"kind" validate:"omitempty,oneof=invoice manual photo contract other"` // 附件类别
+	Page      int    `form:"page,default=1" validate:"min=1"`                                     // 页码
+	PageSize  int    `form:"page_size,default=20" validate:"min=1,max=200"`                       // 每页条数
+}
+
+// UploadAttachmentRequest 上传附件请求（multipart/form-data，文件字段为 file）
+type UploadAttachmentRequest struct {
+	OwnerType   string `form:"owner_type" validate:"required,oneof=asset borrow maintenance"`
+	OwnerID     uint   `form:"owner_id" validate:"required"`
+	Kind        string `form:"kind" validate:"omitempty,oneof=invoice manual photo contract other"`
+	Description string `form:"description" validate:"max=500"`
+}
+
+// DownloadAttachmentRequest 下载附件请求
+type DownloadAttachmentRequest struct {
+	Inline bool `form:"inline"` // 在浏览器中直接打开（如预览图片、PDF）
+}
+
+// SweepRequest 清理未引用上传文件请求
+type SweepRequest struct {
+	DryRun bool `form:"dry_run"` // 仅预览将被清理的文件
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	authpkg "asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// Login 用户登录
+func Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	var user models.User
+	if err := global.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.AUTH_INVALID_CREDENTIALS, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if !user.CheckPassword(req.Password) {
+		utils.Error(c, utils.AUTH_INVALID_CREDENTIALS, nil)
+		return
+	}
+
+	if !user.IsActive {
+		utils.Error(c, utils.AUTH_USER_DISABLED, nil)
+		return
+	}
+
+	// 记录最后登录时间
+	now := time.Now()
+	if err := global.DB.Model(&user).Update("last_login_at", now).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	session, err := authpkg.CreateSession(user.ID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	response, err := issueTokens(&user, session.SessionToken)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌
+func RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	claims, err := authpkg.ParseToken(req.RefreshToken, authpkg.TokenTypeRefresh)
+	if err != nil {
+		utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+		return
+	}
+
+	// 刷新令牌沿用原会话，会话被注销后无法再刷新
+	if err := authpkg.ValidateSession(claims.SessionID, claims.UserID); err != nil {
+		if errors.Is(err, authpkg.ErrSessionInvalid) {
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, err.Error(), nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 重新加载用户，确保角色和状态是最新的
+	var user models.User
+	if err := global.DB.First(&user, claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorWithMessage(c, utils.UNAUTHORIZED, "用户不存在", nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if !user.IsActive {
+		utils.Error(c, utils.AUTH_USER_DISABLED, nil)
+		return
+	}
+
+	response, err := issueTokens(&user, claims.SessionID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// GetCurrentUser 获取当前登录用户信息
+func GetCurrentUser(c *gin.Context) {
+	identity := authpkg.GetIdentity(c)
+	if identity == nil {
+		utils.Error(c, utils.UNAUTHORIZED, nil)
+		return
+	}
+
+	var user models.User
+	if err := global.DB.Preload("Department").First(&user, identity.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.NotFound(c, "用户")
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, user)
+}
+
+// Logout 注销当前会话
+func Logout(c *gin.Context) {
+	identity := authpkg.GetIdentity(c)
+	if identity == nil {
+		utils.Error(c, utils.UNAUTHORIZED, nil)
+		return
+	}
+	if identity.SessionID == "" {
+		utils.ErrorWithMessage(c, utils.BAD_REQUEST, "当前认证方式没有会话可注销", nil)
+		return
+	}
+
+	var session models.UserSession
+	if err := global.DB.Where("session_token = ?", identity.SessionID).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Success(c, gin.H{"message": "已退出登录"})
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := authpkg.RevokeSession(&session); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "已退出登录"})
+}
+
+// GetSessions 获取当前用户的有效会话
+func GetSessions(c *gin.Context) {
+	identity := authpkg.GetIdentity(c)
+	if identity == nil {
+		utils.Error(c, utils.UNAUTHORIZED, nil)
+		return
+	}
+
+	sessions, err := authpkg.GetUserSessions(identity.UserID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	response := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = SessionResponse{
+			UserSession: session,
+			Current:     session.SessionToken == identity.SessionID,
+		}
+	}
+
+	utils.Success(c, response)
+}
+
+// RevokeSession 注销当前用户的指定会话
+func RevokeSession(c *gin.Context) {
+	identity := authpkg.GetIdentity(c)
+	if identity == nil {
+		utils.Error(c, utils.UNAUTHORIZED, nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的会话ID")
+		return
+	}
+
+	var session models.UserSession
+	if err := global.DB.Where("id = ? AND user_id = ?", id, identity.UserID).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.SESSION_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := authpkg.RevokeSession(&session); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "会话已注销"})
+}
+
+// RevokeOtherSessions 注销当前用户除本会话外的全部会话
+func RevokeOtherSessions(c *gin.Context) {
+	identity := authpkg.GetIdentity(c)
+	if identity == nil {
+		utils.Error(c, utils.UNAUTHORIZED, nil)
+		return
+	}
+
+	revoked, err := authpkg.RevokeUserSessions(identity.UserID, identity.SessionID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"revoked": revoked})
+}
+
+// issueTokens 为用户签发绑定会话的访问令牌和刷新令牌
+func issueTokens(user *models.User, sessionID string) (*TokenResponse, error) {
+	identity := authpkg.NewIdentityFromUser(user)
+	identity.SessionID = sessionID
+
+	accessToken, expiresAt, err := authpkg.GenerateToken(identity, authpkg.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExpiresAt, err := authpkg.GenerateToken(identity, authpkg.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             user,
+	}, nil
+}
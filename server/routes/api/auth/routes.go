@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册认证相关路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	auth := r.Group("/auth")
+	{
+		auth.POST("/login", Login)          // 用户登录
+		auth.POST("/refresh", RefreshToken) // 刷新访问令牌
+		auth.GET("/me", GetCurrentUser)     // 获取当前用户信息
+		auth.POST("/logout", Logout)        // 注销当前会话
+
+		auth.GET("/sessions", GetSessions)            // 获取当前用户的会话
+		auth.DELETE("/sessions", RevokeOtherSessions) // 注销其他会话
+		auth.DELETE("/sessions/:id", RevokeSession)   // 注销指定会话
+	}
+}
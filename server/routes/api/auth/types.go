@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"asset-management-system/server/models"
+	"time"
+)
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" validate:"required,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenResponse 令牌响应
+type TokenResponse struct {
+	AccessToken      string       `json:"access_token"`
+	RefreshToken     string       `json:"refresh_token"`
+	TokenType        string       `json:"token_type"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	User             *models.User `json:"user"`
+}
+
+// SessionResponse 会话响应
+type SessionResponse struct {
+	models.UserSession
+	Current bool `json:"current"` // 是否为当前会话
+}
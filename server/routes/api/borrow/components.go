@@ -0,0 +1,90 @@
+package borrow
+
+import (
+	"fmt"
+
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+
+	"gorm.io/gorm"
+)
+
+// borrowComponents 为父资产的全部下级组件创建借用记录，不在数据范围内或不可借用的组件跳过并返回原因
+func borrowComponents(tx *gorm.DB, scope *auth.DataScope, parent *models.BorrowRecord) ([]SkippedComponent, error) {
+	ids, err := models.ComponentDescendantIDs(tx, parent.AssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make([]SkippedComponent, 0)
+	for _, id := range ids {
+		var component models.Asset
+		if err := scope.Apply(tx).First(&component, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				skipped = append(skipped, SkippedComponent{AssetID: id, Reason: "不在数据范围内"})
+				continue
+			}
+			return nil, err
+		}
+
+		reason, err := componentUnavailableReason(tx, &component, parent)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			skipped = append(skipped, SkippedComponent{AssetID: id, AssetNo: component.AssetNo, Reason: reason})
+			continue
+		}
+
+		record := models.BorrowRecord{
+			AssetID:            component.ID,
+			BorrowerName:       parent.BorrowerName,
+			BorrowerContact:    parent.BorrowerContact,
+			DepartmentID:       parent.DepartmentID,
+			BorrowDate:         parent.BorrowDate,
+			ExpectedReturnDate: parent.ExpectedReturnDate,
+			Purpose:            parent.Purpose,
+			Notes:              fmt.Sprintf("随父资产借出（借用记录 #%d）", parent.ID),
+			Status:             models.BorrowStatusBorrowed,
+			ParentRecordID:     &parent.ID,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return nil, err
+		}
+	}
+	return skipped, nil
+}
+
+// componentUnavailableReason 检查组件能否在父资产的借用期间借出，不能借出时返回原因
+func componentUnavailableReason(tx *gorm.DB, component *models.Asset, parent *models.BorrowRecord) (string, error) {
+	if component.Status != models.AssetStatusAvailable {
+		return fmt.Sprintf("资产状态为 %s，不可借用", component.Status), nil
+	}
+
+	frozen, err := models.IsAssetFrozen(tx, component.ID)
+	if err != nil {
+		return "", err
+	}
+	if frozen {
+		return "资产处置中，已冻结", nil
+	}
+
+	var count int64
+	if err := tx.Model(&models.BorrowRecord{}).
+		Where("asset_id = ? AND status = ?", component.ID, models.BorrowStatusBorrowed).
+		Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "资产已有未归还的借用记录", nil
+	}
+
+	conflicts, err := models.FindReservationConflicts(tx, component.ID, parent.BorrowDate, parent.ExpectedReturnDate, pickupGrace(), 0)
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) > 0 {
+		return "借用期间资产已被预约", nil
+	}
+	return "", nil
+}
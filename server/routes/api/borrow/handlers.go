@@ -3,6 +3,7 @@ package borrow
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 	"asset-management-system/server/pkg/utils"
 	"fmt"
 	"strconv"
@@ -56,8 +57,13 @@ func GetBorrowRecords(c *gin.Context) {
 		}
 	}
 
-	// 构建查询
-	query := global.DB.Model(&models.BorrowRecord{}).
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.BorrowRecord{}).
 		Preload("Asset").
 		Preload("Asset.Category").
 		Preload("Department")
@@ -107,11 +113,18 @@ func GetBorrowRecord(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	var borrowRecord models.BorrowRecord
-	if err := global.DB.
+	if err := scope.DB().
 		Preload("Asset").
 		Preload("Asset.Category").
 		Preload("Department").
+		Preload("ComponentRecords.Asset").
 		First(&borrowRecord, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.BORROW_NOT_FOUND, nil)
@@ -145,9 +158,14 @@ func CreateBorrowRecord(c *gin.Context) {
 		return
 	}
 
-	// 验证资产是否存在且可借用
+	// 验证资产是否存在且可借用（只能借用数据范围内的资产）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var asset models.Asset
-	if err := global.DB.First(&asset, req.AssetID).Error; err != nil {
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
 			return
@@ -172,8 +190,23 @@ func CreateBorrowRecord(c *gin.Context) {
 		return
 	}
 
+	// 借用期间不能与有效预约重叠
+	if !checkReservationConflicts(c, asset.ID, req.BorrowDate, req.ExpectedReturnDate) {
+		return
+	}
+
+	// 部门受限用户未指定部门时默认归属本部门
+	if req.DepartmentID == nil && !scope.All {
+		req.DepartmentID = scope.DepartmentID
+	}
+
 	// 验证部门是否存在（如果提供了部门ID）
 	if req.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权为该部门登记借用", nil)
+			return
+		}
+
 		var department models.Department
 		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -198,7 +231,33 @@ func CreateBorrowRecord(c *gin.Context) {
 		Status:             models.BorrowStatusBorrowed,
 	}
 
-	if err := global.DB.Create(&borrowRecord).Error; err != nil {
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(&borrowRecord).Error; err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	// 同时借出组件
+	var skipped []SkippedComponent
+	if req.IncludeComponents {
+		skipped, err = borrowComponents(tx, scope, &borrowRecord)
+		if err != nil {
+			tx.Rollback()
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
 		utils.InternalError(c, err)
 		return
 	}
@@ -208,16 +267,18 @@ func CreateBorrowRecord(c *gin.Context) {
 		Preload("Asset").
 		Preload("Asset.Category").
 		Preload("Department").
+		Preload("ComponentRecords.Asset").
 		First(&borrowRecord, borrowRecord.ID).Error; err != nil {
 		utils.InternalError(c, err)
 		return
 	}
 
 	response := BorrowResponse{
-		BorrowRecord: borrowRecord,
-		IsOverdue:    borrowRecord.IsOverdue(),
-		OverdueDays:  borrowRecord.GetOverdueDays(),
-		CanReturn:    borrowRecord.Status == models.BorrowStatusBorrowed,
+		BorrowRecord:      borrowRecord,
+		IsOverdue:         borrowRecord.IsOverdue(),
+		OverdueDays:       borrowRecord.GetOverdueDays(),
+		CanReturn:         borrowRecord.Status == models.BorrowStatusBorrowed,
+		SkippedComponents: skipped,
 	}
 
 	utils.Success(c, response)
@@ -243,9 +304,14 @@ func UpdateBorrowRecord(c *gin.Context) {
 		return
 	}
 
-	// 查找借用记录
+	// 查找借用记录（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var borrowRecord models.BorrowRecord
-	if err := global.DB.First(&borrowRecord, id).Error; err != nil {
+	if err := scope.DB().First(&borrowRecord, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.BORROW_NOT_FOUND, nil)
 			return
@@ -260,8 +326,18 @@ func UpdateBorrowRecord(c *gin.Context) {
 		return
 	}
 
+	// 调整预计归还日期时，延长的借用期间不能与有效预约重叠
+	if req.ExpectedReturnDate != nil && !checkReservationConflicts(c, borrowRecord.AssetID, time.Now(), req.ExpectedReturnDate) {
+		return
+	}
+
 	// 验证部门是否存在
 	if req.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权将借用记录调整到该部门", nil)
+			return
+		}
+
 		var department models.Department
 		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -337,9 +413,14 @@ func ReturnAsset(c *gin.Context) {
 		return
 	}
 
-	// 查找借用记录
+	// 查找借用记录（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var borrowRecord models.BorrowRecord
-	if err := global.DB.First(&borrowRecord, id).Error; err != nil {
+	if err := scope.DB().First(&borrowRecord, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.BORROW_NOT_FOUND, nil)
 			return
@@ -385,9 +466,16 @@ func ReturnAsset(c *gin.Context) {
 	}
 
 	// 更新资产状态
-	if err := tx.Model(&models.Asset{}).Where("id = ?", borrowRecord.AssetID).Update("status", models.AssetStatusAvailable).Error; err != nil {
+	if err := models.ChangeAssetStatus(tx, borrowRecord.AssetID, models.AssetStatusAvailable); err != nil {
 		tx.Rollback()
-		utils.InternalError(c, err)
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	// 随父资产借出的组件一并归还
+	if err := models.ReturnComponentRecords(tx, borrowRecord.ID, returnDate); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
 		return
 	}
 
@@ -425,9 +513,14 @@ func DeleteBorrowRecord(c *gin.Context) {
 		return
 	}
 
-	// 查找借用记录
+	// 查找借用记录（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var borrowRecord models.BorrowRecord
-	if err := global.DB.First(&borrowRecord, id).Error; err != nil {
+	if err := scope.DB().First(&borrowRecord, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.Error(c, utils.BORROW_NOT_FOUND, nil)
 			return
@@ -476,11 +569,36 @@ func GetAvailableAssets(c *gin.Context) {
 		"id":   true,
 	})
 
-	// 构建查询 - 只查询可用状态的资产
-	query := global.DB.Model(&models.Asset{}).
-		Where("status = ?", models.AssetStatusAvailable).
+	// 指定时间段时须同时提供开始和结束时间
+	var window AvailableAssetsWindow
+	if err := c.ShouldBindQuery(&window); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if (window.StartTime == nil) != (window.EndTime == nil) {
+		utils.ValidationError(c, "开始时间和结束时间须同时提供")
+		return
+	}
+	if window.StartTime != nil && !window.EndTime.After(*window.StartTime) {
+		utils.ValidationError(c, "结束时间必须晚于开始时间")
+		return
+	}
+
+	// 构建查询 - 只查询数据范围内可借用的资产
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.Asset{}).
 		Preload("Category").
 		Preload("Department")
+	if window.StartTime != nil {
+		// 整个时间段内空闲：届时已归还且没有重叠的有效预约
+		query = models.AssetsFreeDuring(query, *window.StartTime, *window.EndTime, pickupGrace())
+	} else {
+		query = query.Where("status = ?", models.AssetStatusAvailable)
+	}
 
 	// 应用搜索条件
 	search := c.Query("search")
@@ -539,23 +657,30 @@ func GetAvailableAssets(c *gin.Context) {
 
 // GetBorrowStats 获取借用统计信息
 func GetBorrowStats(c *gin.Context) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	db := scope.DB()
+
 	var stats BorrowStatsResponse
 
 	// 总借用数
-	global.DB.Model(&models.BorrowRecord{}).Count(&stats.TotalBorrows)
+	db.Model(&models.BorrowRecord{}).Count(&stats.TotalBorrows)
 
 	// 活跃借用数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ?", models.BorrowStatusBorrowed).
 		Count(&stats.ActiveBorrows)
 
 	// 已归还数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ?", models.BorrowStatusReturned).
 		Count(&stats.ReturnedBorrows)
 
 	// 超期借用数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ? AND expected_return_date < ?", models.BorrowStatusBorrowed, time.Now()).
 		Count(&stats.OverdueBorrows)
 
@@ -565,7 +690,7 @@ func GetBorrowStats(c *gin.Context) {
 		Status string
 		Count  int64
 	}{}
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Select("status, COUNT(*) as count").
 		Group("status").
 		Scan(&statusStats)
@@ -574,6 +699,7 @@ func GetBorrowStats(c *gin.Context) {
 	}
 
 	// 月度统计（最近12个月）
+	borrowCondition, borrowArgs := scope.Condition("borrow_records", "borrow_records")
 	monthlyStats := []MonthlyBorrowStats{}
 	global.DB.Raw(`
 		SELECT 
@@ -581,10 +707,10 @@ func GetBorrowStats(c *gin.Context) {
 			COUNT(*) as count,
 			SUM(CASE WHEN status = 'returned' THEN 1 ELSE 0 END) as returns
 		FROM borrow_records 
-		WHERE borrow_date >= date('now', '-12 months')
+		WHERE borrow_date >= date('now', '-12 months') AND `+borrowCondition+`
 		GROUP BY strftime('%Y-%m', borrow_date)
 		ORDER BY month
-	`).Scan(&monthlyStats)
+	`, borrowArgs...).Scan(&monthlyStats)
 	stats.BorrowsByMonth = monthlyStats
 
 	// 借用人排行（前10）
@@ -595,13 +721,15 @@ func GetBorrowStats(c *gin.Context) {
 			COUNT(*) as count,
 			SUM(CASE WHEN status = 'borrowed' THEN 1 ELSE 0 END) as active_count
 		FROM borrow_records 
+		WHERE `+borrowCondition+`
 		GROUP BY borrower_name 
 		ORDER BY count DESC 
 		LIMIT 10
-	`).Scan(&borrowerStats)
+	`, borrowArgs...).Scan(&borrowerStats)
 	stats.TopBorrowers = borrowerStats
 
 	// 热门资产排行（前10）
+	aliasCondition, aliasArgs := scope.Condition("borrow_records", "br")
 	assetStats := []AssetBorrowStats{}
 	global.DB.Raw(`
 		SELECT 
@@ -611,10 +739,11 @@ func GetBorrowStats(c *gin.Context) {
 			COUNT(*) as count
 		FROM borrow_records br
 		JOIN assets a ON br.asset_id = a.id
+		WHERE `+aliasCondition+`
 		GROUP BY br.asset_id, a.name, a.asset_no
 		ORDER BY count DESC 
 		LIMIT 10
-	`).Scan(&assetStats)
+	`, aliasArgs...).Scan(&assetStats)
 	stats.TopAssets = assetStats
 
 	utils.Success(c, stats)
@@ -0,0 +1,36 @@
+package borrow
+
+import (
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pickupGrace 预约开始后的取用宽限时间
+func pickupGrace() time.Duration {
+	return time.Duration(global.AppConfig.ReservationPickupGraceHours) * time.Hour
+}
+
+// checkReservationConflicts 检查借用期间是否与有效预约重叠，重叠时写入冲突响应并返回 false
+// end 为空表示未填写预计归还日期，此后的所有预约都视为冲突
+func checkReservationConflicts(c *gin.Context, assetID uint, start time.Time, end *time.Time) bool {
+	conflicts, err := models.FindReservationConflicts(global.DB, assetID, start, end, pickupGrace(), 0)
+	if err != nil {
+		utils.InternalError(c, err)
+		return false
+	}
+	if len(conflicts) == 0 {
+		return true
+	}
+
+	message := "借用期间资产已被预约，请缩短预计归还日期或通过预约取用"
+	if end == nil {
+		message = "资产已有后续预约，请填写在预约开始前的预计归还日期"
+	}
+	utils.ErrorWithMessage(c, utils.RESERVATION_CONFLICT, message, gin.H{"reservations": conflicts})
+	return false
+}
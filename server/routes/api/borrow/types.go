@@ -15,6 +15,7 @@ type CreateBorrowRequest struct {
 	ExpectedReturnDate *time.Time `json:"expected_return_date"`
 	Purpose            string     `json:"purpose"`
 	Notes              string     `json:"notes"`
+	IncludeComponents  bool       `json:"include_components"` // 同时借出父资产下的全部组件，不可借用的组件跳过
 }
 
 // UpdateBorrowRequest 更新借用记录请求
@@ -40,6 +41,15 @@ type BorrowResponse struct {
 	IsOverdue   bool `json:"is_overdue"`
 	OverdueDays int  `json:"overdue_days"`
 	CanReturn   bool `json:"can_return"`
+
+	SkippedComponents []SkippedComponent `json:"skipped_components,omitempty"` // 未能随父资产借出的组件
+}
+
+// SkippedComponent 未能随父资产借出的组件
+type SkippedComponent struct {
+	AssetID uint   `json:"asset_id"`
+	AssetNo string `json:"asset_no,omitempty"`
+	Reason  string `json:"reason"`
 }
 
 // BorrowFilters 借用记录筛选条件
@@ -89,6 +99,12 @@ type AssetBorrowStats struct {
 	Count     int64  `json:"count"`
 }
 
+// AvailableAssetsWindow 可借用资产的查询时间段，不指定时查询当前可借用的资产
+type AvailableAssetsWindow struct {
+	StartTime *time.Time `form:"start_time"`
+	EndTime   *time.Time `form:"end_time"`
+}
+
 // AvailableAssetResponse 可借用资产响应
 type AvailableAssetResponse struct {
 	models.Asset
@@ -3,6 +3,8 @@ package categories
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/attributes"
+	"asset-management-system/server/pkg/depreciation"
 	"asset-management-system/server/pkg/utils"
 	"encoding/json"
 	"strconv"
@@ -141,6 +143,10 @@ func CreateCategory(c *gin.Context) {
 		utils.ValidationError(c, err.Error())
 		return
 	}
+	if err := depreciation.CheckPartial(req.DepreciationMethod, req.UsefulLifeMonths); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
 
 	// 检查分类编码是否已存在
 	var existingCategory models.Category
@@ -174,6 +180,10 @@ func CreateCategory(c *gin.Context) {
 			utils.ValidationError(c, "属性格式错误")
 			return
 		}
+		if err := checkAttributeTemplate(attributesJSON); err != nil {
+			utils.ValidationError(c, err)
+			return
+		}
 	}
 
 	// 创建分类
@@ -183,6 +193,10 @@ func CreateCategory(c *gin.Context) {
 		ParentID:    req.ParentID,
 		Description: req.Description,
 		Attributes:  attributesJSON,
+
+		DepreciationMethod: req.DepreciationMethod,
+		UsefulLifeMonths:   req.UsefulLifeMonths,
+		SalvageRate:        req.SalvageRate,
 	}
 
 	if err := global.DB.Create(&category).Error; err != nil {
@@ -238,6 +252,19 @@ func UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	// 校验合并后的折旧参数
+	method, usefulLife := category.DepreciationMethod, category.UsefulLifeMonths
+	if req.DepreciationMethod != nil {
+		method = *req.DepreciationMethod
+	}
+	if req.UsefulLifeMonths != nil {
+		usefulLife = req.UsefulLifeMonths
+	}
+	if err := depreciation.CheckPartial(method, usefulLife); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
 	// 检查分类编码是否已被其他分类使用
 	if req.Code != nil && *req.Code != category.Code {
 		var existingCategory models.Category
@@ -296,8 +323,21 @@ func UpdateCategory(c *gin.Context) {
 			utils.ValidationError(c, "属性格式错误")
 			return
 		}
+		if err := checkAttributeTemplate(attributesJSON); err != nil {
+			utils.ValidationError(c, err)
+			return
+		}
 		updates["attributes"] = attributesJSON
 	}
+	if req.DepreciationMethod != nil {
+		updates["depreciation_method"] = *req.DepreciationMethod
+	}
+	if req.UsefulLifeMonths != nil {
+		updates["useful_life_months"] = *req.UsefulLifeMonths
+	}
+	if req.SalvageRate != nil {
+		updates["salvage_rate"] = *req.SalvageRate
+	}
 
 	// 执行更新
 	if err := global.DB.Model(&category).Updates(updates).Error; err != nil {
@@ -508,3 +548,12 @@ func checkCircularReference(categoryID, parentID uint) error {
 
 	return nil
 }
+
+// checkAttributeTemplate 校验分类属性模板，错误统一为按字段的校验错误
+func checkAttributeTemplate(attributesJSON []byte) error {
+	template, err := attributes.ParseTemplate(attributesJSON)
+	if err != nil {
+		return attributes.ValidationErrors{{Field: "attributes", Message: err.Error()}}
+	}
+	return attributes.CheckTemplate(template)
+}
@@ -32,6 +32,11 @@ type CreateCategoryRequest struct {
 	ParentID    *uint       `json:"parent_id"`
 	Description string      `json:"description"`
 	Attributes  interface{} `json:"attributes"`
+
+	// 折旧参数，未设置时沿用上级分类
+	DepreciationMethod models.DepreciationMethod `json:"depreciation_method" validate:"omitempty,oneof=straight_line double_declining sum_of_years"`
+	UsefulLifeMonths   *int                      `json:"useful_life_months" validate:"omitempty,min=1,max=600"`
+	SalvageRate        *float64                  `json:"salvage_rate" validate:"omitempty,min=0,lt=1"`
 }
 
 // UpdateCategoryRequest 更新分类请求
@@ -41,6 +46,11 @@ type UpdateCategoryRequest struct {
 	ParentID    *uint       `json:"parent_id"`
 	Description *string     `json:"description"`
 	Attributes  interface{} `json:"attributes"`
+
+	// 折旧参数
+	DepreciationMethod *models.DepreciationMethod `json:"depreciation_method" validate:"omitempty,oneof=straight_line double_declining sum_of_years"`
+	UsefulLifeMonths   *int                       `json:"useful_life_months" validate:"omitempty,min=1,max=600"`
+	SalvageRate        *float64                   `json:"salvage_rate" validate:"omitempty,min=0,lt=1"`
 }
 
 // CategoryFilters 分类筛选条件
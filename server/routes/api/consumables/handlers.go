@@ -0,0 +1,494 @@
+package consumables
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetConsumables 获取耗材列表
+func GetConsumables(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters ConsumableFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"code": false,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "code", "name", "min_stock", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 耗材为公共库存，不按数据范围过滤
+	query := applyConsumableFilters(global.DB.Model(&models.Consumable{}), filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取耗材列表
+	var list []models.Consumable
+	if err := query.
+		Preload("Stocks", preloadStocks).
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&list).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	responses := make([]ConsumableResponse, len(list))
+	for i := range list {
+		responses[i] = buildConsumableResponse(&list[i])
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, responses)
+	utils.Success(c, response)
+}
+
+// GetConsumable 获取耗材详情及各位置库存
+func GetConsumable(c *gin.Context) {
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, buildConsumableResponse(consumable))
+}
+
+// CreateConsumable 创建耗材，初始库存通过入库登记
+func CreateConsumable(c *gin.Context) {
+	var req CreateConsumableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 检查耗材编码是否已存在
+	var existing models.Consumable
+	if err := global.DB.Where("code = ?", req.Code).First(&existing).Error; err == nil {
+		utils.Error(c, utils.CONSUMABLE_CODE_EXISTS, nil)
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		utils.InternalError(c, err)
+		return
+	}
+
+	consumable := models.Consumable{
+		Code:          req.Code,
+		Name:          req.Name,
+		Specification: req.Specification,
+		Unit:          req.Unit,
+		MinStock:      req.MinStock,
+		Description:   req.Description,
+	}
+	if err := global.DB.Create(&consumable).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, buildConsumableResponse(&consumable))
+}
+
+// UpdateConsumable 更新耗材
+func UpdateConsumable(c *gin.Context) {
+	var req UpdateConsumableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	// 检查耗材编码是否与其他耗材重复
+	if req.Code != nil && *req.Code != consumable.Code {
+		var existing models.Consumable
+		if err := global.DB.Where("code = ? AND id != ?", *req.Code, consumable.ID).First(&existing).Error; err == nil {
+			utils.Error(c, utils.CONSUMABLE_CODE_EXISTS, nil)
+			return
+		} else if err != gorm.ErrRecordNotFound {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	// 构建更新数据
+	updates := make(map[string]interface{})
+	if req.Code != nil {
+		updates["code"] = *req.Code
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Specification != nil {
+		updates["specification"] = *req.Specification
+	}
+	if req.Unit != nil {
+		updates["unit"] = *req.Unit
+	}
+	if req.MinStock != nil {
+		updates["min_stock"] = *req.MinStock
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	// 执行更新
+	if len(updates) > 0 {
+		if err := global.DB.Model(consumable).Updates(updates).Error; err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	if err := global.DB.Preload("Stocks", preloadStocks).First(consumable, consumable.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, buildConsumableResponse(consumable))
+}
+
+// DeleteConsumable 删除耗材，仍有库存时须先出库清零
+func DeleteConsumable(c *gin.Context) {
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	if consumable.TotalStock() > 0 {
+		utils.Error(c, utils.CONSUMABLE_HAS_STOCK, gin.H{"total_stock": consumable.TotalStock()})
+		return
+	}
+
+	if err := global.DB.Delete(consumable).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "耗材删除成功"})
+}
+
+// GetConsumableLedger 获取库存台账，按时间顺序列出出入库流水及变动后余额
+func GetConsumableLedger(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters LedgerFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	query := global.DB.Model(&models.ConsumableTransaction{}).Where("consumable_id = ?", consumable.ID)
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.Location != nil && *filters.Location != "" {
+		query = query.Where("location = ?", *filters.Location)
+	}
+	if filters.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filters.DepartmentID)
+	}
+	if filters.DateFrom != nil {
+		query = query.Where("created_at >= ?", *filters.DateFrom)
+	}
+	if filters.DateTo != nil {
+		query = query.Where("created_at <= ?", *filters.DateTo)
+	}
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取流水列表
+	var transactions []models.ConsumableTransaction
+	if err := query.
+		Preload("Department").
+		Order("id ASC").
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&transactions).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, transactions)
+	utils.Success(c, response)
+}
+
+// StockIn 耗材入库
+func StockIn(c *gin.Context) {
+	var req StockInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	location := strings.TrimSpace(req.Location)
+	if location == "" {
+		utils.ValidationError(c, "存放位置不能为空")
+		return
+	}
+
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	transaction := models.ConsumableTransaction{
+		ConsumableID: consumable.ID,
+		Type:         models.ConsumableTransactionIn,
+		Location:     location,
+		Quantity:     req.Quantity,
+		UnitPrice:    req.UnitPrice,
+		Supplier:     req.Supplier,
+		Notes:        req.Notes,
+		Operator:     auth.GetOperator(c),
+	}
+	recordTransaction(c, &transaction)
+}
+
+// StockOut 耗材出库，领用到部门或个人
+func StockOut(c *gin.Context) {
+	var req StockOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	location := strings.TrimSpace(req.Location)
+	if location == "" {
+		utils.ValidationError(c, "存放位置不能为空")
+		return
+	}
+	recipientName := strings.TrimSpace(req.RecipientName)
+	if req.DepartmentID == nil && recipientName == "" {
+		utils.ValidationError(c, "领用部门和领用人至少填写一项")
+		return
+	}
+
+	consumable, ok := findConsumable(c)
+	if !ok {
+		return
+	}
+
+	// 验证领用部门
+	if req.DepartmentID != nil {
+		var department models.Department
+		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	transaction := models.ConsumableTransaction{
+		ConsumableID:  consumable.ID,
+		Type:          models.ConsumableTransactionOut,
+		Location:      location,
+		Quantity:      req.Quantity,
+		DepartmentID:  req.DepartmentID,
+		RecipientName: recipientName,
+		Notes:         req.Notes,
+		Operator:      auth.GetOperator(c),
+	}
+	recordTransaction(c, &transaction)
+}
+
+// recordTransaction 在事务中登记出入库流水并返回流水记录
+func recordTransaction(c *gin.Context, transaction *models.ConsumableTransaction) {
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := models.RecordConsumableTransaction(tx, transaction); err != nil {
+		tx.Rollback()
+		if err == models.ErrInsufficientStock {
+			var available int
+			global.DB.Model(&models.ConsumableStock{}).
+				Where("consumable_id = ? AND location = ?", transaction.ConsumableID, transaction.Location).
+				Pluck("quantity", &available)
+			utils.Error(c, utils.CONSUMABLE_INSUFFICIENT_STOCK, gin.H{
+				"location":  transaction.Location,
+				"available": available,
+				"requested": transaction.Quantity,
+			})
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Preload("Consumable").Preload("Department").First(transaction, transaction.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	middleware.SetAuditTarget(c, "consumable_transactions", transaction.ID, models.OperationTypeCreate)
+	utils.Success(c, transaction)
+}
+
+// findConsumable 按路径参数查找耗材并加载各位置库存，失败时已写入响应
+func findConsumable(c *gin.Context) (*models.Consumable, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的耗材ID")
+		return nil, false
+	}
+
+	var consumable models.Consumable
+	if err := global.DB.Preload("Stocks", preloadStocks).First(&consumable, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.CONSUMABLE_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &consumable, true
+}
+
+// preloadStocks 按位置排序加载库存
+func preloadStocks(db *gorm.DB) *gorm.DB {
+	return db.Order("location ASC")
+}
+
+// buildConsumableResponse 构建耗材响应
+func buildConsumableResponse(consumable *models.Consumable) ConsumableResponse {
+	return ConsumableResponse{
+		Consumable: *consumable,
+		TotalStock: consumable.TotalStock(),
+		LowStock:   consumable.IsLowStock(),
+	}
+}
+
+// applyConsumableFilters 应用耗材筛选条件
+func applyConsumableFilters(query *gorm.DB, filters ConsumableFilters) *gorm.DB {
+	if filters.Keyword != nil && *filters.Keyword != "" {
+		keyword := "%" + *filters.Keyword + "%"
+		query = query.Where("(code LIKE ? OR name LIKE ? OR specification LIKE ?)", keyword, keyword, keyword)
+	}
+	if filters.Location != nil && *filters.Location != "" {
+		query = query.Where("id IN (?)", global.DB.Model(&models.ConsumableStock{}).
+			Select("consumable_id").
+			Where("location LIKE ? AND quantity > 0", "%"+*filters.Location+"%"))
+	}
+	if filters.LowStock != nil {
+		lowStock := models.LowStockConsumables(global.DB).Select("id")
+		if *filters.LowStock {
+			query = query.Where("id IN (?)", lowStock)
+		} else {
+			query = query.Where("id NOT IN (?)", lowStock)
+		}
+	}
+
+	return query
+}
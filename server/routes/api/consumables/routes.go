@@ -0,0 +1,20 @@
+package consumables
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册耗材路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	consumables := r.Group("/consumables")
+	{
+		consumables.GET("", GetConsumables)                 // 获取耗材列表
+		consumables.POST("", CreateConsumable)              // 创建耗材
+		consumables.GET("/:id", GetConsumable)              // 获取耗材详情及各位置库存
+		consumables.PUT("/:id", UpdateConsumable)           // 更新耗材
+		consumables.DELETE("/:id", DeleteConsumable)        // 删除耗材
+		consumables.GET("/:id/ledger", GetConsumableLedger) // 获取库存台账
+		consumables.POST("/:id/stock-in", StockIn)          // 入库
+		consumables.POST("/:id/stock-out", StockOut)        // 出库（领用）
+	}
+}
@@ -0,0 +1,68 @@
+package consumables
+
+import (
+	"time"
+
+	"asset-management-system/server/models"
+)
+
+// CreateConsumableRequest 创建耗材请求
+type CreateConsumableRequest struct {
+	Code          string `json:"code" validate:"required,max=50"`
+	Name          string `json:"name" validate:"required,max=200"`
+	Specification string `json:"specification" validate:"max=200"`
+	Unit          string `json:"unit" validate:"max=20"`
+	MinStock      int    `json:"min_stock" validate:"min=0"`
+	Description   string `json:"description"`
+}
+
+// UpdateConsumableRequest 更新耗材请求
+type UpdateConsumableRequest struct {
+	Code          *string `json:"code" validate:"omitempty,min=1,max=50"`
+	Name          *string `json:"name" validate:"omitempty,min=1,max=200"`
+	Specification *string `json:"specification" validate:"omitempty,max=200"`
+	Unit          *string `json:"unit" validate:"omitempty,max=20"`
+	MinStock      *int    `json:"min_stock" validate:"omitempty,min=0"`
+	Description   *string `json:"description"`
+}
+
+// StockInRequest 入库请求
+type StockInRequest struct {
+	Location  string   `json:"location" validate:"required,max=200"`
+	Quantity  int      `json:"quantity" validate:"required,min=1"`
+	UnitPrice *float64 `json:"unit_price" validate:"omitempty,min=0"`
+	Supplier  string   `json:"supplier" validate:"max=200"`
+	Notes     string   `json:"notes"`
+}
+
+// StockOutRequest 出库（领用）请求，领用部门和领用人至少填写一项
+type StockOutRequest struct {
+	Location      string `json:"location" validate:"required,max=200"`
+	Quantity      int    `json:"quantity" validate:"required,min=1"`
+	DepartmentID  *uint  `json:"department_id"`
+	RecipientName string `json:"recipient_name" validate:"max=100"`
+	Notes         string `json:"notes"`
+}
+
+// ConsumableResponse 耗材响应，附带各位置库存合计和预警状态
+type ConsumableResponse struct {
+	models.Consumable
+	TotalStock int  `json:"total_stock"`
+	LowStock   bool `json:"low_stock"`
+}
+
+// ConsumableFilters 耗材筛选条件
+type ConsumableFilters struct {
+	Keyword  *string `json:"keyword" form:"keyword"`
+	Location *string `json:"location" form:"location"`
+	LowStock *bool   `json:"low_stock" form:"low_stock"`
+}
+
+// LedgerFilters 库存台账筛选条件
+type LedgerFilters struct {
+	Type         *models.ConsumableTransactionType `json:"type" form:"type"`
+	Location     *string                           `json:"location" form:"location"`
+	DepartmentID *uint                             `json:"department_id" form:"department_id"`
+	DateFrom     *time.Time                        `json:"date_from" form:"date_from"`
+	DateTo       *time.Time                        `json:"date_to" form:"date_to"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,14 +28,19 @@ func RegisterRoutes(router *gin.RouterGroup) {
 
 // GetDashboardStats 获取仪表板统计数据
 func GetDashboardStats(c *gin.Context) {
+	scope, ok := getDataScope(c)
+	if !ok {
+		return
+	}
+
 	stats := map[string]interface{}{
-		"assets":         getAssetStats(),
+		"assets":         getAssetStats(scope),
 		"categories":     getCategoryStats(),
 		"departments":    getDepartmentStats(),
-		"borrow":         getBorrowStats(),
-		"inventory":      getInventoryStats(),
-		"recent_assets":  getRecentAssets(),
-		"recent_borrows": getRecentBorrows(),
+		"borrow":         getBorrowStats(scope),
+		"inventory":      getInventoryStats(scope),
+		"recent_assets":  getRecentAssets(scope),
+		"recent_borrows": getRecentBorrows(scope),
 		"system_status":  getSystemStatusInfo(),
 		"last_updated":   time.Now(),
 	}
@@ -54,9 +60,14 @@ func GetSystemStatus(c *gin.Context) {
 
 // GetRecentActivity 获取最近活动
 func GetRecentActivity(c *gin.Context) {
+	scope, ok := getDataScope(c)
+	if !ok {
+		return
+	}
+
 	activity := map[string]interface{}{
-		"recent_assets":  getRecentAssets(),
-		"recent_borrows": getRecentBorrows(),
+		"recent_assets":  getRecentAssets(scope),
+		"recent_borrows": getRecentBorrows(scope),
 	}
 
 	c.JSON(http.StatusOK, activity)
@@ -164,15 +175,30 @@ func TestDashboard(c *gin.Context) {
 	})
 }
 
+// getDataScope 获取当前请求的数据范围，失败时直接返回错误响应
+func getDataScope(c *gin.Context) (*auth.DataScope, bool) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "DATABASE_ERROR",
+			"message": "获取数据范围失败",
+			"data":    err.Error(),
+		})
+		return nil, false
+	}
+	return scope, true
+}
+
 // getAssetStats 获取资产统计
-func getAssetStats() map[string]interface{} {
+func getAssetStats(scope *auth.DataScope) map[string]interface{} {
 	var total, available, borrowed, maintenance, scrapped int64
+	db := scope.DB()
 
-	global.DB.Model(&models.Asset{}).Count(&total)
-	global.DB.Model(&models.Asset{}).Where("status = ?", "available").Count(&available)
-	global.DB.Model(&models.Asset{}).Where("status = ?", "borrowed").Count(&borrowed)
-	global.DB.Model(&models.Asset{}).Where("status = ?", "maintenance").Count(&maintenance)
-	global.DB.Model(&models.Asset{}).Where("status = ?", "scrapped").Count(&scrapped)
+	db.Model(&models.Asset{}).Count(&total)
+	db.Model(&models.Asset{}).Where("status = ?", "available").Count(&available)
+	db.Model(&models.Asset{}).Where("status = ?", "borrowed").Count(&borrowed)
+	db.Model(&models.Asset{}).Where("status = ?", "maintenance").Count(&maintenance)
+	db.Model(&models.Asset{}).Where("status = ?", "scrapped").Count(&scrapped)
 
 	return map[string]interface{}{
 		"total":       total,
@@ -204,16 +230,17 @@ func getDepartmentStats() map[string]interface{} {
 }
 
 // getBorrowStats 获取借用统计
-func getBorrowStats() map[string]interface{} {
+func getBorrowStats(scope *auth.DataScope) map[string]interface{} {
 	var total, active, overdue, todayReturns int64
+	db := scope.DB()
 
-	global.DB.Model(&models.BorrowRecord{}).Count(&total)
-	global.DB.Model(&models.BorrowRecord{}).Where("status = ?", "borrowed").Count(&active)
-	global.DB.Model(&models.BorrowRecord{}).Where("status = ? AND expected_return_date < ?", "borrowed", time.Now()).Count(&overdue)
+	db.Model(&models.BorrowRecord{}).Count(&total)
+	db.Model(&models.BorrowRecord{}).Where("status = ?", "borrowed").Count(&active)
+	db.Model(&models.BorrowRecord{}).Where("status = ? AND expected_return_date < ?", "borrowed", time.Now()).Count(&overdue)
 
 	// 计算今日归还数量
 	today := time.Now().Format("2006-01-02")
-	global.DB.Model(&models.BorrowRecord{}).Where("status = ? AND DATE(return_date) = ?", "returned", today).Count(&todayReturns)
+	db.Model(&models.BorrowRecord{}).Where("status = ? AND DATE(return_date) = ?", "returned", today).Count(&todayReturns)
 
 	return map[string]interface{}{
 		"total":         total,
@@ -224,12 +251,13 @@ func getBorrowStats() map[string]interface{} {
 }
 
 // getInventoryStats 获取盘点统计
-func getInventoryStats() map[string]interface{} {
+func getInventoryStats(scope *auth.DataScope) map[string]interface{} {
 	var total, pending, completed int64
+	db := scope.DB()
 
-	global.DB.Model(&models.InventoryTask{}).Count(&total)
-	global.DB.Model(&models.InventoryTask{}).Where("status = ?", "pending").Count(&pending)
-	global.DB.Model(&models.InventoryTask{}).Where("status = ?", "completed").Count(&completed)
+	db.Model(&models.InventoryTask{}).Count(&total)
+	db.Model(&models.InventoryTask{}).Where("status = ?", "pending").Count(&pending)
+	db.Model(&models.InventoryTask{}).Where("status = ?", "completed").Count(&completed)
 
 	return map[string]interface{}{
 		"total":     total,
@@ -292,8 +320,9 @@ func checkDatabaseStatus() ServiceStatus {
 }
 
 // getRecentAssets 获取最近添加的资产
-func getRecentAssets() []map[string]interface{} {
+func getRecentAssets(scope *auth.DataScope) []map[string]interface{} {
 	var assets []map[string]interface{}
+	assetCondition, assetArgs := scope.Condition("assets", "a")
 
 	rows, err := global.DB.Raw(`
 		SELECT a.id, a.asset_no, a.name, a.status, a.created_at,
@@ -301,10 +330,10 @@ func getRecentAssets() []map[string]interface{} {
 		FROM assets a
 		LEFT JOIN categories c ON c.id = a.category_id AND c.deleted_at IS NULL
 		LEFT JOIN departments d ON d.id = a.department_id AND d.deleted_at IS NULL
-		WHERE a.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL AND `+assetCondition+`
 		ORDER BY a.created_at DESC
 		LIMIT 5
-	`).Rows()
+	`, assetArgs...).Rows()
 
 	if err != nil {
 		return assets
@@ -336,18 +365,19 @@ func getRecentAssets() []map[string]interface{} {
 }
 
 // getRecentBorrows 获取最近的借用记录
-func getRecentBorrows() []map[string]interface{} {
+func getRecentBorrows(scope *auth.DataScope) []map[string]interface{} {
 	var borrows []map[string]interface{}
+	borrowCondition, borrowArgs := scope.Condition("borrow_records", "br")
 
 	rows, err := global.DB.Raw(`
 		SELECT br.id, br.borrower_name, br.borrow_date, br.status, br.expected_return_date,
 		       a.asset_no, a.name as asset_name
 		FROM borrow_records br
 		JOIN assets a ON a.id = br.asset_id AND a.deleted_at IS NULL
-		WHERE br.deleted_at IS NULL AND br.status = 'borrowed'
+		WHERE br.deleted_at IS NULL AND br.status = 'borrowed' AND `+borrowCondition+`
 		ORDER BY br.borrow_date DESC
 		LIMIT 5
-	`).Rows()
+	`, borrowArgs...).Rows()
 
 	if err != nil {
 		return borrows
@@ -3,6 +3,7 @@ package departments
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 	"asset-management-system/server/pkg/utils"
 	"fmt"
 	"strconv"
@@ -47,7 +48,7 @@ func GetDepartments(c *gin.Context) {
 	})
 
 	// 验证排序字段
-	allowedSortFields := []string{"id", "name", "code", "manager", "contact", "created_at", "updated_at"}
+	allowedSortFields := []string{"id", "name", "code", "parent_id", "manager", "contact", "created_at", "updated_at"}
 	for _, sort := range req.Sorts {
 		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
 			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
@@ -160,13 +161,36 @@ func CreateDepartment(c *gin.Context) {
 		return
 	}
 
+	// 验证上级部门是否存在
+	if req.ParentID != nil {
+		var parent models.Department
+		if err := global.DB.First(&parent, *req.ParentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.ErrorWithMessage(c, utils.DEPARTMENT_NOT_FOUND, "上级部门不存在", nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	// 检查 DooTask 部门是否已关联其他部门
+	if req.DooTaskDepartmentID != nil {
+		if err := checkDooTaskDepartmentBound(*req.DooTaskDepartmentID, 0); err != nil {
+			utils.ErrorWithMessage(c, utils.VALIDATION_ERROR, err.Error(), nil)
+			return
+		}
+	}
+
 	// 创建部门
 	department := models.Department{
-		Name:        req.Name,
-		Code:        req.Code,
-		Manager:     req.Manager,
-		Contact:     req.Contact,
-		Description: req.Description,
+		Name:                req.Name,
+		Code:                req.Code,
+		ParentID:            req.ParentID,
+		DooTaskDepartmentID: req.DooTaskDepartmentID,
+		Manager:             req.Manager,
+		Contact:             req.Contact,
+		Description:         req.Description,
 	}
 
 	if err := global.DB.Create(&department).Error; err != nil {
@@ -233,6 +257,46 @@ func UpdateDepartment(c *gin.Context) {
 	if req.Code != nil {
 		updates["code"] = *req.Code
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			updates["parent_id"] = nil
+		} else {
+			// 上级部门不能是自身或自身的子部门
+			descendantIDs, err := models.GetDepartmentDescendantIDs(global.DB, department.ID)
+			if err != nil {
+				utils.InternalError(c, err)
+				return
+			}
+			for _, descendantID := range descendantIDs {
+				if descendantID == *req.ParentID {
+					utils.ValidationError(c, "上级部门不能是自身或其子部门")
+					return
+				}
+			}
+
+			var parent models.Department
+			if err := global.DB.First(&parent, *req.ParentID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					utils.ErrorWithMessage(c, utils.DEPARTMENT_NOT_FOUND, "上级部门不存在", nil)
+					return
+				}
+				utils.InternalError(c, err)
+				return
+			}
+			updates["parent_id"] = *req.ParentID
+		}
+	}
+	if req.DooTaskDepartmentID != nil {
+		if *req.DooTaskDepartmentID == 0 {
+			updates["dootask_department_id"] = nil
+		} else {
+			if err := checkDooTaskDepartmentBound(*req.DooTaskDepartmentID, department.ID); err != nil {
+				utils.ErrorWithMessage(c, utils.VALIDATION_ERROR, err.Error(), nil)
+				return
+			}
+			updates["dootask_department_id"] = *req.DooTaskDepartmentID
+		}
+	}
 	// 对于非必填字段，允许空字符串清空字段
 	if req.Manager != nil {
 		updates["manager"] = *req.Manager
@@ -300,6 +364,20 @@ func DeleteDepartment(c *gin.Context) {
 		return
 	}
 
+	// 检查是否有子部门
+	var childCount int64
+	if err := global.DB.Model(&models.Department{}).
+		Where("parent_id = ?", id).
+		Count(&childCount).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if childCount > 0 {
+		utils.Error(c, utils.DEPARTMENT_HAS_CHILDREN, nil)
+		return
+	}
+
 	// 删除部门
 	if err := global.DB.Delete(&department).Error; err != nil {
 		utils.InternalError(c, err)
@@ -317,6 +395,18 @@ func GetDepartmentStats(c *gin.Context) {
 		return
 	}
 
+	// 只能查看数据范围内的部门统计
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	departmentID := uint(id)
+	if !scope.CanAccessDepartment(&departmentID) {
+		utils.Error(c, utils.FORBIDDEN, nil)
+		return
+	}
+
 	// 验证部门是否存在
 	var department models.Department
 	if err := global.DB.First(&department, id).Error; err != nil {
@@ -386,11 +476,55 @@ func GetDepartmentStats(c *gin.Context) {
 		return
 	}
 
+	// 按调入、调出和状态统计调拨单
+	var transferStats DepartmentTransferStats
+	transferCounts := []struct {
+		Incoming bool
+		Status   models.TransferStatus
+		Count    int64
+	}{}
+	if err := global.DB.Model(&models.AssetTransfer{}).
+		Select("to_department_id = ? as incoming, status, COUNT(*) as count", id).
+		Where("to_department_id = ? OR from_department_id = ?", id, id).
+		Group("incoming, status").
+		Scan(&transferCounts).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	for _, stat := range transferCounts {
+		switch {
+		case stat.Status == models.TransferStatusCompleted && stat.Incoming:
+			transferStats.Incoming += stat.Count
+		case stat.Status == models.TransferStatusCompleted:
+			transferStats.Outgoing += stat.Count
+		case models.IsOpenTransferStatus(stat.Status) && stat.Incoming:
+			transferStats.PendingIncoming += stat.Count
+		case models.IsOpenTransferStatus(stat.Status):
+			transferStats.PendingOutgoing += stat.Count
+		}
+	}
+
+	// 获取最近的调拨记录
+	var recentTransfers []models.AssetTransfer
+	if err := global.DB.
+		Preload("Asset").
+		Preload("FromDepartment").
+		Preload("ToDepartment").
+		Where("to_department_id = ? OR from_department_id = ?", id, id).
+		Order("created_at DESC").
+		Limit(5).
+		Find(&recentTransfers).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	response := DepartmentStatsResponse{
 		TotalAssets:      totalAssets,
 		AssetsByStatus:   assetsByStatus,
 		AssetsByCategory: assetsByCategory,
 		RecentAssets:     recentAssets,
+		Transfers:        transferStats,
+		RecentTransfers:  recentTransfers,
 	}
 
 	utils.Success(c, response)
@@ -407,6 +541,27 @@ func applyDepartmentFilters(query *gorm.DB, filters DepartmentFilters) *gorm.DB
 	if filters.Manager != nil && *filters.Manager != "" {
 		query = query.Where("manager LIKE ?", "%"+*filters.Manager+"%")
 	}
+	if filters.ParentID != nil {
+		if *filters.ParentID == 0 {
+			query = query.Where("parent_id IS NULL")
+		} else {
+			query = query.Where("parent_id = ?", *filters.ParentID)
+		}
+	}
 
 	return query
 }
+
+// checkDooTaskDepartmentBound 检查 DooTask 部门是否已关联到其他部门
+func checkDooTaskDepartmentBound(dooTaskDepartmentID uint, excludeID uint) error {
+	var count int64
+	if err := global.DB.Model(&models.Department{}).
+		Where("dootask_department_id = ? AND id != ?", dooTaskDepartmentID, excludeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("DooTask部门 %d 已关联其他部门", dooTaskDepartmentID)
+	}
+	return nil
+}
@@ -6,20 +6,24 @@ import (
 
 // CreateDepartmentRequest 创建部门请求
 type CreateDepartmentRequest struct {
-	Name        string `json:"name" validate:"required,max=100"`
-	Code        string `json:"code" validate:"required,max=50"`
-	Manager     string `json:"manager" validate:"max=100"`
-	Contact     string `json:"contact" validate:"max=100"`
-	Description string `json:"description"`
+	Name                string `json:"name" validate:"required,max=100"`
+	Code                string `json:"code" validate:"required,max=50"`
+	ParentID            *uint  `json:"parent_id"`
+	DooTaskDepartmentID *uint  `json:"dootask_department_id"`
+	Manager             string `json:"manager" validate:"max=100"`
+	Contact             string `json:"contact" validate:"max=100"`
+	Description         string `json:"description"`
 }
 
 // UpdateDepartmentRequest 更新部门请求
 type UpdateDepartmentRequest struct {
-	Name        *string `json:"name" validate:"omitempty,max=100"`
-	Code        *string `json:"code" validate:"omitempty,max=50"`
-	Manager     *string `json:"manager" validate:"omitempty,max=100"`
-	Contact     *string `json:"contact" validate:"omitempty,max=100"`
-	Description *string `json:"description"`
+	Name                *string `json:"name" validate:"omitempty,max=100"`
+	Code                *string `json:"code" validate:"omitempty,max=50"`
+	ParentID            *uint   `json:"parent_id"`             // 传0表示设为顶级部门
+	DooTaskDepartmentID *uint   `json:"dootask_department_id"` // 传0表示取消关联
+	Manager             *string `json:"manager" validate:"omitempty,max=100"`
+	Contact             *string `json:"contact" validate:"omitempty,max=100"`
+	Description         *string `json:"description"`
 }
 
 // DepartmentResponse 部门响应
@@ -30,9 +34,10 @@ type DepartmentResponse struct {
 
 // DepartmentFilters 部门筛选条件
 type DepartmentFilters struct {
-	Name    *string `json:"name" form:"name"`
-	Code    *string `json:"code" form:"code"`
-	Manager *string `json:"manager" form:"manager"`
+	Name     *string `json:"name" form:"name"`
+	Code     *string `json:"code" form:"code"`
+	Manager  *string `json:"manager" form:"manager"`
+	ParentID *uint   `json:"parent_id" form:"parent_id"`
 }
 
 // DepartmentStatsResponse 部门统计响应
@@ -41,4 +46,15 @@ type DepartmentStatsResponse struct {
 	AssetsByStatus   map[string]int64 `json:"assets_by_status"`
 	AssetsByCategory map[string]int64 `json:"assets_by_category"`
 	RecentAssets     []models.Asset   `json:"recent_assets"`
+
+	Transfers       DepartmentTransferStats `json:"transfers"`        // 调拨统计
+	RecentTransfers []models.AssetTransfer  `json:"recent_transfers"` // 最近的调入调出记录
+}
+
+// DepartmentTransferStats 部门调拨统计
+type DepartmentTransferStats struct {
+	Incoming        int64 `json:"incoming"`         // 已完成调入
+	Outgoing        int64 `json:"outgoing"`         // 已完成调出
+	PendingIncoming int64 `json:"pending_incoming"` // 进行中的调入
+	PendingOutgoing int64 `json:"pending_outgoing"` // 进行中的调出
 }
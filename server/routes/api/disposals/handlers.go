@@ -0,0 +1,660 @@
+package disposals
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/depreciation"
+	"asset-management-system/server/pkg/utils"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetDisposals 获取处置申请列表
+func GetDisposals(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters DisposalFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"created_at": true,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "asset_id", "method", "residual_value", "status", "reviewed_at", "disposal_date", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.AssetDisposal{}).Preload("Asset")
+
+	// 应用筛选条件
+	if filters.AssetID != nil {
+		query = query.Where("asset_id = ?", *filters.AssetID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.Method != nil {
+		query = query.Where("method = ?", *filters.Method)
+	}
+	if filters.RequestedBy != nil && *filters.RequestedBy != "" {
+		query = query.Where("requested_by LIKE ?", "%"+*filters.RequestedBy+"%")
+	}
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取处置申请列表
+	var disposals []models.AssetDisposal
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&disposals).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, disposals)
+	utils.Success(c, response)
+}
+
+// GetDisposal 获取处置申请详情
+func GetDisposal(c *gin.Context) {
+	disposal, ok := findDisposal(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, disposal)
+}
+
+// CreateDisposal 发起处置申请
+func CreateDisposal(c *gin.Context) {
+	var req CreateDisposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 只能处置数据范围内的资产
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	var asset models.Asset
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 已核销的资产不能再次处置
+	frozen, err := models.IsAssetFrozen(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if frozen {
+		utils.Error(c, utils.ASSET_FROZEN, nil)
+		return
+	}
+
+	// 同一资产同时只能有一张进行中的处置申请
+	inProgress, err := models.HasOpenDisposal(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if inProgress {
+		utils.Error(c, utils.DISPOSAL_IN_PROGRESS, nil)
+		return
+	}
+
+	disposal := models.AssetDisposal{
+		AssetID:       asset.ID,
+		Reason:        req.Reason,
+		Method:        req.Method,
+		ResidualValue: req.ResidualValue,
+		Status:        models.DisposalStatusRequested,
+		RequestedBy:   auth.GetOperator(c),
+	}
+
+	if err := global.DB.Create(&disposal).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Preload("Asset").First(&disposal, disposal.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, disposal)
+}
+
+// ApproveDisposal 审批通过处置申请，批准后资产冻结，不能再借用、编辑或删除
+func ApproveDisposal(c *gin.Context) {
+	var req ReviewDisposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	disposal, ok := findDisposal(c)
+	if !ok {
+		return
+	}
+
+	if !disposal.CanTransitionTo(models.DisposalStatusApproved) {
+		utils.Error(c, utils.DISPOSAL_INVALID_STATUS, gin.H{"status": disposal.Status})
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(disposal).Updates(map[string]interface{}{
+		"status":         models.DisposalStatusApproved,
+		"reviewed_by":    auth.GetOperator(c),
+		"reviewed_at":    time.Now(),
+		"review_comment": req.Comment,
+	}).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 批准前确认资产当前可以报废（如没有未归还的借用、未关闭的维修工单）
+	var asset models.Asset
+	if err := tx.First(&asset, disposal.AssetID).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+	if err := models.CheckAssetStatusTransition(tx, &asset, models.AssetStatusScrapped); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadDisposal(global.DB).First(disposal, disposal.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, disposal)
+}
+
+// RejectDisposal 驳回处置申请，已批准的申请驳回后资产解除冻结
+func RejectDisposal(c *gin.Context) {
+	var req ReviewDisposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	disposal, ok := findDisposal(c)
+	if !ok {
+		return
+	}
+
+	if !disposal.CanTransitionTo(models.DisposalStatusRejected) {
+		utils.Error(c, utils.DISPOSAL_INVALID_STATUS, gin.H{"status": disposal.Status})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":         models.DisposalStatusRejected,
+		"reviewed_by":    auth.GetOperator(c),
+		"reviewed_at":    time.Now(),
+		"review_comment": req.Comment,
+	}
+	if err := global.DB.Model(disposal).Updates(updates).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadDisposal(global.DB).First(disposal, disposal.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, disposal)
+}
+
+// CompleteDisposal 完成处置，在同一事务中报废资产并生成核销记录
+func CompleteDisposal(c *gin.Context) {
+	var req CompleteDisposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	disposal, ok := findDisposal(c)
+	if !ok {
+		return
+	}
+
+	if !disposal.CanTransitionTo(models.DisposalStatusCompleted) {
+		utils.Error(c, utils.DISPOSAL_INVALID_STATUS, gin.H{"status": disposal.Status})
+		return
+	}
+
+	disposalDate := time.Now()
+	if req.DisposalDate != nil {
+		disposalDate = *req.DisposalDate
+	}
+	if disposal.ReviewedAt != nil && disposalDate.Before(disposal.ReviewedAt.Truncate(24*time.Hour)) {
+		utils.ValidationError(c, "处置日期不能早于审批日期")
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if req.ResidualValue != nil {
+		if err := tx.Model(disposal).Update("residual_value", *req.ResidualValue).Error; err != nil {
+			tx.Rollback()
+			utils.InternalError(c, err)
+			return
+		}
+		disposal.ResidualValue = req.ResidualValue
+	}
+
+	writeOff, err := buildWriteOff(tx, disposal, disposalDate, auth.GetOperator(c))
+	if err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := disposal.Complete(tx, writeOff); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadDisposal(global.DB).First(disposal, disposal.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, disposal)
+}
+
+// GetWriteOffs 获取核销记录列表
+func GetWriteOffs(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters WriteOffFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"disposal_date": true,
+		"id":            true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "asset_id", "asset_no", "department_id", "method", "original_value", "net_value", "residual_value", "gain_loss", "disposal_date", "created_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	query, ok := writeOffQuery(c, filters)
+	if !ok {
+		return
+	}
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取核销记录列表
+	var writeOffs []models.AssetWriteOff
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&writeOffs).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, writeOffs)
+	utils.Success(c, response)
+}
+
+// ExportWriteOffs 导出核销记录，供财务入账
+func ExportWriteOffs(c *gin.Context) {
+	// 解析筛选条件
+	var filters WriteOffFilters
+	var req utils.PaginationRequest
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	query, ok := writeOffQuery(c, filters)
+	if !ok {
+		return
+	}
+
+	var writeOffs []models.AssetWriteOff
+	if err := query.Order("disposal_date, id").Find(&writeOffs).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 创建Excel文件
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	// 设置工作表名称
+	sheetName := "资产核销"
+	f.SetSheetName("Sheet1", sheetName)
+
+	// 设置表头
+	headers := []string{
+		"核销编号", "处置日期", "资产编号", "资产名称", "分类", "部门", "处置方式", "处置原因",
+		"原值", "累计折旧", "账面净值", "残值收入", "处置损益", "审批人", "审批日期", "核销人",
+	}
+
+	// 写入表头
+	for i, header := range headers {
+		cell := fmt.Sprintf("%c1", 'A'+i)
+		f.SetCellValue(sheetName, cell, header)
+	}
+
+	// 处置方式映射
+	methodMap := map[models.DisposalMethod]string{
+		models.DisposalMethodSell:           "出售",
+		models.DisposalMethodDonate:         "捐赠",
+		models.DisposalMethodDestroy:        "销毁",
+		models.DisposalMethodReturnToVendor: "退回供应商",
+	}
+
+	// 写入数据
+	for i, writeOff := range writeOffs {
+		row := i + 2 // 从第2行开始写入数据
+
+		methodText := methodMap[writeOff.Method]
+		if methodText == "" {
+			methodText = string(writeOff.Method)
+		}
+
+		approvedAtStr := ""
+		if writeOff.ApprovedAt != nil {
+			approvedAtStr = writeOff.ApprovedAt.Format("2006-01-02")
+		}
+
+		data := []interface{}{
+			writeOff.ID,
+			writeOff.DisposalDate.Format("2006-01-02"),
+			writeOff.AssetNo,
+			writeOff.AssetName,
+			writeOff.CategoryName,
+			writeOff.DepartmentName,
+			methodText,
+			writeOff.Reason,
+			writeOff.OriginalValue,
+			writeOff.AccumulatedDepreciation,
+			writeOff.NetValue,
+			writeOff.ResidualValue,
+			writeOff.GainLoss,
+			writeOff.ApprovedBy,
+			approvedAtStr,
+			writeOff.WrittenOffBy,
+		}
+
+		for j, value := range data {
+			cell := fmt.Sprintf("%c%d", 'A'+j, row)
+			f.SetCellValue(sheetName, cell, value)
+		}
+	}
+
+	// 设置列宽
+	columnWidths := []float64{10, 12, 15, 20, 15, 15, 12, 30, 12, 12, 12, 12, 12, 12, 12, 12}
+	for i, width := range columnWidths {
+		col := fmt.Sprintf("%c:%c", 'A'+i, 'A'+i)
+		f.SetColWidth(sheetName, col, col, width)
+	}
+
+	// 设置响应头
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", "attachment; filename=资产核销.xlsx")
+
+	// 写入响应
+	if err := f.Write(c.Writer); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+}
+
+// buildWriteOff 生成核销记录快照，账面净值按处置当月的折旧计划计算
+// 无法计算折旧的资产（缺少价格、日期或折旧参数）按原值核销
+func buildWriteOff(tx *gorm.DB, disposal *models.AssetDisposal, disposalDate time.Time, operator string) (*models.AssetWriteOff, error) {
+	var asset models.Asset
+	if err := tx.Preload("Category").Preload("Department").First(&asset, disposal.AssetID).Error; err != nil {
+		return nil, err
+	}
+
+	writeOff := &models.AssetWriteOff{
+		AssetNo:      asset.AssetNo,
+		AssetName:    asset.Name,
+		CategoryName: asset.Category.Name,
+		DepartmentID: asset.DepartmentID,
+		Method:       disposal.Method,
+		Reason:       disposal.Reason,
+		ApprovedBy:   disposal.ReviewedBy,
+		ApprovedAt:   disposal.ReviewedAt,
+		DisposalDate: disposalDate,
+		WrittenOffBy: operator,
+	}
+	if asset.Department.ID != 0 {
+		writeOff.DepartmentName = asset.Department.Name
+	}
+	if asset.PurchasePrice != nil {
+		writeOff.OriginalValue = roundAmount(*asset.PurchasePrice)
+	}
+	writeOff.NetValue = writeOff.OriginalValue
+
+	schedule, err := depreciation.NewResolver(tx).Schedule(&asset)
+	if err != nil && !depreciation.IsSettingsError(err) {
+		return nil, err
+	}
+	if err == nil {
+		summary := schedule.At(disposalDate.Format(depreciation.PeriodLayout))
+		writeOff.AccumulatedDepreciation = summary.Accumulated
+		writeOff.NetValue = summary.NetValue
+	}
+
+	if disposal.ResidualValue != nil {
+		writeOff.ResidualValue = roundAmount(*disposal.ResidualValue)
+	}
+	writeOff.GainLoss = roundAmount(writeOff.ResidualValue - writeOff.NetValue)
+
+	return writeOff, nil
+}
+
+// writeOffQuery 构建按数据范围和筛选条件过滤的核销记录查询，失败时已写入响应
+func writeOffQuery(c *gin.Context, filters WriteOffFilters) (*gorm.DB, bool) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+	query := scope.DB().Model(&models.AssetWriteOff{})
+
+	if filters.AssetID != nil {
+		query = query.Where("asset_id = ?", *filters.AssetID)
+	}
+	if filters.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filters.DepartmentID)
+	}
+	if filters.Method != nil {
+		query = query.Where("method = ?", *filters.Method)
+	}
+	if filters.DisposalDateFrom != nil {
+		query = query.Where("disposal_date >= ?", *filters.DisposalDateFrom)
+	}
+	if filters.DisposalDateTo != nil {
+		query = query.Where("disposal_date <= ?", *filters.DisposalDateTo)
+	}
+
+	return query, true
+}
+
+// findDisposal 按路径参数查找数据范围内的处置申请，失败时已写入响应
+func findDisposal(c *gin.Context) (*models.AssetDisposal, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的处置申请ID")
+		return nil, false
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	var disposal models.AssetDisposal
+	if err := preloadDisposal(scope.DB()).First(&disposal, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.DISPOSAL_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &disposal, true
+}
+
+// preloadDisposal 预加载处置申请关联数据
+func preloadDisposal(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Asset").
+		Preload("WriteOff")
+}
+
+// roundAmount 金额保留两位小数
+func roundAmount(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,20 @@
+package disposals
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册资产处置路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	disposals := r.Group("/disposals")
+	{
+		disposals.GET("", GetDisposals)                      // 获取处置申请列表
+		disposals.POST("", CreateDisposal)                   // 发起处置申请
+		disposals.GET("/write-offs", GetWriteOffs)           // 获取核销记录列表
+		disposals.GET("/write-offs/export", ExportWriteOffs) // 导出核销记录
+		disposals.GET("/:id", GetDisposal)                   // 获取处置申请详情
+		disposals.PUT("/:id/approve", ApproveDisposal)       // 审批通过
+		disposals.PUT("/:id/reject", RejectDisposal)         // 驳回
+		disposals.PUT("/:id/complete", CompleteDisposal)     // 完成处置并核销
+	}
+}
@@ -0,0 +1,42 @@
+package disposals
+
+import (
+	"asset-management-system/server/models"
+	"time"
+)
+
+// CreateDisposalRequest 发起处置申请请求
+type CreateDisposalRequest struct {
+	AssetID       uint                  `json:"asset_id" validate:"required"`
+	Reason        string                `json:"reason" validate:"required"`
+	Method        models.DisposalMethod `json:"method" validate:"required,oneof=sell donate destroy return_to_vendor"`
+	ResidualValue *float64              `json:"residual_value" validate:"omitempty,min=0"`
+}
+
+// ReviewDisposalRequest 审批处置申请请求
+type ReviewDisposalRequest struct {
+	Comment string `json:"comment"`
+}
+
+// CompleteDisposalRequest 完成处置请求
+type CompleteDisposalRequest struct {
+	DisposalDate  *time.Time `json:"disposal_date"`                             // 实际处置日期，默认当天
+	ResidualValue *float64   `json:"residual_value" validate:"omitempty,min=0"` // 实际残值收入，默认取申请时的预计残值
+}
+
+// DisposalFilters 处置申请筛选条件
+type DisposalFilters struct {
+	AssetID     *uint                  `json:"asset_id" form:"asset_id"`
+	Status      *models.DisposalStatus `json:"status" form:"status"`
+	Method      *models.DisposalMethod `json:"method" form:"method"`
+	RequestedBy *string                `json:"requested_by" form:"requested_by"`
+}
+
+// WriteOffFilters 核销记录筛选条件
+type WriteOffFilters struct {
+	AssetID          *uint                  `json:"asset_id" form:"asset_id"`
+	DepartmentID     *uint                  `json:"department_id" form:"department_id"`
+	Method           *models.DisposalMethod `json:"method" form:"method"`
+	DisposalDateFrom *time.Time             `json:"disposal_date_from" form:"disposal_date_from"`
+	DisposalDateTo   *time.Time             `json:"disposal_date_to" form:"disposal_date_to"`
+}
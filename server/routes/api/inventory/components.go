@@ -0,0 +1,68 @@
+package inventory
+
+import (
+	"fmt"
+
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+
+	"gorm.io/gorm"
+)
+
+// createKitComponentRecords 按套件盘点：为父资产的下级组件登记与父资产相同的盘点结果
+// 不在数据范围内或本任务中已有盘点记录的组件跳过
+func createKitComponentRecords(tx *gorm.DB, scope *auth.DataScope, kit *models.InventoryRecord) ([]models.InventoryRecord, error) {
+	ids, err := models.ComponentDescendantIDs(tx, kit.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var components []models.Asset
+	if err := scope.Apply(tx).Where("id IN ?", ids).Find(&components).Error; err != nil {
+		return nil, err
+	}
+
+	var recorded []uint
+	if err := tx.Model(&models.InventoryRecord{}).
+		Where("task_id = ? AND asset_id IN ?", kit.TaskID, ids).
+		Pluck("asset_id", &recorded).Error; err != nil {
+		return nil, err
+	}
+	skip := make(map[uint]bool, len(recorded))
+	for _, id := range recorded {
+		skip[id] = true
+	}
+
+	records := make([]models.InventoryRecord, 0, len(components))
+	for _, component := range components {
+		if skip[component.ID] {
+			continue
+		}
+
+		// 结果正常时组件实际状态即系统状态，否则与父资产一致
+		actualStatus := kit.ActualStatus
+		if kit.Result == models.InventoryResultNormal {
+			actualStatus = component.Status
+		}
+
+		record := models.InventoryRecord{
+			TaskID:         kit.TaskID,
+			AssetID:        component.ID,
+			ExpectedStatus: component.Status,
+			ActualStatus:   actualStatus,
+			Result:         kit.Result,
+			Notes:          fmt.Sprintf("随套件盘点（盘点记录 #%d）", kit.ID),
+			CheckedAt:      kit.CheckedAt,
+			CheckedBy:      kit.CheckedBy,
+			KitRecordID:    &kit.ID,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -3,6 +3,7 @@ package inventory
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 	"asset-management-system/server/pkg/utils"
 	"encoding/json"
 	"fmt"
@@ -35,8 +36,13 @@ func GetInventoryTasks(c *gin.Context) {
 		query.PageSize = 100
 	}
 
-	// 构建查询
-	db := global.DB.Model(&models.InventoryTask{})
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	db := scope.DB().Model(&models.InventoryTask{})
 
 	// 状态筛选
 	if query.Status != "" {
@@ -96,6 +102,26 @@ func CreateInventoryTask(c *gin.Context) {
 		return
 	}
 
+	// 部门受限用户只能为本部门及子部门创建盘点任务
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if req.DepartmentID == nil && !scope.All {
+		req.DepartmentID = scope.DepartmentID
+	}
+	if !scope.CanAccessDepartment(req.DepartmentID) {
+		utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权为该部门创建盘点任务", nil)
+		return
+	}
+	for _, departmentID := range req.ScopeFilter.DepartmentIDs {
+		if !scope.CanAccessDepartment(&departmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, fmt.Sprintf("无权盘点部门 %d 的资产", departmentID), nil)
+			return
+		}
+	}
+
 	// 序列化范围过滤条件
 	scopeFilterJSON, err := json.Marshal(req.ScopeFilter)
 	if err != nil {
@@ -105,14 +131,15 @@ func CreateInventoryTask(c *gin.Context) {
 
 	// 创建盘点任务
 	task := models.InventoryTask{
-		TaskName:    req.TaskName,
-		TaskType:    req.TaskType,
-		ScopeFilter: scopeFilterJSON,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-		CreatedBy:   req.CreatedBy,
-		Notes:       req.Notes,
-		Status:      models.InventoryTaskStatusPending,
+		TaskName:     req.TaskName,
+		TaskType:     req.TaskType,
+		ScopeFilter:  scopeFilterJSON,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		CreatedBy:    auth.GetOperator(c),
+		DepartmentID: req.DepartmentID,
+		Notes:        req.Notes,
+		Status:       models.InventoryTaskStatusPending,
 	}
 
 	if err := global.DB.Create(&task).Error; err != nil {
@@ -140,8 +167,14 @@ func GetInventoryTask(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
 	var task models.InventoryTask
-	if err := global.DB.Preload("Records").
+	if err := scope.DB().Preload("Records").
 		Preload("Records.Asset").
 		Preload("Records.Asset.Category").
 		Preload("Records.Asset.Department").
@@ -178,9 +211,14 @@ func UpdateInventoryTask(c *gin.Context) {
 		return
 	}
 
-	// 查找任务
+	// 查找任务（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var task models.InventoryTask
-	if err := global.DB.First(&task, id).Error; err != nil {
+	if err := scope.DB().First(&task, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.NotFound(c, "盘点任务不存在")
 			return
@@ -240,9 +278,14 @@ func DeleteInventoryTask(c *gin.Context) {
 		return
 	}
 
-	// 查找任务
+	// 查找任务（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var task models.InventoryTask
-	if err := global.DB.First(&task, id).Error; err != nil {
+	if err := scope.DB().First(&task, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.NotFound(c, "盘点任务不存在")
 			return
@@ -297,8 +340,13 @@ func GetInventoryRecords(c *gin.Context) {
 		query.PageSize = 100
 	}
 
-	// 构建查询
-	db := global.DB.Model(&models.InventoryRecord{}).
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	db := scope.DB().Model(&models.InventoryRecord{}).
 		Preload("Asset").
 		Preload("Asset.Category").
 		Preload("Asset.Department").
@@ -356,9 +404,14 @@ func CreateInventoryRecord(c *gin.Context) {
 		return
 	}
 
-	// 验证任务是否存在且状态正确
+	// 验证任务是否存在且状态正确（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var task models.InventoryTask
-	if err := global.DB.First(&task, req.TaskID).Error; err != nil {
+	if err := scope.DB().First(&task, req.TaskID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.NotFound(c, "盘点任务不存在")
 			return
@@ -374,7 +427,7 @@ func CreateInventoryRecord(c *gin.Context) {
 
 	// 验证资产是否存在
 	var asset models.Asset
-	if err := global.DB.First(&asset, req.AssetID).Error; err != nil {
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.NotFound(c, "资产不存在")
 			return
@@ -400,10 +453,34 @@ func CreateInventoryRecord(c *gin.Context) {
 		Result:         req.Result,
 		Notes:          req.Notes,
 		CheckedAt:      &now,
-		CheckedBy:      req.CheckedBy,
+		CheckedBy:      auth.GetOperator(c),
 	}
 
-	if err := global.DB.Create(&record).Error; err != nil {
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, "创建盘点记录失败")
+		return
+	}
+
+	// 按套件盘点时同时登记下级组件
+	if req.IncludeComponents {
+		if _, err := createKitComponentRecords(tx, scope, &record); err != nil {
+			tx.Rollback()
+			utils.InternalError(c, "创建组件盘点记录失败")
+			return
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
 		utils.InternalError(c, "创建盘点记录失败")
 		return
 	}
@@ -413,6 +490,7 @@ func CreateInventoryRecord(c *gin.Context) {
 		Preload("Asset.Category").
 		Preload("Asset.Department").
 		Preload("Task").
+		Preload("Components.Asset").
 		First(&record, record.ID).Error; err != nil {
 		utils.InternalError(c, "获取盘点记录详情失败")
 		return
@@ -440,6 +518,14 @@ func BatchCreateInventoryRecords(c *gin.Context) {
 		return
 	}
 
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	operator := auth.GetOperator(c)
+
 	// 开始事务
 	tx := global.DB.Begin()
 
@@ -447,7 +533,7 @@ func BatchCreateInventoryRecords(c *gin.Context) {
 	for _, recordReq := range req.Records {
 		// 验证任务是否存在且状态正确
 		var task models.InventoryTask
-		if err := tx.First(&task, recordReq.TaskID).Error; err != nil {
+		if err := scope.Apply(tx).First(&task, recordReq.TaskID).Error; err != nil {
 			tx.Rollback()
 			if err == gorm.ErrRecordNotFound {
 				utils.NotFound(c, fmt.Sprintf("盘点任务 %d 不存在", recordReq.TaskID))
@@ -465,7 +551,7 @@ func BatchCreateInventoryRecords(c *gin.Context) {
 
 		// 验证资产是否存在
 		var asset models.Asset
-		if err := tx.First(&asset, recordReq.AssetID).Error; err != nil {
+		if err := scope.Apply(tx).First(&asset, recordReq.AssetID).Error; err != nil {
 			tx.Rollback()
 			if err == gorm.ErrRecordNotFound {
 				utils.NotFound(c, fmt.Sprintf("资产 %d 不存在", recordReq.AssetID))
@@ -493,7 +579,7 @@ func BatchCreateInventoryRecords(c *gin.Context) {
 			Result:         recordReq.Result,
 			Notes:          recordReq.Notes,
 			CheckedAt:      &now,
-			CheckedBy:      recordReq.CheckedBy,
+			CheckedBy:      operator,
 		}
 
 		if err := tx.Create(&record).Error; err != nil {
@@ -502,6 +588,17 @@ func BatchCreateInventoryRecords(c *gin.Context) {
 			return
 		}
 
+		// 按套件盘点时同时登记下级组件
+		if recordReq.IncludeComponents {
+			components, err := createKitComponentRecords(tx, scope, &record)
+			if err != nil {
+				tx.Rollback()
+				utils.InternalError(c, "创建组件盘点记录失败")
+				return
+			}
+			record.Components = components
+		}
+
 		createdRecords = append(createdRecords, record)
 	}
 
@@ -522,9 +619,14 @@ func GetInventoryReport(c *gin.Context) {
 		return
 	}
 
-	// 获取盘点任务
+	// 获取盘点任务（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
 	var task models.InventoryTask
-	if err := global.DB.Preload("Records").
+	if err := scope.DB().Preload("Records").
 		Preload("Records.Asset").
 		Preload("Records.Asset.Category").
 		Preload("Records.Asset.Department").
@@ -545,7 +647,7 @@ func GetInventoryReport(c *gin.Context) {
 	}
 
 	// 生成分类统计
-	categoryStats, err := generateCategoryStats(uint(id))
+	categoryStats, err := generateCategoryStats(uint(id), scope)
 	if err != nil {
 		utils.InternalError(c, "生成分类统计失败")
 		return
@@ -553,7 +655,7 @@ func GetInventoryReport(c *gin.Context) {
 	report.CategoryStats = categoryStats
 
 	// 生成部门统计
-	departmentStats, err := generateDepartmentStats(uint(id))
+	departmentStats, err := generateDepartmentStats(uint(id), scope)
 	if err != nil {
 		utils.InternalError(c, "生成部门统计失败")
 		return
@@ -609,45 +711,16 @@ func buildInventoryTaskResponse(task *models.InventoryTask) InventoryTaskRespons
 
 // calculateTotalAssets 计算盘点任务的总资产数
 func calculateTotalAssets(task *models.InventoryTask) int64 {
-	db := global.DB.Model(&models.Asset{})
-
-	// 解析范围过滤条件
-	var scopeFilter models.InventoryScopeFilter
-	if len(task.ScopeFilter) > 0 {
-		json.Unmarshal(task.ScopeFilter, &scopeFilter)
-	}
-
-	// 根据任务类型和范围过滤条件构建查询
-	switch task.TaskType {
-	case models.InventoryTaskTypeCategory:
-		if len(scopeFilter.CategoryIDs) > 0 {
-			db = db.Where("category_id IN ?", scopeFilter.CategoryIDs)
-		}
-	case models.InventoryTaskTypeDepartment:
-		if len(scopeFilter.DepartmentIDs) > 0 {
-			db = db.Where("department_id IN ?", scopeFilter.DepartmentIDs)
-		}
-	}
-
-	// 状态过滤
-	if len(scopeFilter.AssetStatuses) > 0 {
-		db = db.Where("status IN ?", scopeFilter.AssetStatuses)
-	}
-
-	// 位置过滤
-	if scopeFilter.LocationFilter != "" {
-		db = db.Where("location LIKE ?", "%"+scopeFilter.LocationFilter+"%")
-	}
-
 	var count int64
-	db.Count(&count)
+	task.ScopeAssets(global.DB.Model(&models.Asset{})).Count(&count)
 	return count
 }
 
 
 // generateCategoryStats 生成分类盘点统计
-func generateCategoryStats(taskID uint) ([]CategoryInventoryStats, error) {
+func generateCategoryStats(taskID uint, scope *auth.DataScope) ([]CategoryInventoryStats, error) {
 	var stats []CategoryInventoryStats
+	assetCondition, assetArgs := scope.Condition("assets", "a")
 
 	query := `
 		SELECT 
@@ -662,12 +735,13 @@ func generateCategoryStats(taskID uint) ([]CategoryInventoryStats, error) {
 		FROM categories c
 		LEFT JOIN assets a ON c.id = a.category_id
 		LEFT JOIN inventory_records ir ON a.id = ir.asset_id AND ir.task_id = ?
-		WHERE a.id IS NOT NULL
+		WHERE a.id IS NOT NULL AND ` + assetCondition + `
 		GROUP BY c.id, c.name
 		ORDER BY c.name
 	`
 
-	if err := global.DB.Raw(query, taskID).Scan(&stats).Error; err != nil {
+	args := append([]interface{}{taskID}, assetArgs...)
+	if err := global.DB.Raw(query, args...).Scan(&stats).Error; err != nil {
 		return nil, err
 	}
 
@@ -675,8 +749,9 @@ func generateCategoryStats(taskID uint) ([]CategoryInventoryStats, error) {
 }
 
 // generateDepartmentStats 生成部门盘点统计
-func generateDepartmentStats(taskID uint) ([]DepartmentInventoryStats, error) {
+func generateDepartmentStats(taskID uint, scope *auth.DataScope) ([]DepartmentInventoryStats, error) {
 	var stats []DepartmentInventoryStats
+	assetCondition, assetArgs := scope.Condition("assets", "a")
 
 	query := `
 		SELECT 
@@ -691,12 +766,13 @@ func generateDepartmentStats(taskID uint) ([]DepartmentInventoryStats, error) {
 		FROM departments d
 		LEFT JOIN assets a ON d.id = a.department_id
 		LEFT JOIN inventory_records ir ON a.id = ir.asset_id AND ir.task_id = ?
-		WHERE a.id IS NOT NULL
+		WHERE a.id IS NOT NULL AND ` + assetCondition + `
 		GROUP BY d.id, d.name
 		ORDER BY d.name
 	`
 
-	if err := global.DB.Raw(query, taskID).Scan(&stats).Error; err != nil {
+	args := append([]interface{}{taskID}, assetArgs...)
+	if err := global.DB.Raw(query, args...).Scan(&stats).Error; err != nil {
 		return nil, err
 	}
 
@@ -8,13 +8,13 @@ import (
 
 // CreateInventoryTaskRequest 创建盘点任务请求
 type CreateInventoryTaskRequest struct {
-	TaskName    string                      `json:"task_name" validate:"required,max=200"`
-	TaskType    models.InventoryTaskType    `json:"task_type" validate:"required,oneof=full category department"`
-	ScopeFilter models.InventoryScopeFilter `json:"scope_filter"`
-	StartDate   *time.Time                  `json:"start_date"`
-	EndDate     *time.Time                  `json:"end_date"`
-	CreatedBy   string                      `json:"created_by" validate:"max=100"`
-	Notes       string                      `json:"notes"`
+	TaskName     string                      `json:"task_name" validate:"required,max=200"`
+	TaskType     models.InventoryTaskType    `json:"task_type" validate:"required,oneof=full category department"`
+	ScopeFilter  models.InventoryScopeFilter `json:"scope_filter"`
+	StartDate    *time.Time                  `json:"start_date"`
+	EndDate      *time.Time                  `json:"end_date"`
+	DepartmentID *uint                       `json:"department_id"`
+	Notes        string                      `json:"notes"`
 }
 
 // UpdateInventoryTaskRequest 更新盘点任务请求
@@ -28,12 +28,12 @@ type UpdateInventoryTaskRequest struct {
 
 // CreateInventoryRecordRequest 创建盘点记录请求
 type CreateInventoryRecordRequest struct {
-	TaskID       uint                   `json:"task_id" validate:"required"`
-	AssetID      uint                   `json:"asset_id" validate:"required"`
-	ActualStatus models.AssetStatus     `json:"actual_status" validate:"required"`
-	Result       models.InventoryResult `json:"result" validate:"required,oneof=normal surplus deficit damaged"`
-	Notes        string                 `json:"notes"`
-	CheckedBy    string                 `json:"checked_by" validate:"max=100"`
+	TaskID            uint                   `json:"task_id" validate:"required"`
+	AssetID           uint                   `json:"asset_id" validate:"required"`
+	ActualStatus      models.AssetStatus     `json:"actual_status" validate:"required"`
+	Result            models.InventoryResult `json:"result" validate:"required,oneof=normal surplus deficit damaged"`
+	Notes             string                 `json:"notes"`
+	IncludeComponents bool                   `json:"include_components"` // 按套件盘点，下级组件按同一结果登记
 }
 
 // BatchCreateInventoryRecordsRequest 批量创建盘点记录请求
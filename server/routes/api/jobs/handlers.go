@@ -0,0 +1,201 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/jobs"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetJobs 获取批量任务列表，管理员可查看全部任务，其他用户只能查看自己提交的任务
+func GetJobs(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters JobFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"id": true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "type", "status", "total", "created_at", "finished_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	query := visibleJobs(c)
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取任务列表
+	var list []models.BulkJob
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&list).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	responses := make([]JobResponse, len(list))
+	for i := range list {
+		responses[i] = JobResponse{BulkJob: list[i], Progress: list[i].Progress()}
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, responses)
+	utils.Success(c, response)
+}
+
+// GetJob 获取批量任务进度
+func GetJob(c *gin.Context) {
+	job, ok := findJob(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, JobResponse{BulkJob: *job, Progress: job.Progress()})
+}
+
+// GetJobErrors 分页获取批量任务中每个资产的失败原因
+func GetJobErrors(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	job, ok := findJob(c)
+	if !ok {
+		return
+	}
+
+	query := global.DB.Model(&models.BulkJobError{}).Where("job_id = ?", job.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var errs []models.BulkJobError
+	if err := query.Order("id ASC").Offset(req.GetOffset()).Limit(req.PageSize).Find(&errs).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, utils.NewPaginationResponse(req.Page, req.PageSize, total, errs))
+}
+
+// CancelJob 取消批量任务，执行中的任务在当前批次完成后停止，已处理的资产不回滚
+func CancelJob(c *gin.Context) {
+	job, ok := findJob(c)
+	if !ok {
+		return
+	}
+
+	if job.IsFinished() {
+		utils.Error(c, utils.JOB_FINISHED, gin.H{"status": job.Status})
+		return
+	}
+
+	if err := jobs.Cancel(global.DB, job); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.First(job, job.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, JobResponse{BulkJob: *job, Progress: job.Progress()})
+}
+
+// findJob 按路径参数查找当前用户可见的任务，失败时已写入响应
+func findJob(c *gin.Context) (*models.BulkJob, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的任务ID")
+		return nil, false
+	}
+
+	var job models.BulkJob
+	if err := visibleJobs(c).First(&job, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.JOB_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &job, true
+}
+
+// visibleJobs 当前用户可见的任务：管理员可见全部，其他用户只能看到自己提交的任务
+func visibleJobs(c *gin.Context) *gorm.DB {
+	query := global.DB.Model(&models.BulkJob{})
+	identity := auth.GetIdentity(c)
+	if identity == nil {
+		return query.Where("1 = 0")
+	}
+	if identity.HasRole(auth.RoleAdmin) {
+		return query
+	}
+	return query.Where("created_by_id = ?", identity.UserID)
+}
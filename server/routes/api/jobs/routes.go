@@ -0,0 +1,16 @@
+package jobs
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册后台批量任务路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	jobs := r.Group("/jobs")
+	{
+		jobs.GET("", GetJobs)                 // 获取批量任务列表
+		jobs.GET("/:id", GetJob)              // 获取批量任务进度
+		jobs.GET("/:id/errors", GetJobErrors) // 获取批量任务失败明细
+		jobs.PUT("/:id/cancel", CancelJob)    // 取消批量任务
+	}
+}
@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"asset-management-system/server/models"
+)
+
+// JobResponse 批量任务响应
+type JobResponse struct {
+	models.BulkJob
+	Progress float64 `json:"progress"` // 完成百分比
+}
+
+// JobFilters 批量任务筛选条件
+type JobFilters struct {
+	Type   *models.BulkJobType   `json:"type" form:"type"`
+	Status *models.BulkJobStatus `json:"status" form:"status"`
+}
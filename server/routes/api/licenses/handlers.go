@@ -0,0 +1,640 @@
+package licenses
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetLicenses 获取许可证列表
+func GetLicenses(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters LicenseFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if filters.ExpiringWithin != nil && *filters.ExpiringWithin < 0 {
+		utils.ValidationError(c, "到期天数不能为负数")
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"id": true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "product", "vendor", "type", "seat_count", "purchase_date", "expiry_date", "cost", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 许可证为公共资源，不按数据范围过滤
+	query := applyLicenseFilters(global.DB.Model(&models.License{}), filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取许可证列表
+	var list []models.License
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&list).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	responses, err := buildLicenseResponses(list, canViewLicenseKey(c))
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, responses)
+	utils.Success(c, response)
+}
+
+// GetLicense 获取许可证详情及在用席位
+func GetLicense(c *gin.Context) {
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+
+	respondLicense(c, license.ID)
+}
+
+// CreateLicense 创建许可证
+func CreateLicense(c *gin.Context) {
+	var req CreateLicenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if req.PurchaseDate != nil && req.ExpiryDate != nil && req.ExpiryDate.Before(*req.PurchaseDate) {
+		utils.ValidationError(c, "到期日期不能早于购买日期")
+		return
+	}
+
+	license := models.License{
+		Product:      req.Product,
+		Vendor:       req.Vendor,
+		Type:         req.Type,
+		LicenseKey:   req.LicenseKey,
+		SeatCount:    req.SeatCount,
+		PurchaseDate: req.PurchaseDate,
+		ExpiryDate:   req.ExpiryDate,
+		Cost:         req.Cost,
+		Notes:        req.Notes,
+		CreatedBy:    auth.GetOperator(c),
+	}
+	if license.Type == "" {
+		license.Type = models.LicenseTypeDesktop
+	}
+	if err := global.DB.Create(&license).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	respondLicense(c, license.ID)
+}
+
+// UpdateLicense 更新许可证，席位数不能少于已分配的席位数
+func UpdateLicense(c *gin.Context) {
+	var req UpdateLicenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+
+	purchaseDate, expiryDate := license.PurchaseDate, license.ExpiryDate
+	if req.PurchaseDate != nil {
+		purchaseDate = req.PurchaseDate
+	}
+	if req.ExpiryDate != nil {
+		expiryDate = req.ExpiryDate
+	}
+	if purchaseDate != nil && expiryDate != nil && expiryDate.Before(*purchaseDate) {
+		utils.ValidationError(c, "到期日期不能早于购买日期")
+		return
+	}
+
+	// 构建更新数据
+	updates := make(map[string]interface{})
+	if req.Product != nil {
+		updates["product"] = *req.Product
+	}
+	if req.Vendor != nil {
+		updates["vendor"] = *req.Vendor
+	}
+	if req.Type != nil {
+		updates["type"] = *req.Type
+	}
+	if req.LicenseKey != nil {
+		updates["license_key"] = *req.LicenseKey
+	}
+	if req.SeatCount != nil {
+		updates["seat_count"] = *req.SeatCount
+	}
+	if req.PurchaseDate != nil {
+		updates["purchase_date"] = *req.PurchaseDate
+	}
+	if req.ExpiryDate != nil {
+		updates["expiry_date"] = *req.ExpiryDate
+	}
+	if req.Cost != nil {
+		updates["cost"] = *req.Cost
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	if len(updates) > 0 {
+		// 开始事务
+		tx := global.DB.Begin()
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+			}
+		}()
+
+		// 减少席位数时检查是否超额分配
+		if req.SeatCount != nil {
+			used, err := models.CountActiveSeats(tx, license.ID)
+			if err != nil {
+				tx.Rollback()
+				utils.InternalError(c, err)
+				return
+			}
+			if int64(*req.SeatCount) < used {
+				tx.Rollback()
+				utils.Error(c, utils.LICENSE_SEAT_COUNT_TOO_LOW, gin.H{"used_seats": used})
+				return
+			}
+		}
+
+		if err := tx.Model(license).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			utils.InternalError(c, err)
+			return
+		}
+
+		// 提交事务
+		if err := tx.Commit().Error; err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	respondLicense(c, license.ID)
+}
+
+// DeleteLicense 删除许可证，仍有已分配的席位时须先回收
+func DeleteLicense(c *gin.Context) {
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+
+	used, err := models.CountActiveSeats(global.DB, license.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if used > 0 {
+		utils.Error(c, utils.LICENSE_HAS_SEATS, gin.H{"used_seats": used})
+		return
+	}
+
+	if err := global.DB.Delete(license).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "许可证删除成功"})
+}
+
+// GetLicenseSeats 获取许可证的席位分配记录，含已回收的记录
+func GetLicenseSeats(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters SeatFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+
+	query := global.DB.Model(&models.LicenseSeat{}).Where("license_id = ?", license.ID)
+	if filters.Active != nil {
+		if *filters.Active {
+			query = query.Where("revoked_at IS NULL")
+		} else {
+			query = query.Where("revoked_at IS NOT NULL")
+		}
+	}
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取分配记录
+	var seats []models.LicenseSeat
+	if err := query.
+		Preload("Asset").
+		Order("assigned_at DESC, id DESC").
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&seats).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, seats)
+	utils.Success(c, response)
+}
+
+// AssignSeat 将许可证席位分配给人员或资产，席位已满或已过期时拒绝
+func AssignSeat(c *gin.Context) {
+	var req AssignSeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	assigneeName := strings.TrimSpace(req.AssigneeName)
+	if req.AssetID == nil && assigneeName == "" {
+		utils.ValidationError(c, "使用人和资产至少填写一项")
+		return
+	}
+
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+	if license.IsExpired(time.Now()) {
+		utils.Error(c, utils.LICENSE_EXPIRED, gin.H{"expiry_date": license.ExpiryDate})
+		return
+	}
+
+	// 只能分配给数据范围内的资产
+	if req.AssetID != nil {
+		scope, err := auth.GetDataScope(c)
+		if err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		var asset models.Asset
+		if err := scope.DB().First(&asset, *req.AssetID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	seat := models.LicenseSeat{
+		AssigneeName:    assigneeName,
+		AssigneeContact: req.AssigneeContact,
+		AssetID:         req.AssetID,
+		AssignedBy:      auth.GetOperator(c),
+		Notes:           req.Notes,
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := models.AssignLicenseSeat(tx, license, &seat); err != nil {
+		tx.Rollback()
+		switch err {
+		case models.ErrNoSeatsAvailable:
+			utils.Error(c, utils.LICENSE_NO_SEATS_AVAILABLE, gin.H{"seat_count": license.SeatCount})
+		case models.ErrSeatAlreadyAssigned:
+			utils.Error(c, utils.LICENSE_SEAT_ASSIGNED, nil)
+		default:
+			utils.InternalError(c, err)
+		}
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Preload("Asset").First(&seat, seat.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	middleware.SetAuditTarget(c, "license_seats", seat.ID, models.OperationTypeCreate)
+	utils.Success(c, seat)
+}
+
+// RevokeSeat 回收席位，分配记录保留
+func RevokeSeat(c *gin.Context) {
+	var req RevokeSeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	license, ok := findLicense(c)
+	if !ok {
+		return
+	}
+
+	seatID, err := strconv.ParseUint(c.Param("seat_id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的席位ID")
+		return
+	}
+
+	var seat models.LicenseSeat
+	if err := global.DB.Where("license_id = ?", license.ID).First(&seat, seatID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.LICENSE_SEAT_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+	if !seat.IsActive() {
+		utils.Error(c, utils.LICENSE_SEAT_REVOKED, gin.H{"revoked_at": seat.RevokedAt})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"revoked_at": time.Now(),
+		"revoked_by": auth.GetOperator(c),
+	}
+	if req.Notes != "" {
+		updates["notes"] = req.Notes
+	}
+
+	// 以条件更新回收，避免重复回收
+	result := global.DB.Model(&seat).Where("revoked_at IS NULL").Updates(updates)
+	if result.Error != nil {
+		utils.InternalError(c, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.Error(c, utils.LICENSE_SEAT_REVOKED, nil)
+		return
+	}
+
+	if err := global.DB.Preload("Asset").First(&seat, seat.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	middleware.SetAuditTarget(c, "license_seats", seat.ID, models.OperationTypeUpdate)
+	utils.Success(c, seat)
+}
+
+// findLicense 按路径参数查找许可证，失败时已写入响应
+func findLicense(c *gin.Context) (*models.License, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的许可证ID")
+		return nil, false
+	}
+
+	var license models.License
+	if err := global.DB.First(&license, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.LICENSE_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &license, true
+}
+
+// respondLicense 加载许可证及在用席位并返回
+func respondLicense(c *gin.Context, id uint) {
+	var license models.License
+	if err := global.DB.
+		Preload("Seats", func(db *gorm.DB) *gorm.DB {
+			return db.Where("revoked_at IS NULL").Order("assigned_at ASC")
+		}).
+		Preload("Seats.Asset").
+		First(&license, id).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	responses, err := buildLicenseResponses([]models.License{license}, canViewLicenseKey(c))
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, responses[0])
+}
+
+// buildLicenseResponses 批量统计已分配席位并构建响应，showKey 为 false 时许可证密钥脱敏
+func buildLicenseResponses(list []models.License, showKey bool) ([]LicenseResponse, error) {
+	ids := make([]uint, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+
+	var counts []struct {
+		LicenseID uint
+		Count     int64
+	}
+	if len(ids) > 0 {
+		if err := models.ActiveLicenseSeats(global.DB).
+			Select("license_id, COUNT(*) as count").
+			Where("license_id IN ?", ids).
+			Group("license_id").
+			Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+	}
+	used := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		used[count.LicenseID] = count.Count
+	}
+
+	now := time.Now()
+	responses := make([]LicenseResponse, len(list))
+	for i := range list {
+		license := &list[i]
+		response := LicenseResponse{
+			License:      *license,
+			UsedSeats:    used[license.ID],
+			DaysToExpiry: license.DaysToExpiry(now),
+			Expired:      license.IsExpired(now),
+		}
+		response.AvailableSeats = int64(license.SeatCount) - response.UsedSeats
+		if response.AvailableSeats < 0 {
+			response.AvailableSeats = 0
+		}
+		if license.SeatCount > 0 {
+			response.Utilization = float64(response.UsedSeats) * 100 / float64(license.SeatCount)
+		}
+		if !showKey {
+			response.LicenseKey = maskLicenseKey(license.LicenseKey)
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+// canViewLicenseKey 有许可证更新权限时才返回完整密钥，只读角色和API密钥按权限判断
+func canViewLicenseKey(c *gin.Context) bool {
+	identity := auth.GetIdentity(c)
+	if identity == nil {
+		return false
+	}
+	if identity.AuthType == auth.AuthTypeAPIKey {
+		return auth.HasScope(identity.Scopes, "licenses", auth.ActionUpdate)
+	}
+	return auth.HasPermission(identity.Roles, "licenses", auth.ActionUpdate)
+}
+
+// maskLicenseKey 许可证密钥脱敏，只保留末4位
+func maskLicenseKey(key string) string {
+	runes := []rune(key)
+	if len(runes) == 0 {
+		return ""
+	}
+	if len(runes) <= 8 {
+		return "********"
+	}
+	return "********" + string(runes[len(runes)-4:])
+}
+
+// applyLicenseFilters 应用许可证筛选条件
+func applyLicenseFilters(query *gorm.DB, filters LicenseFilters) *gorm.DB {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if filters.Keyword != nil && *filters.Keyword != "" {
+		keyword := "%" + *filters.Keyword + "%"
+		query = query.Where("(product LIKE ? OR vendor LIKE ? OR notes LIKE ?)", keyword, keyword, keyword)
+	}
+	if filters.Vendor != nil && *filters.Vendor != "" {
+		query = query.Where("vendor LIKE ?", "%"+*filters.Vendor+"%")
+	}
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.ExpiringWithin != nil {
+		query = query.Where("id IN (?)", models.ExpiringLicenses(global.DB, now, *filters.ExpiringWithin).Select("id"))
+	}
+	if filters.Expired != nil {
+		if *filters.Expired {
+			query = query.Where("expiry_date IS NOT NULL AND expiry_date < ?", today)
+		} else {
+			query = query.Where("(expiry_date IS NULL OR expiry_date >= ?)", today)
+		}
+	}
+	if filters.AssetID != nil {
+		query = query.Where("id IN (?)", models.ActiveLicenseSeats(global.DB).Select("license_id").Where("asset_id = ?", *filters.AssetID))
+	}
+	if filters.AssigneeName != nil && *filters.AssigneeName != "" {
+		query = query.Where("id IN (?)", models.ActiveLicenseSeats(global.DB).Select("license_id").Where("assignee_name LIKE ?", "%"+*filters.AssigneeName+"%"))
+	}
+
+	return query
+}
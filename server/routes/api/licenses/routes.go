@@ -0,0 +1,20 @@
+package licenses
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册软件许可证路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	licenses := r.Group("/licenses")
+	{
+		licenses.GET("", GetLicenses)                          // 获取许可证列表
+		licenses.POST("", CreateLicense)                       // 创建许可证
+		licenses.GET("/:id", GetLicense)                       // 获取许可证详情及在用席位
+		licenses.PUT("/:id", UpdateLicense)                    // 更新许可证（含续费）
+		licenses.DELETE("/:id", DeleteLicense)                 // 删除许可证
+		licenses.GET("/:id/seats", GetLicenseSeats)            // 获取席位分配记录
+		licenses.POST("/:id/seats", AssignSeat)                // 分配席位
+		licenses.PUT("/:id/seats/:seat_id/revoke", RevokeSeat) // 回收席位
+	}
+}
@@ -0,0 +1,72 @@
+package licenses
+
+import (
+	"time"
+
+	"asset-management-system/server/models"
+)
+
+// CreateLicenseRequest 创建许可证请求
+type CreateLicenseRequest struct {
+	Product      string             `json:"product" validate:"required,max=200"`
+	Vendor       string             `json:"vendor" validate:"max=200"`
+	Type         models.LicenseType `json:"type" validate:"omitempty,oneof=saas desktop"`
+	LicenseKey   string             `json:"license_key" validate:"max=500"`
+	SeatCount    int                `json:"seat_count" validate:"required,min=1"`
+	PurchaseDate *time.Time         `json:"purchase_date"`
+	ExpiryDate   *time.Time         `json:"expiry_date"`
+	Cost         *float64           `json:"cost" validate:"omitempty,min=0"`
+	Notes        string             `json:"notes"`
+}
+
+// UpdateLicenseRequest 更新许可证请求，续费时更新到期日期和费用
+type UpdateLicenseRequest struct {
+	Product      *string             `json:"product" validate:"omitempty,min=1,max=200"`
+	Vendor       *string             `json:"vendor" validate:"omitempty,max=200"`
+	Type         *models.LicenseType `json:"type" validate:"omitempty,oneof=saas desktop"`
+	LicenseKey   *string             `json:"license_key" validate:"omitempty,max=500"`
+	SeatCount    *int                `json:"seat_count" validate:"omitempty,min=1"`
+	PurchaseDate *time.Time          `json:"purchase_date"`
+	ExpiryDate   *time.Time          `json:"expiry_date"`
+	Cost         *float64            `json:"cost" validate:"omitempty,min=0"`
+	Notes        *string             `json:"notes"`
+}
+
+// AssignSeatRequest 分配席位请求，使用人和资产至少填写一项
+type AssignSeatRequest struct {
+	AssigneeName    string `json:"assignee_name" validate:"max=100"`
+	AssigneeContact string `json:"assignee_contact" validate:"max=100"`
+	AssetID         *uint  `json:"asset_id"`
+	Notes           string `json:"notes"`
+}
+
+// RevokeSeatRequest 回收席位请求
+type RevokeSeatRequest struct {
+	Notes string `json:"notes"`
+}
+
+// LicenseResponse 许可证响应，附带席位使用情况和到期状态
+type LicenseResponse struct {
+	models.License
+	UsedSeats      int64   `json:"used_seats"`
+	AvailableSeats int64   `json:"available_seats"`
+	Utilization    float64 `json:"utilization"`    // 席位使用率（%）
+	DaysToExpiry   *int    `json:"days_to_expiry"` // 距到期天数，永久授权为空
+	Expired        bool    `json:"expired"`
+}
+
+// LicenseFilters 许可证筛选条件
+type LicenseFilters struct {
+	Keyword        *string             `json:"keyword" form:"keyword"`
+	Vendor         *string             `json:"vendor" form:"vendor"`
+	Type           *models.LicenseType `json:"type" form:"type"`
+	ExpiringWithin *int                `json:"expiring_within" form:"expiring_within"` // 在指定天数内到期
+	Expired        *bool               `json:"expired" form:"expired"`
+	AssetID        *uint               `json:"asset_id" form:"asset_id"` // 分配给该资产
+	AssigneeName   *string             `json:"assignee_name" form:"assignee_name"`
+}
+
+// SeatFilters 席位分配记录筛选条件
+type SeatFilters struct {
+	Active *bool `json:"active" form:"active"`
+}
@@ -227,11 +227,22 @@ func applyOperationLogFilters(query *gorm.DB, filters OperationLogFilters) *gorm
 // getTableLabel 获取表名标签
 func getTableLabel(tableName string) string {
 	labels := map[string]string{
-		"assets":          "资产",
-		"categories":      "分类",
-		"departments":     "部门",
-		"borrow_records":  "借用记录",
-		"inventory_tasks": "盘点任务",
+		"assets":                  "资产",
+		"attachments":             "附件",
+		"categories":              "分类",
+		"departments":             "部门",
+		"borrow_records":          "借用记录",
+		"asset_transfers":         "调拨单",
+		"asset_reservations":      "资产预约",
+		"bulk_jobs":               "批量任务",
+		"maintenance_orders":      "维修工单",
+		"asset_disposals":         "处置申请",
+		"consumables":             "耗材",
+		"consumable_transactions": "耗材出入库",
+		"licenses":                "软件许可证",
+		"license_seats":           "许可证席位",
+		"inventory_tasks":         "盘点任务",
+		"system_configs":          "系统配置",
 	}
 	if label, ok := labels[tableName]; ok {
 		return label
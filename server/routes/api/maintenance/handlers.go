@@ -0,0 +1,401 @@
+package maintenance
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetMaintenanceOrders 获取维修工单列表
+func GetMaintenanceOrders(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters MaintenanceFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"start_date": true,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "asset_id", "vendor", "cost", "start_date", "finish_date", "status", "result", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.MaintenanceOrder{}).Preload("Asset")
+
+	// 应用筛选条件
+	query = applyMaintenanceFilters(query, filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取维修工单列表
+	var orders []models.MaintenanceOrder
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&orders).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, orders)
+	utils.Success(c, response)
+}
+
+// GetMaintenanceOrder 获取维修工单详情
+func GetMaintenanceOrder(c *gin.Context) {
+	order, ok := findMaintenanceOrder(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, order)
+}
+
+// CreateMaintenanceOrder 创建维修工单，资产随之进入维护中
+func CreateMaintenanceOrder(c *gin.Context) {
+	var req CreateMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 只能为数据范围内的资产开单
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	var asset models.Asset
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 同一资产同时只能有一张未关闭的维修工单
+	inProgress, err := models.HasOpenMaintenance(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if inProgress {
+		utils.Error(c, utils.MAINTENANCE_IN_PROGRESS, nil)
+		return
+	}
+
+	// 转换附件列表为JSON
+	var attachmentsJSON []byte
+	if req.Attachments != nil {
+		attachmentsJSON, err = json.Marshal(req.Attachments)
+		if err != nil {
+			utils.ValidationError(c, "附件格式错误")
+			return
+		}
+	}
+
+	order := models.MaintenanceOrder{
+		AssetID:          asset.ID,
+		FaultDescription: req.FaultDescription,
+		Vendor:           req.Vendor,
+		Cost:             req.Cost,
+		Attachments:      attachmentsJSON,
+		Status:           models.MaintenanceStatusOpen,
+		CreatedBy:        auth.GetOperator(c),
+	}
+	if req.StartDate != nil {
+		order.StartDate = *req.StartDate
+	}
+
+	// 创建工单时由钩子在同一事务中将资产置为维护中
+	if err := global.DB.Create(&order).Error; err != nil {
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	if err := global.DB.Preload("Asset").First(&order, order.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, order)
+}
+
+// UpdateMaintenanceOrder 更新维修工单
+func UpdateMaintenanceOrder(c *gin.Context) {
+	var req UpdateMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	order, ok := findMaintenanceOrder(c)
+	if !ok {
+		return
+	}
+
+	// 构建更新数据
+	updates := make(map[string]interface{})
+	if req.FaultDescription != nil {
+		updates["fault_description"] = *req.FaultDescription
+	}
+	if req.Vendor != nil {
+		updates["vendor"] = *req.Vendor
+	}
+	if req.Cost != nil {
+		updates["cost"] = *req.Cost
+	}
+	if req.StartDate != nil {
+		updates["start_date"] = *req.StartDate
+	}
+	if req.Result != nil {
+		updates["result"] = *req.Result
+	}
+	if req.ResultNotes != nil {
+		updates["result_notes"] = *req.ResultNotes
+	}
+	if req.Attachments != nil {
+		attachmentsJSON, err := json.Marshal(req.Attachments)
+		if err != nil {
+			utils.ValidationError(c, "附件格式错误")
+			return
+		}
+		updates["attachments"] = attachmentsJSON
+	}
+
+	// 执行更新
+	if len(updates) > 0 {
+		if err := global.DB.Model(order).Updates(updates).Error; err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	if err := global.DB.Preload("Asset").First(order, order.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, order)
+}
+
+// CloseMaintenanceOrder 关闭维修工单，维护中的资产恢复为可用，无法修复的资产保持维护中等待处置
+func CloseMaintenanceOrder(c *gin.Context) {
+	var req CloseMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	order, ok := findMaintenanceOrder(c)
+	if !ok {
+		return
+	}
+
+	if !order.IsOpen() {
+		utils.Error(c, utils.MAINTENANCE_CLOSED, nil)
+		return
+	}
+	if req.FinishDate != nil && req.FinishDate.Before(order.StartDate) {
+		utils.ValidationError(c, "完成日期不能早于送修日期")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"result":       req.Result,
+		"result_notes": req.ResultNotes,
+		"closed_by":    auth.GetOperator(c),
+	}
+	if req.Cost != nil {
+		updates["cost"] = *req.Cost
+	}
+	if req.FinishDate != nil {
+		updates["finish_date"] = *req.FinishDate
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := order.Close(tx, updates); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Preload("Asset").First(order, order.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, order)
+}
+
+// DeleteMaintenanceOrder 删除维修工单，删除未关闭的工单时资产恢复为可用
+func DeleteMaintenanceOrder(c *gin.Context) {
+	order, ok := findMaintenanceOrder(c)
+	if !ok {
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Delete(order).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	if order.IsOpen() && order.Asset.Status == models.AssetStatusMaintenance {
+		if err := models.ChangeAssetStatus(tx, order.AssetID, models.AssetStatusAvailable); err != nil {
+			tx.Rollback()
+			utils.StatusTransitionError(c, err)
+			return
+		}
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "维修工单删除成功"})
+}
+
+// findMaintenanceOrder 按路径参数查找数据范围内的维修工单，失败时已写入响应
+func findMaintenanceOrder(c *gin.Context) (*models.MaintenanceOrder, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的维修工单ID")
+		return nil, false
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	var order models.MaintenanceOrder
+	if err := scope.DB().Preload("Asset").First(&order, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.MAINTENANCE_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &order, true
+}
+
+// applyMaintenanceFilters 应用维修工单筛选条件
+func applyMaintenanceFilters(query *gorm.DB, filters MaintenanceFilters) *gorm.DB {
+	if filters.AssetID != nil {
+		query = query.Where("asset_id = ?", *filters.AssetID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.Result != nil {
+		query = query.Where("result = ?", *filters.Result)
+	}
+	if filters.Vendor != nil && *filters.Vendor != "" {
+		query = query.Where("vendor LIKE ?", "%"+*filters.Vendor+"%")
+	}
+	if filters.StartDateFrom != nil {
+		query = query.Where("start_date >= ?", *filters.StartDateFrom)
+	}
+	if filters.StartDateTo != nil {
+		query = query.Where("start_date <= ?", *filters.StartDateTo)
+	}
+
+	return query
+}
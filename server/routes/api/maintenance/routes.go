@@ -0,0 +1,18 @@
+package maintenance
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册维修工单路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	maintenance := r.Group("/maintenance")
+	{
+		maintenance.GET("", GetMaintenanceOrders)            // 获取维修工单列表
+		maintenance.POST("", CreateMaintenanceOrder)         // 创建维修工单（送修）
+		maintenance.GET("/:id", GetMaintenanceOrder)         // 获取维修工单详情
+		maintenance.PUT("/:id", UpdateMaintenanceOrder)      // 更新维修工单
+		maintenance.PUT("/:id/close", CloseMaintenanceOrder) // 关闭维修工单
+		maintenance.DELETE("/:id", DeleteMaintenanceOrder)   // 删除维修工单
+	}
+}
@@ -0,0 +1,45 @@
+package maintenance
+
+import (
+	"asset-management-system/server/models"
+	"time"
+)
+
+// CreateMaintenanceRequest 创建维修工单请求
+type CreateMaintenanceRequest struct {
+	AssetID          uint       `json:"asset_id" validate:"required"`
+	FaultDescription string     `json:"fault_description" validate:"required"`
+	Vendor           string     `json:"vendor" validate:"max=200"`
+	Cost             *float64   `json:"cost" validate:"omitempty,min=0"`
+	StartDate        *time.Time `json:"start_date"`
+	Attachments      []string   `json:"attachments" validate:"max=20,dive,max=500"`
+}
+
+// UpdateMaintenanceRequest 更新维修工单请求
+type UpdateMaintenanceRequest struct {
+	FaultDescription *string                   `json:"fault_description" validate:"omitempty,min=1"`
+	Vendor           *string                   `json:"vendor" validate:"omitempty,max=200"`
+	Cost             *float64                  `json:"cost" validate:"omitempty,min=0"`
+	StartDate        *time.Time                `json:"start_date"`
+	Result           *models.MaintenanceResult `json:"result" validate:"omitempty,oneof=repaired unrepairable no_fault"`
+	ResultNotes      *string                   `json:"result_notes"`
+	Attachments      []string                  `json:"attachments" validate:"max=20,dive,max=500"`
+}
+
+// CloseMaintenanceRequest 关闭维修工单请求
+type CloseMaintenanceRequest struct {
+	Result      models.MaintenanceResult `json:"result" validate:"required,oneof=repaired unrepairable no_fault"`
+	ResultNotes string                   `json:"result_notes"`
+	Cost        *float64                 `json:"cost" validate:"omitempty,min=0"`
+	FinishDate  *time.Time               `json:"finish_date"`
+}
+
+// MaintenanceFilters 维修工单筛选条件
+type MaintenanceFilters struct {
+	AssetID       *uint                     `json:"asset_id" form:"asset_id"`
+	Status        *models.MaintenanceStatus `json:"status" form:"status"`
+	Result        *models.MaintenanceResult `json:"result" form:"result"`
+	Vendor        *string                   `json:"vendor" form:"vendor"`
+	StartDateFrom *time.Time                `json:"start_date_from" form:"start_date_from"`
+	StartDateTo   *time.Time                `json:"start_date_to" form:"start_date_to"`
+}
@@ -41,6 +41,34 @@ func getAssetSummary(baseQuery *gorm.DB) AssetSummary {
 	query6 := baseQuery.Session(&gorm.Session{})
 	query6.Where("status = ?", models.AssetStatusScrapped).Count(&summary.ScrappedAssets)
 
+	// 已报废资产按处置方式拆分，以核销记录为准
+	// 核销记录列统一加前缀，避免与资产筛选条件中未限定表名的列冲突
+	summary.ScrappedByMethod = []ScrappedMethodStats{}
+	rows, err := baseQuery.Session(&gorm.Session{}).Select(`
+		COALESCE(wo.write_off_method, 'unrecorded') as method,
+		COUNT(assets.id) as asset_count,
+		COALESCE(SUM(assets.purchase_price), 0) as total_value,
+		COALESCE(SUM(wo.write_off_residual_value), 0) as residual_value
+	`).
+		Joins(`LEFT JOIN (
+			SELECT asset_id AS write_off_asset_id, method AS write_off_method, residual_value AS write_off_residual_value
+			FROM asset_write_offs
+		) wo ON wo.write_off_asset_id = assets.id`).
+		Where("assets.status = ?", models.AssetStatusScrapped).
+		Group("COALESCE(wo.write_off_method, 'unrecorded')").
+		Order("asset_count DESC").
+		Rows()
+	if err != nil {
+		return summary
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stat ScrappedMethodStats
+		rows.Scan(&stat.Method, &stat.AssetCount, &stat.TotalValue, &stat.ResidualValue)
+		summary.ScrappedByMethod = append(summary.ScrappedByMethod, stat)
+	}
+
 	return summary
 }
 
@@ -188,8 +216,8 @@ func getAssetsByPurchaseYear(query *gorm.DB) []PurchaseYearStats {
 	return stats
 }
 
-// getAssetValueAnalysis 获取资产价值分析
-func getAssetValueAnalysis(baseQuery *gorm.DB) ValueAnalysis {
+// getAssetValueAnalysis 获取资产价值分析，includeNetValue 为 true 时同时按折旧计划汇总净值
+func getAssetValueAnalysis(baseQuery *gorm.DB, includeNetValue bool) ValueAnalysis {
 	var analysis ValueAnalysis
 
 	// 高价值资产 (>10000)
@@ -219,6 +247,80 @@ func getAssetValueAnalysis(baseQuery *gorm.DB) ValueAnalysis {
 		}
 	}
 
+	// 净值分析
+	if includeNetValue {
+		if netValue, err := getNetValueAnalysis(baseQuery.Session(&gorm.Session{})); err == nil {
+			analysis.NetValue = netValue
+		}
+	}
+
+	return analysis
+}
+
+// getAssetMaintenanceAnalysis 获取资产维修分析，统计筛选范围内资产的维修工单和费用
+func getAssetMaintenanceAnalysis(baseQuery *gorm.DB) MaintenanceAnalysis {
+	analysis := MaintenanceAnalysis{ByCategory: []MaintenanceCategoryStats{}}
+
+	// 通过关联资产查询，复用资产的筛选条件和数据范围
+	// 工单列统一加前缀，避免与资产筛选条件中未限定表名的列（如 status、created_at）冲突
+	joinOrders := `JOIN (
+		SELECT id AS order_id, asset_id AS order_asset_id, status AS order_status, cost AS order_cost
+		FROM maintenance_orders WHERE deleted_at IS NULL
+	) mo ON mo.order_asset_id = assets.id`
+
+	// 工单数量和费用汇总
+	row := baseQuery.Session(&gorm.Session{}).Select(`
+		COUNT(mo.order_id),
+		COALESCE(SUM(CASE WHEN mo.order_status = ? THEN 1 ELSE 0 END), 0),
+		COUNT(DISTINCT assets.id),
+		COALESCE(SUM(mo.order_cost), 0)
+	`, models.MaintenanceStatusOpen).
+		Joins(joinOrders).
+		Row()
+	if row != nil {
+		row.Scan(&analysis.TotalOrders, &analysis.OpenOrders, &analysis.RepairedAssets, &analysis.TotalCost)
+	}
+	analysis.TotalCost = roundAmount(analysis.TotalCost)
+	if analysis.TotalOrders > 0 {
+		analysis.AverageCost = roundAmount(analysis.TotalCost / float64(analysis.TotalOrders))
+	}
+
+	// 维修资产原值合计（每项资产只计一次）
+	var repairedValue float64
+	row = baseQuery.Session(&gorm.Session{}).
+		Select("COALESCE(SUM(assets.purchase_price), 0)").
+		Where("assets.id IN (SELECT asset_id FROM maintenance_orders WHERE deleted_at IS NULL)").
+		Row()
+	if row != nil {
+		row.Scan(&repairedValue)
+	}
+	if repairedValue > 0 {
+		analysis.CostToValueRate = roundAmount(analysis.TotalCost / repairedValue * 100)
+	}
+
+	// 按分类统计
+	rows, err := baseQuery.Session(&gorm.Session{}).Select(`
+		assets.category_id as category_id,
+		(SELECT name FROM categories WHERE categories.id = assets.category_id) as category_name,
+		COUNT(mo.order_id) as order_count,
+		COALESCE(SUM(mo.order_cost), 0) as total_cost
+	`).
+		Joins(joinOrders).
+		Group("assets.category_id").
+		Order("total_cost DESC").
+		Rows()
+	if err != nil {
+		return analysis
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stat MaintenanceCategoryStats
+		rows.Scan(&stat.CategoryID, &stat.CategoryName, &stat.OrderCount, &stat.TotalCost)
+		stat.TotalCost = roundAmount(stat.TotalCost)
+		analysis.ByCategory = append(analysis.ByCategory, stat)
+	}
+
 	return analysis
 }
 
@@ -5,8 +5,8 @@ import (
 	"strings"
 	"time"
 
-	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 
 	"gorm.io/gorm"
 )
@@ -23,7 +23,7 @@ func getBorrowSummary(query *gorm.DB) BorrowSummary {
 
 	// 已归还借用数 - 对于已归还记录，应该统计所有已归还的记录，不受时间过滤影响
 	// 因为时间过滤是基于borrow_date的，但已归还记录应该基于actual_return_date
-	global.DB.Model(&models.BorrowRecord{}).Where("status = ?", models.BorrowStatusReturned).Count(&summary.ReturnedBorrows)
+	auth.ScopeOf(query).DB().Model(&models.BorrowRecord{}).Where("status = ?", models.BorrowStatusReturned).Count(&summary.ReturnedBorrows)
 
 	// 超期借用数
 	now := time.Now()
@@ -61,7 +61,7 @@ func getBorrowsByDepartment(query *gorm.DB) []BorrowDepartmentStats {
 	}
 
 	// 构建统计查询，确保继承WHERE条件
-	baseQuery := global.DB.Model(&models.BorrowRecord{})
+	baseQuery := auth.ScopeOf(query).DB().Model(&models.BorrowRecord{})
 	if sql != "" && len(args) > 0 {
 		// 如果有WHERE条件，解析并应用
 		whereClause := strings.TrimPrefix(sql, "SELECT * FROM `borrow_records`")
@@ -115,7 +115,7 @@ func getBorrowsByAsset(query *gorm.DB) []BorrowAssetStats {
 	}
 
 	// 构建统计查询，确保继承WHERE条件
-	baseQuery := global.DB.Model(&models.BorrowRecord{})
+	baseQuery := auth.ScopeOf(query).DB().Model(&models.BorrowRecord{})
 	if sql != "" && len(args) > 0 {
 		// 如果有WHERE条件，解析并应用
 		whereClause := strings.TrimPrefix(sql, "SELECT * FROM `borrow_records`")
@@ -242,13 +242,13 @@ func getBorrowOverdueDaysStats(baseQuery *gorm.DB) []OverdueDaysStats {
 }
 
 // getBorrowMonthlyTrend 获取借用月度趋势
-func getBorrowMonthlyTrend(_ *gorm.DB) []MonthlyBorrowStats {
+func getBorrowMonthlyTrend(query *gorm.DB) []MonthlyBorrowStats {
 	// 直接使用简化版本，避免复杂的SQL查询
-	return getBorrowMonthlyTrendSimple()
+	return getBorrowMonthlyTrendSimple(auth.ScopeOf(query).DB())
 }
 
 // getBorrowMonthlyTrendSimple 获取借用月度趋势（简化版本）
-func getBorrowMonthlyTrendSimple() []MonthlyBorrowStats {
+func getBorrowMonthlyTrendSimple(db *gorm.DB) []MonthlyBorrowStats {
 	var stats []MonthlyBorrowStats
 
 	// 获取最近6个月的数据
@@ -261,12 +261,12 @@ func getBorrowMonthlyTrendSimple() []MonthlyBorrowStats {
 		var borrowCount, returnCount int64
 
 		// 查询借用数据
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("borrow_date >= ? AND borrow_date <= ?", monthStart, monthEnd).
 			Count(&borrowCount)
 
 		// 查询归还数据
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("actual_return_date >= ? AND actual_return_date <= ?", monthStart, monthEnd).
 			Count(&returnCount)
 
@@ -292,7 +292,7 @@ func getBorrowPopularAssets(query *gorm.DB) []PopularAssetStats {
 	}
 
 	// 构建统计查询，确保继承WHERE条件
-	baseQuery := global.DB.Model(&models.BorrowRecord{})
+	baseQuery := auth.ScopeOf(query).DB().Model(&models.BorrowRecord{})
 	if sql != "" && len(args) > 0 {
 		// 如果有WHERE条件，解析并应用
 		whereClause := strings.TrimPrefix(sql, "SELECT * FROM `borrow_records`")
@@ -347,7 +347,7 @@ func getBorrowsByBorrower(query *gorm.DB) []BorrowerStats {
 	}
 
 	// 构建统计查询，确保继承WHERE条件
-	baseQuery := global.DB.Model(&models.BorrowRecord{})
+	baseQuery := auth.ScopeOf(query).DB().Model(&models.BorrowRecord{})
 	if sql != "" && len(args) > 0 {
 		// 如果有WHERE条件，解析并应用
 		whereClause := strings.TrimPrefix(sql, "SELECT * FROM `borrow_records`")
@@ -402,7 +402,7 @@ func getBorrowsByAssetCategory(query *gorm.DB) []BorrowCategoryStats {
 	}
 
 	// 构建统计查询，确保继承WHERE条件
-	baseQuery := global.DB.Model(&models.BorrowRecord{})
+	baseQuery := auth.ScopeOf(query).DB().Model(&models.BorrowRecord{})
 	if sql != "" && len(args) > 0 {
 		// 如果有WHERE条件，解析并应用
 		whereClause := strings.TrimPrefix(sql, "SELECT * FROM `borrow_records`")
@@ -504,8 +504,9 @@ func getBorrowTrends(query *gorm.DB) BorrowTrends {
 }
 
 // getBorrowWeeklyTrend 获取周度借用趋势
-func getBorrowWeeklyTrend(_ *gorm.DB) []WeeklyBorrowStats {
+func getBorrowWeeklyTrend(query *gorm.DB) []WeeklyBorrowStats {
 	var stats []WeeklyBorrowStats
+	db := auth.ScopeOf(query).DB()
 
 	// 获取最近8周的数据
 	for i := 7; i >= 0; i-- {
@@ -514,11 +515,11 @@ func getBorrowWeeklyTrend(_ *gorm.DB) []WeeklyBorrowStats {
 
 		var borrowCount, returnCount int64
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("borrow_date >= ? AND borrow_date <= ?", weekStart, weekEnd).
 			Count(&borrowCount)
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("actual_return_date >= ? AND actual_return_date <= ?", weekStart, weekEnd).
 			Count(&returnCount)
 
@@ -534,8 +535,9 @@ func getBorrowWeeklyTrend(_ *gorm.DB) []WeeklyBorrowStats {
 }
 
 // getBorrowDailyTrend 获取日度借用趋势
-func getBorrowDailyTrend(_ *gorm.DB) []DailyBorrowStats {
+func getBorrowDailyTrend(query *gorm.DB) []DailyBorrowStats {
 	var stats []DailyBorrowStats
+	db := auth.ScopeOf(query).DB()
 
 	// 获取最近30天的数据
 	for i := 29; i >= 0; i-- {
@@ -545,11 +547,11 @@ func getBorrowDailyTrend(_ *gorm.DB) []DailyBorrowStats {
 
 		var borrowCount, returnCount int64
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("borrow_date >= ? AND borrow_date <= ?", dateStart, dateEnd).
 			Count(&borrowCount)
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("actual_return_date >= ? AND actual_return_date <= ?", dateStart, dateEnd).
 			Count(&returnCount)
 
@@ -565,19 +567,20 @@ func getBorrowDailyTrend(_ *gorm.DB) []DailyBorrowStats {
 }
 
 // getBorrowHourlyPattern 获取小时借用模式
-func getBorrowHourlyPattern(_ *gorm.DB) []HourlyBorrowStats {
+func getBorrowHourlyPattern(query *gorm.DB) []HourlyBorrowStats {
 	var stats []HourlyBorrowStats
+	db := auth.ScopeOf(query).DB()
 
 	// 获取最近30天的小时模式
 	for hour := 0; hour < 24; hour++ {
 		var borrowCount, returnCount int64
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("strftime('%H', borrow_date) = ? AND borrow_date >= date('now', '-30 days')",
 				fmt.Sprintf("%02d", hour)).
 			Count(&borrowCount)
 
-		global.DB.Model(&models.BorrowRecord{}).
+		db.Model(&models.BorrowRecord{}).
 			Where("strftime('%H', actual_return_date) = ? AND actual_return_date >= date('now', '-30 days')",
 				fmt.Sprintf("%02d", hour)).
 			Count(&returnCount)
@@ -0,0 +1,198 @@
+package reports
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetConsumableReport 获取耗材出入库报表，默认统计当月，按耗材列出期初、入库、出库和期末
+func GetConsumableReport(c *gin.Context) {
+	start, end, err := parseConsumableReportRange(c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    "VALIDATION_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 耗材为公共库存，不按数据范围过滤
+	query := global.DB.Model(&models.ConsumableTransaction{})
+	if consumableID := c.Query("consumable_id"); consumableID != "" {
+		query = query.Where("consumable_id = ?", consumableID)
+	}
+	if location := c.Query("location"); location != "" {
+		query = query.Where("location = ?", location)
+	}
+
+	report, err := buildConsumableReport(query, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "DATABASE_ERROR",
+			"message": "生成耗材出入库报表失败",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "获取耗材出入库报表成功",
+		"data":    report,
+	})
+}
+
+// parseConsumableReportRange 解析统计区间，返回的结束时间为结束日期次日零点（不含）
+func parseConsumableReportRange(startDate, endDate string) (time.Time, time.Time, error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var err error
+	if startDate != "" {
+		if start, err = time.ParseInLocation("2006-01-02", startDate, now.Location()); err != nil {
+			return start, end, fmt.Errorf("开始日期格式错误，应为 YYYY-MM-DD")
+		}
+	}
+	if endDate != "" {
+		if end, err = time.ParseInLocation("2006-01-02", endDate, now.Location()); err != nil {
+			return start, end, fmt.Errorf("结束日期格式错误，应为 YYYY-MM-DD")
+		}
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("结束日期不能早于开始日期")
+	}
+
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// buildConsumableReport 汇总区间内的出入库流水，期初余额由区间开始前的流水累计得出
+func buildConsumableReport(query *gorm.DB, start, end time.Time) (*ConsumableReport, error) {
+	report := &ConsumableReport{
+		Items:        make([]ConsumableMovementItem, 0),
+		ByDepartment: make([]ConsumableDepartmentStats, 0),
+		ByMonth:      make([]ConsumableMonthStats, 0),
+	}
+	report.Summary.StartDate = start.Format("2006-01-02")
+	report.Summary.EndDate = end.AddDate(0, 0, -1).Format("2006-01-02")
+
+	// 按耗材汇总期初和区间内收发
+	var movements []ConsumableMovementItem
+	if err := query.Session(&gorm.Session{}).
+		Select(`
+			consumable_id,
+			COALESCE(SUM(CASE WHEN created_at < ? THEN CASE WHEN type = ? THEN -quantity ELSE quantity END ELSE 0 END), 0) as opening_balance,
+			COALESCE(SUM(CASE WHEN created_at >= ? AND type = ? THEN quantity ELSE 0 END), 0) as in_quantity,
+			COALESCE(SUM(CASE WHEN created_at >= ? AND type = ? THEN quantity ELSE 0 END), 0) as out_quantity,
+			COALESCE(SUM(CASE WHEN created_at >= ? AND type = ? THEN quantity * COALESCE(unit_price, 0) ELSE 0 END), 0) as in_amount
+		`, start, models.ConsumableTransactionOut,
+			start, models.ConsumableTransactionIn,
+			start, models.ConsumableTransactionOut,
+			start, models.ConsumableTransactionIn).
+		Where("created_at < ?", end).
+		Group("consumable_id").
+		Scan(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	// 已删除的耗材仍保留历史流水
+	consumableIDs := make([]uint, 0, len(movements))
+	for _, movement := range movements {
+		consumableIDs = append(consumableIDs, movement.ConsumableID)
+	}
+	var consumables []models.Consumable
+	if len(consumableIDs) > 0 {
+		if err := global.DB.Unscoped().Where("id IN ?", consumableIDs).Find(&consumables).Error; err != nil {
+			return nil, err
+		}
+	}
+	consumableMap := make(map[uint]models.Consumable, len(consumables))
+	for _, consumable := range consumables {
+		consumableMap[consumable.ID] = consumable
+	}
+
+	for _, item := range movements {
+		if item.OpeningBalance == 0 && item.InQuantity == 0 && item.OutQuantity == 0 {
+			continue
+		}
+		if consumable, exists := consumableMap[item.ConsumableID]; exists {
+			item.Code = consumable.Code
+			item.Name = consumable.Name
+			item.Unit = consumable.Unit
+			item.MinStock = consumable.MinStock
+		}
+		item.ClosingBalance = item.OpeningBalance + item.InQuantity - item.OutQuantity
+		item.InAmount = roundAmount(item.InAmount)
+		report.Items = append(report.Items, item)
+
+		report.Summary.ConsumableCount++
+		report.Summary.OpeningBalance += item.OpeningBalance
+		report.Summary.InQuantity += item.InQuantity
+		report.Summary.OutQuantity += item.OutQuantity
+		report.Summary.ClosingBalance += item.ClosingBalance
+		report.Summary.InAmount += item.InAmount
+	}
+	report.Summary.InAmount = roundAmount(report.Summary.InAmount)
+
+	if err := models.LowStockConsumables(global.DB).Count(&report.Summary.LowStockCount).Error; err != nil {
+		return nil, err
+	}
+
+	// 按领用部门统计出库
+	period := query.Session(&gorm.Session{}).Where("created_at >= ? AND created_at < ?", start, end)
+	if err := period.Session(&gorm.Session{}).
+		Select("department_id, COALESCE(SUM(quantity), 0) as out_quantity, COUNT(*) as transaction_count").
+		Where("type = ?", models.ConsumableTransactionOut).
+		Group("department_id").
+		Order("out_quantity DESC").
+		Scan(&report.ByDepartment).Error; err != nil {
+		return nil, err
+	}
+
+	departmentIDs := make([]uint, 0, len(report.ByDepartment))
+	for _, stat := range report.ByDepartment {
+		if stat.DepartmentID != nil {
+			departmentIDs = append(departmentIDs, *stat.DepartmentID)
+		}
+	}
+	var departments []models.Department
+	if len(departmentIDs) > 0 {
+		if err := global.DB.Unscoped().Where("id IN ?", departmentIDs).Find(&departments).Error; err != nil {
+			return nil, err
+		}
+	}
+	departmentNames := make(map[uint]string, len(departments))
+	for _, department := range departments {
+		departmentNames[department.ID] = department.Name
+	}
+	for i := range report.ByDepartment {
+		stat := &report.ByDepartment[i]
+		if stat.DepartmentID == nil {
+			stat.DepartmentName = "未指定部门"
+			continue
+		}
+		stat.DepartmentName = departmentNames[*stat.DepartmentID]
+	}
+
+	// 按月统计收发
+	if err := period.Session(&gorm.Session{}).
+		Select(`
+			strftime('%Y-%m', created_at) as month,
+			COALESCE(SUM(CASE WHEN type = ? THEN quantity ELSE 0 END), 0) as in_quantity,
+			COALESCE(SUM(CASE WHEN type = ? THEN quantity ELSE 0 END), 0) as out_quantity
+		`, models.ConsumableTransactionIn, models.ConsumableTransactionOut).
+		Group("strftime('%Y-%m', created_at)").
+		Order("month ASC").
+		Scan(&report.ByMonth).Error; err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -5,19 +5,19 @@ import (
 	"strconv"
 	"strings"
 
-	"asset-management-system/server/global"
+	"asset-management-system/server/pkg/auth"
 
 	"gorm.io/gorm"
 )
 
 // generateCustomAssetReport 生成自定义资产报表
-func generateCustomAssetReport(req CustomReportRequest) ([]map[string]interface{}, []CustomReportColumn, int64) {
+func generateCustomAssetReport(req CustomReportRequest, scope *auth.DataScope) ([]map[string]interface{}, []CustomReportColumn, int64) {
 	var data []map[string]interface{}
 	var columns []CustomReportColumn
 	var totalCount int64
 
 	// 构建基础查询
-	query := global.DB.Table("assets a").
+	query := scope.DB().Table("assets a").
 		Select(buildAssetSelectFields(req.Metrics)).
 		Joins("LEFT JOIN categories c ON c.id = a.category_id").
 		Joins("LEFT JOIN departments d ON d.id = a.department_id")
@@ -97,13 +97,13 @@ func generateCustomAssetReport(req CustomReportRequest) ([]map[string]interface{
 }
 
 // generateCustomBorrowReport 生成自定义借用报表
-func generateCustomBorrowReport(req CustomReportRequest) ([]map[string]interface{}, []CustomReportColumn, int64) {
+func generateCustomBorrowReport(req CustomReportRequest, scope *auth.DataScope) ([]map[string]interface{}, []CustomReportColumn, int64) {
 	var data []map[string]interface{}
 	var columns []CustomReportColumn
 	var totalCount int64
 
 	// 构建基础查询
-	query := global.DB.Table("borrow_records br").
+	query := scope.DB().Table("borrow_records br").
 		Select(buildBorrowSelectFields(req.Metrics)).
 		Joins("JOIN assets a ON a.id = br.asset_id").
 		Joins("LEFT JOIN departments d ON d.id = br.department_id")
@@ -183,13 +183,13 @@ func generateCustomBorrowReport(req CustomReportRequest) ([]map[string]interface
 }
 
 // generateCustomInventoryReport 生成自定义盘点报表
-func generateCustomInventoryReport(req CustomReportRequest) ([]map[string]interface{}, []CustomReportColumn, int64) {
+func generateCustomInventoryReport(req CustomReportRequest, scope *auth.DataScope) ([]map[string]interface{}, []CustomReportColumn, int64) {
 	var data []map[string]interface{}
 	var columns []CustomReportColumn
 	var totalCount int64
 
 	// 构建基础查询
-	query := global.DB.Table("inventory_records ir").
+	query := scope.DB().Table("inventory_records ir").
 		Select(buildInventorySelectFields(req.Metrics)).
 		Joins("JOIN inventory_tasks it ON it.id = ir.task_id").
 		Joins("JOIN assets a ON a.id = ir.asset_id").
@@ -1,21 +1,23 @@
 package reports
 
 import (
+	"fmt"
 	"time"
 
-	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+
+	"gorm.io/gorm"
 )
 
 // getDashboardAssetOverview 获取仪表板资产概览
-func getDashboardAssetOverview() AssetOverview {
+func getDashboardAssetOverview(db *gorm.DB) AssetOverview {
 	var overview AssetOverview
 
 	// 总资产数和价值
-	global.DB.Model(&models.Asset{}).Count(&overview.TotalAssets)
+	db.Model(&models.Asset{}).Count(&overview.TotalAssets)
 
 	var totalValue *float64
-	row := global.DB.Model(&models.Asset{}).Select("SUM(purchase_price)").Row()
+	row := db.Model(&models.Asset{}).Select("SUM(purchase_price)").Row()
 	if row != nil {
 		err := row.Scan(&totalValue)
 		if err == nil && totalValue != nil {
@@ -24,15 +26,15 @@ func getDashboardAssetOverview() AssetOverview {
 	}
 
 	// 各状态资产数
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusAvailable).Count(&overview.AvailableAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusBorrowed).Count(&overview.BorrowedAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusMaintenance).Count(&overview.MaintenanceAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusScrapped).Count(&overview.ScrappedAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusAvailable).Count(&overview.AvailableAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusBorrowed).Count(&overview.BorrowedAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusMaintenance).Count(&overview.MaintenanceAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusScrapped).Count(&overview.ScrappedAssets)
 
 	// 计算增长率（相比上月）
 	lastMonth := time.Now().AddDate(0, -1, 0)
 	var lastMonthAssets int64
-	global.DB.Model(&models.Asset{}).Where("created_at < ?", lastMonth).Count(&lastMonthAssets)
+	db.Model(&models.Asset{}).Where("created_at < ?", lastMonth).Count(&lastMonthAssets)
 
 	if lastMonthAssets > 0 {
 		overview.GrowthRate = float64(overview.TotalAssets-lastMonthAssets) / float64(lastMonthAssets) * 100
@@ -42,7 +44,7 @@ func getDashboardAssetOverview() AssetOverview {
 }
 
 // getDashboardBorrowOverview 获取仪表板借用概览
-func getDashboardBorrowOverview() BorrowOverview {
+func getDashboardBorrowOverview(db *gorm.DB) BorrowOverview {
 	var overview BorrowOverview
 
 	now := time.Now()
@@ -50,20 +52,20 @@ func getDashboardBorrowOverview() BorrowOverview {
 	tomorrow := today.AddDate(0, 0, 1)
 
 	// 活跃借用数
-	global.DB.Model(&models.BorrowRecord{}).Where("status = ?", models.BorrowStatusBorrowed).Count(&overview.ActiveBorrows)
+	db.Model(&models.BorrowRecord{}).Where("status = ?", models.BorrowStatusBorrowed).Count(&overview.ActiveBorrows)
 
 	// 超期借用数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ? AND expected_return_date < ?", models.BorrowStatusBorrowed, now).
 		Count(&overview.OverdueBorrows)
 
 	// 今日借用数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("borrow_date >= ? AND borrow_date < ?", today, tomorrow).
 		Count(&overview.TodayBorrows)
 
 	// 今日归还数
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("actual_return_date >= ? AND actual_return_date < ?", today, tomorrow).
 		Count(&overview.TodayReturns)
 
@@ -76,28 +78,28 @@ func getDashboardBorrowOverview() BorrowOverview {
 }
 
 // getDashboardInventoryOverview 获取仪表板盘点概览
-func getDashboardInventoryOverview() InventoryOverview {
+func getDashboardInventoryOverview(db *gorm.DB) InventoryOverview {
 	var overview InventoryOverview
 
 	// 计算活跃任务数（pending + in_progress）
 	var pendingTasks, inProgressTasks int64
-	global.DB.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusPending).Count(&pendingTasks)
-	global.DB.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusInProgress).Count(&inProgressTasks)
+	db.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusPending).Count(&pendingTasks)
+	db.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusInProgress).Count(&inProgressTasks)
 	overview.ActiveTasks = pendingTasks + inProgressTasks
 
 	// 已完成任务数
-	global.DB.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusCompleted).Count(&overview.CompletedTasks)
+	db.Model(&models.InventoryTask{}).Where("status = ?", models.InventoryTaskStatusCompleted).Count(&overview.CompletedTasks)
 
 	// 最近完成任务的准确率
 	var task models.InventoryTask
-	if err := global.DB.Where("status = ?", models.InventoryTaskStatusCompleted).
+	if err := db.Where("status = ?", models.InventoryTaskStatusCompleted).
 		Order("updated_at DESC").First(&task).Error; err == nil {
 
 		var normalCount, totalRecords int64
-		global.DB.Model(&models.InventoryRecord{}).
+		db.Model(&models.InventoryRecord{}).
 			Where("task_id = ? AND result = ?", task.ID, models.InventoryResultNormal).
 			Count(&normalCount)
-		global.DB.Model(&models.InventoryRecord{}).
+		db.Model(&models.InventoryRecord{}).
 			Where("task_id = ?", task.ID).
 			Count(&totalRecords)
 
@@ -110,12 +112,12 @@ func getDashboardInventoryOverview() InventoryOverview {
 }
 
 // getDashboardRecentActivity 获取仪表板最近活动
-func getDashboardRecentActivity() RecentActivity {
+func getDashboardRecentActivity(db *gorm.DB) RecentActivity {
 	var activity RecentActivity
 
 	// 最近资产
 	var recentAssets []RecentAsset
-	global.DB.Table("assets a").
+	db.Table("assets a").
 		Select("a.id, a.asset_no, a.name, c.name as category_name, a.created_at").
 		Joins("LEFT JOIN categories c ON c.id = a.category_id").
 		Order("a.created_at DESC").
@@ -125,7 +127,7 @@ func getDashboardRecentActivity() RecentActivity {
 
 	// 最近借用
 	var recentBorrows []RecentBorrow
-	global.DB.Table("borrow_records br").
+	db.Table("borrow_records br").
 		Select("br.id, a.asset_no, a.name as asset_name, u.name as borrower_name, br.borrow_date").
 		Joins("JOIN assets a ON a.id = br.asset_id").
 		Joins("JOIN users u ON u.id = br.borrower_id").
@@ -136,7 +138,7 @@ func getDashboardRecentActivity() RecentActivity {
 
 	// 最近归还
 	var recentReturns []RecentReturn
-	global.DB.Table("borrow_records br").
+	db.Table("borrow_records br").
 		Select("br.id, a.asset_no, a.name as asset_name, u.name as borrower_name, br.actual_return_date").
 		Joins("JOIN assets a ON a.id = br.asset_id").
 		Joins("JOIN users u ON u.id = br.borrower_id").
@@ -150,13 +152,13 @@ func getDashboardRecentActivity() RecentActivity {
 }
 
 // getDashboardAlerts 获取仪表板系统警报
-func getDashboardAlerts() []SystemAlert {
+func getDashboardAlerts(db *gorm.DB) []SystemAlert {
 	var alerts []SystemAlert
 	now := time.Now()
 
 	// 超期借用警报
 	var overdueCount int64
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ? AND expected_return_date < ?", models.BorrowStatusBorrowed, now).
 		Count(&overdueCount)
 
@@ -174,7 +176,7 @@ func getDashboardAlerts() []SystemAlert {
 	// 保修即将到期警报
 	var warrantyExpiringCount int64
 	thirtyDaysLater := now.AddDate(0, 0, 30)
-	global.DB.Model(&models.Asset{}).
+	db.Model(&models.Asset{}).
 		Where(`
 			purchase_date IS NOT NULL 
 			AND warranty_period IS NOT NULL 
@@ -193,19 +195,65 @@ func getDashboardAlerts() []SystemAlert {
 		})
 	}
 
-	// 维护中资产警报
-	var maintenanceCount int64
-	global.DB.Model(&models.Asset{}).
-		Where("status = ?", models.AssetStatusMaintenance).
-		Count(&maintenanceCount)
-
-	if maintenanceCount > 0 {
+	// 维修工单警报：未关闭的工单，超过7天未完成的提升为中等级别
+	var openMaintenance struct {
+		Count     int64
+		LongCount int64
+		Cost      float64
+	}
+	sevenDaysAgo := now.AddDate(0, 0, -7)
+	db.Model(&models.MaintenanceOrder{}).
+		Select(`
+			COUNT(*) as count,
+			COALESCE(SUM(CASE WHEN start_date < ? THEN 1 ELSE 0 END), 0) as long_count,
+			COALESCE(SUM(cost), 0) as cost
+		`, sevenDaysAgo).
+		Where("status = ?", models.MaintenanceStatusOpen).
+		Scan(&openMaintenance)
+
+	if openMaintenance.Count > 0 {
+		severity := "low"
+		description := fmt.Sprintf("有%d张维修工单未关闭，已登记维修费用%.2f元", openMaintenance.Count, openMaintenance.Cost)
+		if openMaintenance.LongCount > 0 {
+			severity = "medium"
+			description += fmt.Sprintf("，其中%d张已超过7天未完成", openMaintenance.LongCount)
+		}
 		alerts = append(alerts, SystemAlert{
 			Type:        "maintenance_due",
-			Title:       "资产维护中",
-			Description: "有资产正在维护中，请关注维护进度",
-			Count:       maintenanceCount,
-			Severity:    "low",
+			Title:       "资产维修中",
+			Description: description,
+			Count:       openMaintenance.Count,
+			Severity:    severity,
+			CreatedAt:   now,
+		})
+	}
+
+	// 许可证即将到期警报
+	var licenseExpiringCount int64
+	models.ExpiringLicenses(db, now, 30).Count(&licenseExpiringCount)
+
+	if licenseExpiringCount > 0 {
+		alerts = append(alerts, SystemAlert{
+			Type:        "license_expiring",
+			Title:       "许可证即将到期",
+			Description: fmt.Sprintf("有%d个软件许可证将在30天内到期，请及时续费", licenseExpiringCount),
+			Count:       licenseExpiringCount,
+			Severity:    "medium",
+			CreatedAt:   now,
+		})
+	}
+
+	// 耗材低库存警报
+	var lowStockCount int64
+	models.LowStockConsumables(db).Count(&lowStockCount)
+
+	if lowStockCount > 0 {
+		alerts = append(alerts, SystemAlert{
+			Type:        "low_stock",
+			Title:       "耗材库存不足",
+			Description: fmt.Sprintf("有%d种耗材库存低于最低库存，请及时补货", lowStockCount),
+			Count:       lowStockCount,
+			Severity:    "medium",
 			CreatedAt:   now,
 		})
 	}
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"time"
 
-	"asset-management-system/server/global"
 	"asset-management-system/server/models"
 
 	"github.com/gin-gonic/gin"
@@ -232,8 +231,12 @@ func exportInventoryReportsToExcel(c *gin.Context, parameters map[string]interfa
 	categoryID := c.Query("category_id")
 	departmentID := c.Query("department_id")
 
-	// 构建查询条件
-	query := global.DB.Model(&models.InventoryTask{})
+	// 构建查询条件（按数据范围过滤）
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	query := scope.DB().Model(&models.InventoryTask{})
 
 	if startDate != "" {
 		if start, err := time.Parse("2006-01-02", startDate); err == nil {
@@ -306,7 +309,7 @@ func exportInventoryReportsToExcel(c *gin.Context, parameters map[string]interfa
 			DamagedCount  int64
 		}
 
-		global.DB.Model(&models.InventoryRecord{}).
+		scope.DB().Model(&models.InventoryRecord{}).
 			Where("task_id = ?", task.ID).
 			Select(`
 				COUNT(DISTINCT asset_id) as total_assets,
@@ -410,7 +413,7 @@ func exportInventoryReportsToExcel(c *gin.Context, parameters map[string]interfa
 	}
 
 	// 构建查询
-	recordQuery := global.DB.Table("inventory_records ir").
+	recordQuery := scope.DB().Table("inventory_records ir").
 		Select(`
 			it.task_name,
 			a.asset_no,
@@ -550,8 +553,12 @@ func exportInventoryReportsToCSV(c *gin.Context, parameters map[string]interface
 	categoryID := c.Query("category_id")
 	departmentID := c.Query("department_id")
 
-	// 构建查询条件
-	query := global.DB.Model(&models.InventoryTask{})
+	// 构建查询条件（按数据范围过滤）
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	query := scope.DB().Model(&models.InventoryTask{})
 
 	if startDate != "" {
 		if start, err := time.Parse("2006-01-02", startDate); err == nil {
@@ -618,7 +625,7 @@ func exportInventoryReportsToCSV(c *gin.Context, parameters map[string]interface
 	}
 
 	// 构建查询
-	recordQuery := global.DB.Table("inventory_records ir").
+	recordQuery := scope.DB().Table("inventory_records ir").
 		Select(`
 			it.task_name,
 			a.asset_no,
@@ -736,16 +743,21 @@ func exportInventoryReportsToCSV(c *gin.Context, parameters map[string]interface
 
 // exportCustomReportsToExcel 导出自定义报表到Excel
 func exportCustomReportsToExcel(c *gin.Context, req CustomReportRequest) {
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+
 	// 获取自定义报表数据
 	var data []map[string]interface{}
 
 	switch req.ReportType {
 	case "asset":
-		data, _, _ = generateCustomAssetReport(req)
+		data, _, _ = generateCustomAssetReport(req, scope)
 	case "borrow":
-		data, _, _ = generateCustomBorrowReport(req)
+		data, _, _ = generateCustomBorrowReport(req, scope)
 	case "inventory":
-		data, _, _ = generateCustomInventoryReport(req)
+		data, _, _ = generateCustomInventoryReport(req, scope)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    "INVALID_REPORT_TYPE",
@@ -830,16 +842,21 @@ func exportCustomReportsToExcel(c *gin.Context, req CustomReportRequest) {
 
 // exportCustomReportsToCSV 导出自定义报表到CSV
 func exportCustomReportsToCSV(c *gin.Context, req CustomReportRequest) {
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+
 	// 获取自定义报表数据
 	var data []map[string]interface{}
 
 	switch req.ReportType {
 	case "asset":
-		data, _, _ = generateCustomAssetReport(req)
+		data, _, _ = generateCustomAssetReport(req, scope)
 	case "borrow":
-		data, _, _ = generateCustomBorrowReport(req)
+		data, _, _ = generateCustomBorrowReport(req, scope)
 	case "inventory":
-		data, _, _ = generateCustomInventoryReport(req)
+		data, _, _ = generateCustomInventoryReport(req, scope)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    "INVALID_REPORT_TYPE",
@@ -9,6 +9,7 @@ import (
 
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -25,9 +26,14 @@ func GetAssetReports(c *gin.Context) {
 	valueRange := c.Query("value_range")
 	warrantyStatus := c.Query("warranty_status")
 	includeSubCategories := c.DefaultQuery("include_sub_categories", "false")
+	includeNetValue := c.DefaultQuery("include_net_value", "false")
 
-	// 构建查询条件
-	query := global.DB.Model(&models.Asset{})
+	// 构建查询条件（按数据范围过滤）
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	query := scope.DB().Model(&models.Asset{})
 
 	// 时间范围筛选
 	if startDate != "" {
@@ -102,7 +108,7 @@ func GetAssetReports(c *gin.Context) {
 
 	// 创建基础查询构建函数
 	buildBaseQuery := func() *gorm.DB {
-		baseQuery := global.DB.Model(&models.Asset{})
+		baseQuery := scope.DB().Model(&models.Asset{})
 
 		// 时间范围筛选
 		if startDate != "" {
@@ -186,7 +192,7 @@ func GetAssetReports(c *gin.Context) {
 	byPurchaseYear := getAssetsByPurchaseYear(buildBaseQuery())
 
 	// 获取价值分析
-	valueAnalysis := getAssetValueAnalysis(buildBaseQuery())
+	valueAnalysis := getAssetValueAnalysis(buildBaseQuery(), includeNetValue == "true")
 
 	// 获取保修状态
 	warrantyStatusData := getAssetWarrantyStatus(buildBaseQuery())
@@ -197,6 +203,9 @@ func GetAssetReports(c *gin.Context) {
 	byPurchaseMonth := getAssetsByPurchaseMonth(buildBaseQuery())
 	utilizationRate := getAssetUtilizationRate(buildBaseQuery())
 
+	// 获取维修分析
+	maintenance := getAssetMaintenanceAnalysis(buildBaseQuery())
+
 	reportData := AssetReportData{
 		Summary:         summary,
 		ByCategory:      byCategory,
@@ -209,6 +218,7 @@ func GetAssetReports(c *gin.Context) {
 		BySupplier:      bySupplier,
 		ByPurchaseMonth: byPurchaseMonth,
 		UtilizationRate: utilizationRate,
+		Maintenance:     maintenance,
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -230,8 +240,12 @@ func GetBorrowReports(c *gin.Context) {
 	overdueOnly := c.DefaultQuery("overdue_only", "false")
 	borrowDuration := c.Query("borrow_duration")
 
-	// 构建查询条件
-	query := global.DB.Model(&models.BorrowRecord{})
+	// 构建查询条件（按数据范围过滤）
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	query := scope.DB().Model(&models.BorrowRecord{})
 
 	// 时间范围筛选
 	if startDate != "" {
@@ -338,8 +352,12 @@ func GetInventoryReports(c *gin.Context) {
 	taskType := c.Query("task_type")
 	status := c.Query("status")
 
-	// 构建查询条件
-	query := global.DB.Model(&models.InventoryTask{})
+	// 构建查询条件（按数据范围过滤）
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	query := scope.DB().Model(&models.InventoryTask{})
 
 	if startDate != "" {
 		if start, err := time.Parse("2006-01-02", startDate); err == nil {
@@ -365,19 +383,19 @@ func GetInventoryReports(c *gin.Context) {
 	summary := getInventorySummary(query)
 
 	// 获取任务分析
-	taskAnalysis := getInventoryTaskAnalysis(startDate, endDate, taskType, status)
+	taskAnalysis := getInventoryTaskAnalysis(scope, startDate, endDate, taskType, status)
 
 	// 获取结果分析
-	resultAnalysis := getInventoryResultAnalysis()
+	resultAnalysis := getInventoryResultAnalysis(scope)
 
 	// 获取部门分析
-	departmentAnalysis := getInventoryDepartmentAnalysis()
+	departmentAnalysis := getInventoryDepartmentAnalysis(scope)
 
 	// 获取分类分析
-	categoryAnalysis := getInventoryCategoryAnalysis()
+	categoryAnalysis := getInventoryCategoryAnalysis(scope)
 
 	// 获取趋势分析
-	trendAnalysis := getInventoryTrendAnalysis()
+	trendAnalysis := getInventoryTrendAnalysis(scope)
 
 	reportData := InventoryReportData{
 		Summary:            summary,
@@ -397,20 +415,26 @@ func GetInventoryReports(c *gin.Context) {
 
 // GetDashboardReports 获取仪表板报表数据
 func GetDashboardReports(c *gin.Context) {
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+	db := scope.DB()
+
 	// 获取资产概览
-	assetOverview := getDashboardAssetOverview()
+	assetOverview := getDashboardAssetOverview(db)
 
 	// 获取借用概览
-	borrowOverview := getDashboardBorrowOverview()
+	borrowOverview := getDashboardBorrowOverview(db)
 
 	// 获取盘点概览
-	inventoryOverview := getDashboardInventoryOverview()
+	inventoryOverview := getDashboardInventoryOverview(db)
 
 	// 获取最近活动
-	recentActivity := getDashboardRecentActivity()
+	recentActivity := getDashboardRecentActivity(db)
 
 	// 获取系统警报
-	alerts := getDashboardAlerts()
+	alerts := getDashboardAlerts(db)
 
 	reportData := DashboardReportData{
 		AssetSummary:     assetOverview,
@@ -439,6 +463,11 @@ func GetCustomReports(c *gin.Context) {
 		return
 	}
 
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+
 	// 根据报表类型生成自定义报表
 	var data []map[string]interface{}
 	var columns []CustomReportColumn
@@ -446,11 +475,11 @@ func GetCustomReports(c *gin.Context) {
 
 	switch req.ReportType {
 	case "asset":
-		data, columns, totalCount = generateCustomAssetReport(req)
+		data, columns, totalCount = generateCustomAssetReport(req, scope)
 	case "borrow":
-		data, columns, totalCount = generateCustomBorrowReport(req)
+		data, columns, totalCount = generateCustomBorrowReport(req, scope)
 	case "inventory":
-		data, columns, totalCount = generateCustomInventoryReport(req)
+		data, columns, totalCount = generateCustomInventoryReport(req, scope)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    "INVALID_REPORT_TYPE",
@@ -521,6 +550,20 @@ func GetRecentReports(c *gin.Context) {
 	})
 }
 
+// getReportScope 获取当前请求的数据范围，失败时直接返回错误响应
+func getReportScope(c *gin.Context) (*auth.DataScope, bool) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "DATABASE_ERROR",
+			"message": "获取数据范围失败",
+			"data":    err.Error(),
+		})
+		return nil, false
+	}
+	return scope, true
+}
+
 // getReportTypeDisplayName 获取报表类型的显示名称
 func getReportTypeDisplayName(reportType models.ReportRecordType) string {
 	switch reportType {
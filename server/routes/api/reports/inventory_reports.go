@@ -3,6 +3,7 @@ package reports
 import (
 	"asset-management-system/server/global"
 	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
 	"time"
 
 	"gorm.io/gorm"
@@ -25,12 +26,13 @@ func getInventorySummary(query *gorm.DB) InventorySummary {
 	query.Where("status = ?", models.InventoryTaskStatusInProgress).Count(&summary.InProgressTasks)
 
 	// 总记录数
-	global.DB.Model(&models.InventoryRecord{}).Count(&summary.TotalRecords)
+	db := auth.ScopeOf(query).DB()
+	db.Model(&models.InventoryRecord{}).Count(&summary.TotalRecords)
 
 	// 整体准确率
 	var normalCount, totalRecords int64
-	global.DB.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultNormal).Count(&normalCount)
-	global.DB.Model(&models.InventoryRecord{}).Count(&totalRecords)
+	db.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultNormal).Count(&normalCount)
+	db.Model(&models.InventoryRecord{}).Count(&totalRecords)
 
 	if totalRecords > 0 {
 		summary.AccuracyRate = float64(normalCount) / float64(totalRecords) * 100
@@ -40,11 +42,11 @@ func getInventorySummary(query *gorm.DB) InventorySummary {
 }
 
 // getInventoryTaskAnalysis 获取盘点任务分析
-func getInventoryTaskAnalysis(startDate, endDate, taskType, status string) []InventoryTaskStats {
+func getInventoryTaskAnalysis(scope *auth.DataScope, startDate, endDate, taskType, status string) []InventoryTaskStats {
 	var stats []InventoryTaskStats
 
 	// 构建查询
-	query := global.DB.Model(&models.InventoryTask{})
+	query := scope.DB().Model(&models.InventoryTask{})
 
 	if startDate != "" {
 		if start, err := time.Parse("2006-01-02", startDate); err == nil {
@@ -113,14 +115,15 @@ func getInventoryTaskAnalysis(startDate, endDate, taskType, status string) []Inv
 }
 
 // getInventoryResultAnalysis 获取盘点结果分析
-func getInventoryResultAnalysis() InventoryResultAnalysis {
+func getInventoryResultAnalysis(scope *auth.DataScope) InventoryResultAnalysis {
 	var analysis InventoryResultAnalysis
+	db := scope.DB()
 
 	// 各状态记录数
-	global.DB.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultNormal).Count(&analysis.NormalCount)
-	global.DB.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultSurplus).Count(&analysis.SurplusCount)
-	global.DB.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultDeficit).Count(&analysis.DeficitCount)
-	global.DB.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultDamaged).Count(&analysis.DamagedCount)
+	db.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultNormal).Count(&analysis.NormalCount)
+	db.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultSurplus).Count(&analysis.SurplusCount)
+	db.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultDeficit).Count(&analysis.DeficitCount)
+	db.Model(&models.InventoryRecord{}).Where("result = ?", models.InventoryResultDamaged).Count(&analysis.DamagedCount)
 
 	// 总记录数
 	totalRecords := analysis.NormalCount + analysis.SurplusCount + analysis.DeficitCount + analysis.DamagedCount
@@ -137,8 +140,9 @@ func getInventoryResultAnalysis() InventoryResultAnalysis {
 }
 
 // getInventoryDepartmentAnalysis 获取部门盘点分析
-func getInventoryDepartmentAnalysis() []InventoryDepartmentStats {
+func getInventoryDepartmentAnalysis(scope *auth.DataScope) []InventoryDepartmentStats {
 	var stats []InventoryDepartmentStats
+	assetCondition, assetArgs := scope.Condition("assets", "a")
 
 	rows, err := global.DB.Raw(`
 		SELECT 
@@ -149,12 +153,12 @@ func getInventoryDepartmentAnalysis() []InventoryDepartmentStats {
 			SUM(CASE WHEN ir.result = 'normal' THEN 1 ELSE 0 END) as normal_count,
 			SUM(CASE WHEN ir.result != 'normal' THEN 1 ELSE 0 END) as issue_count
 		FROM departments d
-		LEFT JOIN assets a ON a.department_id = d.id
+		LEFT JOIN assets a ON a.department_id = d.id AND `+assetCondition+`
 		LEFT JOIN inventory_records ir ON ir.asset_id = a.id
 		GROUP BY d.id, d.name
 		HAVING checked_assets > 0
 		ORDER BY department_name
-	`).Rows()
+	`, assetArgs...).Rows()
 
 	if err != nil {
 		return stats
@@ -182,8 +186,9 @@ func getInventoryDepartmentAnalysis() []InventoryDepartmentStats {
 }
 
 // getInventoryCategoryAnalysis 获取分类盘点分析
-func getInventoryCategoryAnalysis() []InventoryCategoryStats {
+func getInventoryCategoryAnalysis(scope *auth.DataScope) []InventoryCategoryStats {
 	var stats []InventoryCategoryStats
+	assetCondition, assetArgs := scope.Condition("assets", "a")
 
 	rows, err := global.DB.Raw(`
 		SELECT 
@@ -194,12 +199,12 @@ func getInventoryCategoryAnalysis() []InventoryCategoryStats {
 			SUM(CASE WHEN ir.result = 'normal' THEN 1 ELSE 0 END) as normal_count,
 			SUM(CASE WHEN ir.result != 'normal' THEN 1 ELSE 0 END) as issue_count
 		FROM categories c
-		LEFT JOIN assets a ON a.category_id = c.id
+		LEFT JOIN assets a ON a.category_id = c.id AND `+assetCondition+`
 		LEFT JOIN inventory_records ir ON ir.asset_id = a.id
 		GROUP BY c.id, c.name
 		HAVING checked_assets > 0
 		ORDER BY category_name
-	`).Rows()
+	`, assetArgs...).Rows()
 
 	if err != nil {
 		return stats
@@ -225,8 +230,9 @@ func getInventoryCategoryAnalysis() []InventoryCategoryStats {
 }
 
 // getInventoryTrendAnalysis 获取盘点趋势分析
-func getInventoryTrendAnalysis() []InventoryTrendStats {
+func getInventoryTrendAnalysis(scope *auth.DataScope) []InventoryTrendStats {
 	var stats []InventoryTrendStats
+	taskCondition, taskArgs := scope.Condition("inventory_tasks", "it")
 
 	// 获取最近12个月的盘点趋势
 	rows, err := global.DB.Raw(`
@@ -240,10 +246,10 @@ func getInventoryTrendAnalysis() []InventoryTrendStats {
 			END as accuracy_rate
 		FROM inventory_tasks it
 		LEFT JOIN inventory_records ir ON ir.task_id = it.id
-		WHERE it.created_at >= date('now', '-12 months')
+		WHERE it.created_at >= date('now', '-12 months') AND `+taskCondition+`
 		GROUP BY strftime('%Y-%m', it.created_at)
 		ORDER BY month DESC
-	`).Rows()
+	`, taskArgs...).Rows()
 
 	if err != nil {
 		return stats
@@ -0,0 +1,161 @@
+package reports
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultRenewalDays 续费统计默认天数范围
+const defaultRenewalDays = 90
+
+// GetLicenseReport 获取软件许可证报表：席位使用率和指定天数内（默认90天）需续费的许可证
+func GetLicenseReport(c *gin.Context) {
+	renewalDays := defaultRenewalDays
+	if days := c.Query("renewal_days"); days != "" {
+		value, err := strconv.Atoi(days)
+		if err != nil || value < 1 || value > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "续费天数范围应为 1-365",
+			})
+			return
+		}
+		renewalDays = value
+	}
+
+	// 许可证为公共资源，不按数据范围过滤
+	query := global.DB.Model(&models.License{})
+	if vendor := c.Query("vendor"); vendor != "" {
+		query = query.Where("vendor LIKE ?", "%"+vendor+"%")
+	}
+	if licenseType := c.Query("type"); licenseType != "" {
+		query = query.Where("type = ?", licenseType)
+	}
+
+	report, err := buildLicenseReport(query, renewalDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    "DATABASE_ERROR",
+			"message": "生成许可证报表失败",
+			"data":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "SUCCESS",
+		"message": "获取许可证报表成功",
+		"data":    report,
+	})
+}
+
+// buildLicenseReport 统计各许可证的席位使用情况，并列出续费范围内到期的许可证
+func buildLicenseReport(query *gorm.DB, renewalDays int) (*LicenseReport, error) {
+	report := &LicenseReport{
+		Utilization: make([]LicenseUtilizationItem, 0),
+		Renewals:    make([]LicenseRenewalItem, 0),
+	}
+	report.Summary.RenewalDays = renewalDays
+
+	var licenses []models.License
+	if err := query.Order("product ASC").Find(&licenses).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		LicenseID uint
+		Count     int64
+	}
+	if err := models.ActiveLicenseSeats(global.DB).
+		Select("license_id, COUNT(*) as count").
+		Group("license_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	used := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		used[count.LicenseID] = count.Count
+	}
+
+	now := time.Now()
+	for i := range licenses {
+		license := &licenses[i]
+		item := LicenseUtilizationItem{
+			LicenseID:  license.ID,
+			Product:    license.Product,
+			Vendor:     license.Vendor,
+			Type:       string(license.Type),
+			SeatCount:  int64(license.SeatCount),
+			UsedSeats:  used[license.ID],
+			ExpiryDate: license.ExpiryDate,
+		}
+		item.AvailableSeats = item.SeatCount - item.UsedSeats
+		if item.AvailableSeats < 0 {
+			item.AvailableSeats = 0
+		}
+		if item.SeatCount > 0 {
+			item.Utilization = roundAmount(float64(item.UsedSeats) * 100 / float64(item.SeatCount))
+		}
+		report.Utilization = append(report.Utilization, item)
+
+		report.Summary.LicenseCount++
+		report.Summary.TotalSeats += item.SeatCount
+		report.Summary.UsedSeats += item.UsedSeats
+		if license.Cost != nil {
+			report.Summary.TotalCost += *license.Cost
+		}
+		if item.UsedSeats == 0 {
+			report.Summary.UnusedLicenses++
+		} else if item.AvailableSeats == 0 {
+			report.Summary.FullLicenses++
+		}
+
+		days := license.DaysToExpiry(now)
+		if days == nil {
+			continue
+		}
+		if *days < 0 {
+			report.Summary.ExpiredCount++
+			continue
+		}
+		if *days > renewalDays {
+			continue
+		}
+		renewal := LicenseRenewalItem{
+			LicenseID:     license.ID,
+			Product:       license.Product,
+			Vendor:        license.Vendor,
+			ExpiryDate:    *license.ExpiryDate,
+			DaysRemaining: *days,
+			SeatCount:     item.SeatCount,
+			UsedSeats:     item.UsedSeats,
+		}
+		if license.Cost != nil {
+			renewal.Cost = *license.Cost
+		}
+		report.Renewals = append(report.Renewals, renewal)
+		report.Summary.RenewalCount++
+		report.Summary.RenewalCost += renewal.Cost
+	}
+
+	if report.Summary.TotalSeats > 0 {
+		report.Summary.Utilization = roundAmount(float64(report.Summary.UsedSeats) * 100 / float64(report.Summary.TotalSeats))
+	}
+	report.Summary.TotalCost = roundAmount(report.Summary.TotalCost)
+	report.Summary.RenewalCost = roundAmount(report.Summary.RenewalCost)
+
+	// 按到期先后排列续费清单
+	sort.SliceStable(report.Renewals, func(i, j int) bool {
+		return report.Renewals[i].DaysRemaining < report.Renewals[j].DaysRemaining
+	})
+
+	return report, nil
+}
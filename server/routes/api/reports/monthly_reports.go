@@ -12,6 +12,7 @@ import (
 	"asset-management-system/server/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // MonthlyReportRequest 月度报告请求
@@ -41,8 +42,13 @@ func GenerateMonthlyReport(c *gin.Context) {
 		return
 	}
 
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+
 	// 生成文本报告
-	reportContent, err := generateMonthlyReportText(monthTime)
+	reportContent, err := generateMonthlyReportText(monthTime, scope.DB())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    "GENERATE_ERROR",
@@ -63,9 +69,14 @@ func GenerateMonthlyReport(c *gin.Context) {
 
 // ExportAssetInventory 导出资产清单
 func ExportAssetInventory(c *gin.Context) {
-	// 查询资产数据
+	scope, ok := getReportScope(c)
+	if !ok {
+		return
+	}
+
+	// 查询资产数据（按数据范围过滤）
 	var assets []models.Asset
-	query := global.DB.Preload("Category").Preload("Department").Find(&assets)
+	query := scope.DB().Preload("Category").Preload("Department").Find(&assets)
 	if query.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    "QUERY_ERROR",
@@ -182,7 +193,7 @@ func ExportAssetInventory(c *gin.Context) {
 }
 
 // generateMonthlyReportText 生成月度报告文本
-func generateMonthlyReportText(month time.Time) (string, error) {
+func generateMonthlyReportText(month time.Time, db *gorm.DB) (string, error) {
 	var report bytes.Buffer
 
 	// 标题
@@ -202,15 +213,15 @@ func generateMonthlyReportText(month time.Time) (string, error) {
 
 	// 总资产数
 	var totalAssets int64
-	global.DB.Model(&models.Asset{}).Count(&totalAssets)
+	db.Model(&models.Asset{}).Count(&totalAssets)
 	report.WriteString(fmt.Sprintf("总资产数: %d\n", totalAssets))
 
 	// 各状态资产统计
 	var availableAssets, borrowedAssets, maintenanceAssets, scrappedAssets int64
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusAvailable).Count(&availableAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusBorrowed).Count(&borrowedAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusMaintenance).Count(&maintenanceAssets)
-	global.DB.Model(&models.Asset{}).Where("status = ?", models.AssetStatusScrapped).Count(&scrappedAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusAvailable).Count(&availableAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusBorrowed).Count(&borrowedAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusMaintenance).Count(&maintenanceAssets)
+	db.Model(&models.Asset{}).Where("status = ?", models.AssetStatusScrapped).Count(&scrappedAssets)
 
 	report.WriteString(fmt.Sprintf("可用资产: %d\n", availableAssets))
 	report.WriteString(fmt.Sprintf("借用中资产: %d\n", borrowedAssets))
@@ -223,10 +234,10 @@ func generateMonthlyReportText(month time.Time) (string, error) {
 
 	// 月度借用统计
 	var monthlyBorrows, monthlyReturns int64
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("borrow_date BETWEEN ? AND ?", monthStart, monthEnd).
 		Count(&monthlyBorrows)
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("actual_return_date BETWEEN ? AND ?", monthStart, monthEnd).
 		Count(&monthlyReturns)
 
@@ -235,7 +246,7 @@ func generateMonthlyReportText(month time.Time) (string, error) {
 
 	// 超期借用
 	var overdueCount int64
-	global.DB.Model(&models.BorrowRecord{}).
+	db.Model(&models.BorrowRecord{}).
 		Where("status = ? AND expected_return_date < ?", models.BorrowStatusBorrowed, time.Now()).
 		Count(&overdueCount)
 	report.WriteString(fmt.Sprintf("当前超期借用: %d\n\n", overdueCount))
@@ -246,18 +257,18 @@ func generateMonthlyReportText(month time.Time) (string, error) {
 
 	// 月度盘点任务统计
 	var monthlyTasks int64
-	global.DB.Model(&models.InventoryTask{}).
+	db.Model(&models.InventoryTask{}).
 		Where("created_at BETWEEN ? AND ?", monthStart, monthEnd).
 		Count(&monthlyTasks)
 	report.WriteString(fmt.Sprintf("本月盘点任务: %d\n", monthlyTasks))
 
 	// 盘点准确率
 	var normalRecords, totalRecords int64
-	global.DB.Model(&models.InventoryRecord{}).
+	db.Model(&models.InventoryRecord{}).
 		Joins("JOIN inventory_tasks ON inventory_tasks.id = inventory_records.task_id").
 		Where("inventory_tasks.created_at BETWEEN ? AND ?", monthStart, monthEnd).
 		Count(&totalRecords)
-	global.DB.Model(&models.InventoryRecord{}).
+	db.Model(&models.InventoryRecord{}).
 		Joins("JOIN inventory_tasks ON inventory_tasks.id = inventory_records.task_id").
 		Where("inventory_tasks.created_at BETWEEN ? AND ? AND inventory_records.result = ?",
 			monthStart, monthEnd, models.InventoryResultNormal).
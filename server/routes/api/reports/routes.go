@@ -21,6 +21,18 @@ func RegisterRoutes(router *gin.RouterGroup) {
 		reportsGroup.GET("/inventory", GetInventoryReports)
 		reportsGroup.GET("/inventory/export", ExportInventoryReports)
 
+		// 折旧台账
+		reportsGroup.GET("/depreciation", GetDepreciationLedger)
+
+		// 组件价值汇总
+		reportsGroup.GET("/compositions", GetCompositionReport)
+
+		// 耗材出入库报表
+		reportsGroup.GET("/consumables", GetConsumableReport)
+
+		// 软件许可证席位使用和续费报表
+		reportsGroup.GET("/licenses", GetLicenseReport)
+
 		// 仪表板数据（综合报表）
 		reportsGroup.GET("/dashboard", GetDashboardReports)
 
@@ -2,6 +2,8 @@ package reports
 
 import (
 	"time"
+
+	"asset-management-system/server/models"
 )
 
 // AssetReportData 资产报表数据
@@ -17,6 +19,7 @@ type AssetReportData struct {
 	BySupplier      []SupplierStats      `json:"by_supplier"`
 	ByPurchaseMonth []PurchaseMonthStats `json:"by_purchase_month"`
 	UtilizationRate UtilizationRate      `json:"utilization_rate"`
+	Maintenance     MaintenanceAnalysis  `json:"maintenance"`
 }
 
 // AssetSummary 资产汇总
@@ -27,6 +30,16 @@ type AssetSummary struct {
 	BorrowedAssets    int64   `json:"borrowed_assets"`
 	MaintenanceAssets int64   `json:"maintenance_assets"`
 	ScrappedAssets    int64   `json:"scrapped_assets"`
+
+	ScrappedByMethod []ScrappedMethodStats `json:"scrapped_by_method"` // 已报废资产按处置方式拆分
+}
+
+// ScrappedMethodStats 按处置方式统计的已报废资产
+type ScrappedMethodStats struct {
+	Method        string  `json:"method"` // 处置方式，未经处置流程报废的资产为 unrecorded
+	AssetCount    int64   `json:"asset_count"`
+	TotalValue    float64 `json:"total_value"`    // 原值合计
+	ResidualValue float64 `json:"residual_value"` // 残值收入合计
 }
 
 // CategoryStats 分类统计
@@ -68,6 +81,37 @@ type ValueAnalysis struct {
 	LowValue     int64   `json:"low_value"`     // 低价值资产数量 (<1000)
 	NoValue      int64   `json:"no_value"`      // 无价值信息资产数量
 	AverageValue float64 `json:"average_value"` // 平均价值
+
+	NetValue *NetValueAnalysis `json:"net_value,omitempty"` // 净值分析，include_net_value=true 时返回
+}
+
+// NetValueAnalysis 净值分析
+type NetValueAnalysis struct {
+	Period                  string  `json:"period"`                   // 统计期间（YYYY-MM）
+	DepreciableAssets       int64   `json:"depreciable_assets"`       // 可计算折旧的资产数量
+	SkippedAssets           int64   `json:"skipped_assets"`           // 缺少价格、日期或折旧参数的资产数量
+	TotalOriginalValue      float64 `json:"total_original_value"`     // 原值合计
+	AccumulatedDepreciation float64 `json:"accumulated_depreciation"` // 累计折旧合计
+	TotalNetValue           float64 `json:"total_net_value"`          // 净值合计
+}
+
+// MaintenanceAnalysis 维修分析
+type MaintenanceAnalysis struct {
+	TotalOrders     int64                      `json:"total_orders"`       // 维修工单总数
+	OpenOrders      int64                      `json:"open_orders"`        // 未关闭工单数
+	RepairedAssets  int64                      `json:"repaired_assets"`    // 发生过维修的资产数量
+	TotalCost       float64                    `json:"total_cost"`         // 维修费用合计
+	AverageCost     float64                    `json:"average_cost"`       // 平均每单维修费用
+	CostToValueRate float64                    `json:"cost_to_value_rate"` // 维修费用占维修资产原值的百分比
+	ByCategory      []MaintenanceCategoryStats `json:"by_category"`
+}
+
+// MaintenanceCategoryStats 按分类统计的维修数据
+type MaintenanceCategoryStats struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	OrderCount   int64   `json:"order_count"`
+	TotalCost    float64 `json:"total_cost"`
 }
 
 // WarrantyStatus 保修状态
@@ -355,7 +399,7 @@ type RecentReturn struct {
 
 // SystemAlert 系统警报
 type SystemAlert struct {
-	Type        string    `json:"type"` // overdue, warranty_expiring, maintenance_due
+	Type        string    `json:"type"` // overdue, warranty_expiring, maintenance_due, license_expiring, low_stock
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Count       int64     `json:"count"`
@@ -435,3 +479,159 @@ type ExportRequest struct {
 	DateRange  DateRange              `json:"date_range"`
 	Filters    map[string]interface{} `json:"filters"`
 }
+
+// DepreciationLedger 折旧台账
+type DepreciationLedger struct {
+	Period  string                    `json:"period"`
+	Summary DepreciationLedgerSummary `json:"summary"`
+	Items   []DepreciationLedgerItem  `json:"items"`
+}
+
+// DepreciationLedgerSummary 折旧台账汇总
+type DepreciationLedgerSummary struct {
+	AssetCount              int64   `json:"asset_count"`              // 计入台账的资产数量
+	SkippedCount            int64   `json:"skipped_count"`            // 缺少价格、日期或折旧参数的资产数量
+	TotalOriginalValue      float64 `json:"total_original_value"`     // 原值合计
+	PeriodDepreciation      float64 `json:"period_depreciation"`      // 本期折旧合计
+	AccumulatedDepreciation float64 `json:"accumulated_depreciation"` // 累计折旧合计
+	TotalNetValue           float64 `json:"total_net_value"`          // 净值合计
+}
+
+// DepreciationLedgerItem 折旧台账明细
+type DepreciationLedgerItem struct {
+	AssetID        uint                      `json:"asset_id"`
+	AssetNo        string                    `json:"asset_no"`
+	AssetName      string                    `json:"asset_name"`
+	CategoryName   string                    `json:"category_name"`
+	DepartmentName string                    `json:"department_name"`
+	Method         models.DepreciationMethod `json:"method"`
+	OriginalValue  float64                   `json:"original_value"` // 原值
+	Depreciation   float64                   `json:"depreciation"`   // 本期折旧额
+	Accumulated    float64                   `json:"accumulated"`    // 累计折旧
+	NetValue       float64                   `json:"net_value"`      // 期末净值
+}
+
+// CompositionReport 组件价值汇总报表，组件原值计入顶层父资产
+type CompositionReport struct {
+	Summary CompositionReportSummary `json:"summary"`
+	Items   []CompositionReportItem  `json:"items"`
+}
+
+// CompositionReportSummary 组件价值汇总
+type CompositionReportSummary struct {
+	ParentCount    int64   `json:"parent_count"`    // 含组件的顶层资产数量
+	ComponentCount int64   `json:"component_count"` // 组件数量（含多级组件）
+	OwnValue       float64 `json:"own_value"`       // 父资产自身原值合计
+	ComponentValue float64 `json:"component_value"` // 组件原值合计
+	TotalValue     float64 `json:"total_value"`     // 含组件的原值合计
+}
+
+// CompositionReportItem 组件价值汇总明细
+type CompositionReportItem struct {
+	AssetID        uint    `json:"asset_id"`
+	AssetNo        string  `json:"asset_no"`
+	AssetName      string  `json:"asset_name"`
+	CategoryName   string  `json:"category_name"`
+	DepartmentName string  `json:"department_name"`
+	OwnValue       float64 `json:"own_value"`       // 自身原值
+	ComponentCount int64   `json:"component_count"` // 组件数量（含多级组件）
+	ComponentValue float64 `json:"component_value"` // 组件原值合计
+	TotalValue     float64 `json:"total_value"`     // 含组件的原值合计
+}
+
+// ConsumableReport 耗材出入库报表
+type ConsumableReport struct {
+	Summary      ConsumableReportSummary     `json:"summary"`
+	Items        []ConsumableMovementItem    `json:"items"`
+	ByDepartment []ConsumableDepartmentStats `json:"by_department"`
+	ByMonth      []ConsumableMonthStats      `json:"by_month"`
+}
+
+// ConsumableReportSummary 耗材出入库汇总
+type ConsumableReportSummary struct {
+	StartDate       string  `json:"start_date"`
+	EndDate         string  `json:"end_date"`
+	ConsumableCount int64   `json:"consumable_count"` // 期间有余额或有变动的耗材数
+	LowStockCount   int64   `json:"low_stock_count"`  // 当前低于最低库存的耗材数
+	OpeningBalance  int64   `json:"opening_balance"`  // 期初余额合计
+	InQuantity      int64   `json:"in_quantity"`      // 入库数量合计
+	OutQuantity     int64   `json:"out_quantity"`     // 出库数量合计
+	ClosingBalance  int64   `json:"closing_balance"`  // 期末余额合计
+	InAmount        float64 `json:"in_amount"`        // 入库金额合计
+}
+
+// ConsumableMovementItem 单个耗材的期初、收发和期末
+type ConsumableMovementItem struct {
+	ConsumableID   uint    `json:"consumable_id"`
+	Code           string  `json:"code"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	MinStock       int     `json:"min_stock"`
+	OpeningBalance int64   `json:"opening_balance"`
+	InQuantity     int64   `json:"in_quantity"`
+	OutQuantity    int64   `json:"out_quantity"`
+	ClosingBalance int64   `json:"closing_balance"`
+	InAmount       float64 `json:"in_amount"`
+}
+
+// ConsumableDepartmentStats 按领用部门统计的出库
+type ConsumableDepartmentStats struct {
+	DepartmentID     *uint  `json:"department_id"`
+	DepartmentName   string `json:"department_name"`
+	OutQuantity      int64  `json:"out_quantity"`
+	TransactionCount int64  `json:"transaction_count"`
+}
+
+// ConsumableMonthStats 按月统计的出入库数量
+type ConsumableMonthStats struct {
+	Month       string `json:"month"`
+	InQuantity  int64  `json:"in_quantity"`
+	OutQuantity int64  `json:"out_quantity"`
+}
+
+// LicenseReport 软件许可证报表
+type LicenseReport struct {
+	Summary     LicenseReportSummary     `json:"summary"`
+	Utilization []LicenseUtilizationItem `json:"utilization"`
+	Renewals    []LicenseRenewalItem     `json:"renewals"`
+}
+
+// LicenseReportSummary 软件许可证汇总
+type LicenseReportSummary struct {
+	LicenseCount   int64   `json:"license_count"`
+	TotalSeats     int64   `json:"total_seats"`
+	UsedSeats      int64   `json:"used_seats"`
+	Utilization    float64 `json:"utilization"` // 席位使用率（%）
+	TotalCost      float64 `json:"total_cost"`
+	RenewalDays    int     `json:"renewal_days"`    // 续费统计的天数范围
+	RenewalCount   int64   `json:"renewal_count"`   // 范围内到期的许可证数
+	RenewalCost    float64 `json:"renewal_cost"`    // 范围内到期许可证的上次费用合计
+	ExpiredCount   int64   `json:"expired_count"`   // 已过期的许可证数
+	FullLicenses   int64   `json:"full_licenses"`   // 席位已分配完的许可证数
+	UnusedLicenses int64   `json:"unused_licenses"` // 未分配任何席位的许可证数
+}
+
+// LicenseUtilizationItem 单个许可证的席位使用情况
+type LicenseUtilizationItem struct {
+	LicenseID      uint       `json:"license_id"`
+	Product        string     `json:"product"`
+	Vendor         string     `json:"vendor"`
+	Type           string     `json:"type"`
+	SeatCount      int64      `json:"seat_count"`
+	UsedSeats      int64      `json:"used_seats"`
+	AvailableSeats int64      `json:"available_seats"`
+	Utilization    float64    `json:"utilization"`
+	ExpiryDate     *time.Time `json:"expiry_date"`
+}
+
+// LicenseRenewalItem 即将到期需续费的许可证
+type LicenseRenewalItem struct {
+	LicenseID     uint      `json:"license_id"`
+	Product       string    `json:"product"`
+	Vendor        string    `json:"vendor"`
+	ExpiryDate    time.Time `json:"expiry_date"`
+	DaysRemaining int       `json:"days_remaining"`
+	SeatCount     int64     `json:"seat_count"`
+	UsedSeats     int64     `json:"used_seats"`
+	Cost          float64   `json:"cost"` // 上次购买或续费费用
+}
@@ -0,0 +1,497 @@
+package reservations
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetReservations 获取预约列表
+func GetReservations(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters ReservationFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"start_time": false,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "asset_id", "reserver_name", "department_id", "start_time", "end_time", "status", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 先将超时未取用的预约置为过期，保证状态筛选准确
+	if _, err := models.ExpireReservations(global.DB, pickupGrace()); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 构建查询（按数据范围过滤）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := preloadReservation(scope.DB().Model(&models.AssetReservation{}))
+
+	// 应用筛选条件
+	query = applyReservationFilters(query, filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取预约列表
+	var reservations []models.AssetReservation
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&reservations).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	responses := make([]ReservationResponse, len(reservations))
+	for i := range reservations {
+		responses[i] = buildReservationResponse(&reservations[i])
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, responses)
+	utils.Success(c, response)
+}
+
+// GetReservation 获取预约详情
+func GetReservation(c *gin.Context) {
+	reservation, ok := findReservation(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, buildReservationResponse(reservation))
+}
+
+// CreateReservation 创建预约，时间段不能与其他有效预约或届时未归还的借用重叠
+func CreateReservation(c *gin.Context) {
+	var req CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+	if !req.EndTime.After(req.StartTime) {
+		utils.ValidationError(c, "预约结束时间必须晚于开始时间")
+		return
+	}
+	if !req.EndTime.After(time.Now()) {
+		utils.ValidationError(c, "预约结束时间必须晚于当前时间")
+		return
+	}
+
+	// 只能预约数据范围内的资产
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	var asset models.Asset
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if asset.Status == models.AssetStatusScrapped {
+		utils.ErrorWithMessage(c, utils.ASSET_NOT_AVAILABLE, "资产已报废，不能预约", nil)
+		return
+	}
+	frozen, err := models.IsAssetFrozen(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if frozen {
+		utils.Error(c, utils.ASSET_FROZEN, nil)
+		return
+	}
+
+	// 部门受限用户未指定部门时默认归属本部门
+	if req.DepartmentID == nil && !scope.All {
+		req.DepartmentID = scope.DepartmentID
+	}
+
+	// 验证部门是否存在（如果提供了部门ID）
+	if req.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权为该部门登记预约", nil)
+			return
+		}
+
+		var department models.Department
+		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	reservation := models.AssetReservation{
+		AssetID:         asset.ID,
+		ReserverName:    req.ReserverName,
+		ReserverContact: req.ReserverContact,
+		DepartmentID:    req.DepartmentID,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		Purpose:         req.Purpose,
+		Notes:           req.Notes,
+		Status:          models.ReservationStatusReserved,
+		ReservedBy:      auth.GetOperator(c),
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 在事务中检查冲突，避免并发预约同一时间段
+	conflict, err := findConflicts(tx, asset.ID, req.StartTime, req.EndTime, 0)
+	if err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+	if conflict != nil {
+		tx.Rollback()
+		utils.Error(c, utils.RESERVATION_CONFLICT, conflict)
+		return
+	}
+
+	if err := tx.Create(&reservation).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadReservation(global.DB).First(&reservation, reservation.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, buildReservationResponse(&reservation))
+}
+
+// CancelReservation 取消预约，只能取消尚未取用的有效预约
+func CancelReservation(c *gin.Context) {
+	var req CancelReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	reservation, ok := findReservation(c)
+	if !ok {
+		return
+	}
+
+	if reservation.Status != models.ReservationStatusReserved {
+		utils.Error(c, utils.RESERVATION_INVALID_STATUS, gin.H{"status": reservation.Status})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":        models.ReservationStatusCancelled,
+		"cancelled_by":  auth.GetOperator(c),
+		"cancelled_at":  time.Now(),
+		"cancel_reason": req.Reason,
+	}
+	if err := global.DB.Model(reservation).Updates(updates).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadReservation(global.DB).First(reservation, reservation.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, buildReservationResponse(reservation))
+}
+
+// PickupReservation 取用预约的资产，在同一事务中创建借用记录并将预约置为已取用
+func PickupReservation(c *gin.Context) {
+	var req PickupReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 取用即登记借用，须同时有借用登记权限
+	if !auth.HasPermission(auth.GetIdentity(c).Roles, "borrow", auth.ActionCreate) {
+		utils.Error(c, utils.FORBIDDEN, nil)
+		return
+	}
+
+	reservation, ok := findReservation(c)
+	if !ok {
+		return
+	}
+
+	if !reservation.IsActive(pickupGrace()) {
+		utils.Error(c, utils.RESERVATION_INVALID_STATUS, gin.H{"status": reservation.Status})
+		return
+	}
+
+	now := time.Now()
+	expectedReturnDate := reservation.EndTime
+	if req.ExpectedReturnDate != nil {
+		expectedReturnDate = *req.ExpectedReturnDate
+	}
+	if !expectedReturnDate.After(now) {
+		utils.ValidationError(c, "预计归还时间必须晚于当前时间")
+		return
+	}
+
+	// 检查资产当前能否借出
+	if reservation.Asset.Status != models.AssetStatusAvailable {
+		utils.ErrorWithMessage(c, utils.ASSET_NOT_AVAILABLE, "资产当前状态为 "+string(reservation.Asset.Status)+"，暂不能取用", nil)
+		return
+	}
+	frozen, err := models.IsAssetFrozen(global.DB, reservation.AssetID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if frozen {
+		utils.Error(c, utils.ASSET_FROZEN, nil)
+		return
+	}
+
+	notes := fmt.Sprintf("由预约 #%d 取用", reservation.ID)
+	if req.Notes != "" {
+		notes = req.Notes
+	}
+	borrowRecord := models.BorrowRecord{
+		AssetID:            reservation.AssetID,
+		BorrowerName:       reservation.ReserverName,
+		BorrowerContact:    reservation.ReserverContact,
+		DepartmentID:       reservation.DepartmentID,
+		BorrowDate:         now,
+		ExpectedReturnDate: &expectedReturnDate,
+		Purpose:            reservation.Purpose,
+		Notes:              notes,
+		Status:             models.BorrowStatusBorrowed,
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 借用期间不能与其他预约或未归还的借用重叠
+	conflict, err := findConflicts(tx, reservation.AssetID, now, expectedReturnDate, reservation.ID)
+	if err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+	if conflict != nil {
+		tx.Rollback()
+		utils.Error(c, utils.RESERVATION_CONFLICT, conflict)
+		return
+	}
+
+	// 创建借用记录，资产状态由模型钩子变更为借用中
+	if err := tx.Create(&borrowRecord).Error; err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return
+	}
+
+	if err := tx.Model(reservation).Updates(map[string]interface{}{
+		"status":           models.ReservationStatusFulfilled,
+		"borrow_record_id": borrowRecord.ID,
+		"fulfilled_at":     now,
+	}).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadReservation(global.DB).First(reservation, reservation.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, buildReservationResponse(reservation))
+}
+
+// findReservation 按路径参数查找数据范围内的预约，失败时已写入响应
+func findReservation(c *gin.Context) (*models.AssetReservation, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的预约ID")
+		return nil, false
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	var reservation models.AssetReservation
+	if err := preloadReservation(scope.DB()).First(&reservation, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.RESERVATION_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	// 超时未取用的预约按过期处理
+	if reservation.Status == models.ReservationStatusReserved && !reservation.IsActive(pickupGrace()) {
+		if err := global.DB.Model(&reservation).Update("status", models.ReservationStatusExpired).Error; err != nil {
+			utils.InternalError(c, err)
+			return nil, false
+		}
+		reservation.Status = models.ReservationStatusExpired
+	}
+
+	return &reservation, true
+}
+
+// findConflicts 查找与时间段重叠的有效预约和届时仍未归还的借用，没有冲突时返回 nil
+func findConflicts(tx *gorm.DB, assetID uint, start, end time.Time, excludeID uint) (*ReservationConflictResponse, error) {
+	reservations, err := models.FindReservationConflicts(tx, assetID, start, &end, pickupGrace(), excludeID)
+	if err != nil {
+		return nil, err
+	}
+	borrowRecord, err := models.FindBorrowConflict(tx, assetID, start)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 && borrowRecord == nil {
+		return nil, nil
+	}
+	return &ReservationConflictResponse{Reservations: reservations, BorrowRecord: borrowRecord}, nil
+}
+
+// buildReservationResponse 组装预约响应
+func buildReservationResponse(reservation *models.AssetReservation) ReservationResponse {
+	response := ReservationResponse{AssetReservation: *reservation}
+	if reservation.IsActive(pickupGrace()) {
+		deadline := reservation.PickupDeadline(pickupGrace())
+		response.PickupDeadline = &deadline
+		response.CanPickup = reservation.Asset.Status == models.AssetStatusAvailable
+		response.CanCancel = true
+	}
+	return response
+}
+
+// pickupGrace 预约开始后的取用宽限时间
+func pickupGrace() time.Duration {
+	return time.Duration(global.AppConfig.ReservationPickupGraceHours) * time.Hour
+}
+
+// preloadReservation 预加载预约关联数据
+func preloadReservation(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Asset").
+		Preload("Department").
+		Preload("BorrowRecord")
+}
+
+// applyReservationFilters 应用预约筛选条件
+func applyReservationFilters(query *gorm.DB, filters ReservationFilters) *gorm.DB {
+	if filters.AssetID != nil {
+		query = query.Where("asset_id = ?", *filters.AssetID)
+	}
+	if filters.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filters.DepartmentID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.ReserverName != nil && *filters.ReserverName != "" {
+		query = query.Where("reserver_name LIKE ?", "%"+*filters.ReserverName+"%")
+	}
+	if filters.From != nil {
+		query = query.Where("end_time > ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("start_time < ?", *filters.To)
+	}
+
+	return query
+}
@@ -0,0 +1,17 @@
+package reservations
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册资产预约路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	reservations := r.Group("/reservations")
+	{
+		reservations.GET("", GetReservations)              // 获取预约列表
+		reservations.POST("", CreateReservation)           // 创建预约
+		reservations.GET("/:id", GetReservation)           // 获取预约详情
+		reservations.PUT("/:id/cancel", CancelReservation) // 取消预约
+		reservations.PUT("/:id/pickup", PickupReservation) // 取用资产，预约转为借用记录
+	}
+}
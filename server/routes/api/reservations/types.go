@@ -0,0 +1,53 @@
+package reservations
+
+import (
+	"asset-management-system/server/models"
+	"time"
+)
+
+// CreateReservationRequest 创建预约请求
+type CreateReservationRequest struct {
+	AssetID         uint      `json:"asset_id" validate:"required"`
+	ReserverName    string    `json:"reserver_name" validate:"required,max=100"`
+	ReserverContact string    `json:"reserver_contact" validate:"max=100"`
+	DepartmentID    *uint     `json:"department_id"`
+	StartTime       time.Time `json:"start_time" validate:"required"`
+	EndTime         time.Time `json:"end_time" validate:"required"`
+	Purpose         string    `json:"purpose"`
+	Notes           string    `json:"notes"`
+}
+
+// CancelReservationRequest 取消预约请求
+type CancelReservationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PickupReservationRequest 取用预约资产请求
+type PickupReservationRequest struct {
+	ExpectedReturnDate *time.Time `json:"expected_return_date"` // 不填时为预约结束时间
+	Notes              string     `json:"notes"`
+}
+
+// ReservationResponse 预约响应
+type ReservationResponse struct {
+	models.AssetReservation
+	PickupDeadline *time.Time `json:"pickup_deadline,omitempty"` // 有效预约的取用截止时间
+	CanPickup      bool       `json:"can_pickup"`
+	CanCancel      bool       `json:"can_cancel"`
+}
+
+// ReservationConflictResponse 预约时间冲突详情
+type ReservationConflictResponse struct {
+	Reservations []models.AssetReservation `json:"reservations"`            // 时间段重叠的有效预约
+	BorrowRecord *models.BorrowRecord      `json:"borrow_record,omitempty"` // 届时仍未归还的借用记录
+}
+
+// ReservationFilters 预约筛选条件
+type ReservationFilters struct {
+	AssetID      *uint                     `json:"asset_id" form:"asset_id"`
+	DepartmentID *uint                     `json:"department_id" form:"department_id"`
+	Status       *models.ReservationStatus `json:"status" form:"status"`
+	ReserverName *string                   `json:"reserver_name" form:"reserver_name"`
+	From         *time.Time                `json:"from" form:"from"` // 与 from~to 时间段重叠的预约
+	To           *time.Time                `json:"to" form:"to"`
+}
@@ -0,0 +1,293 @@
+package scan
+
+import (
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PerformScanAction 对扫码识别的资产执行借出、归还或盘点，在一个事务中完成
+func PerformScanAction(c *gin.Context) {
+	var req ScanActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	code := scanCode(c)
+	asset, matchedBy, ok := resolveAsset(c, scope, code)
+	if !ok {
+		return
+	}
+
+	// 按当前状态校验动作是否可执行
+	state, err := buildScanResponse(c, scope, asset)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	for _, unavailable := range state.Unavailable {
+		if unavailable.Action != req.Action {
+			continue
+		}
+		if !auth.HasPermission(auth.GetIdentity(c).Roles, actionResource(req.Action), actionPermission(req.Action)) {
+			utils.Error(c, utils.FORBIDDEN, nil)
+			return
+		}
+		utils.ErrorWithMessage(c, utils.SCAN_ACTION_NOT_ALLOWED, unavailable.Reason, nil)
+		return
+	}
+
+	response := ScanActionResponse{Action: req.Action}
+	switch req.Action {
+	case ActionCheckout:
+		response.BorrowRecord, ok = checkout(c, scope, asset, &req)
+	case ActionCheckin:
+		response.BorrowRecord, ok = checkin(c, state.OpenBorrow, &req)
+	case ActionInventory:
+		response.InventoryRecord, ok = checkInventory(c, asset, state.ActiveTasks, &req)
+	}
+	if !ok {
+		return
+	}
+
+	// 返回执行后的状态，便于继续扫码操作
+	response.Scan, err = buildScanResponse(c, scope, asset)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	response.Scan.Code = code
+	response.Scan.MatchedBy = matchedBy
+
+	utils.Success(c, response)
+}
+
+// actionResource 获取动作对应的权限路由分组
+func actionResource(action string) string {
+	if action == ActionInventory {
+		return "inventory"
+	}
+	return "borrow"
+}
+
+// actionPermission 获取动作所需的操作权限
+func actionPermission(action string) string {
+	if action == ActionCheckin {
+		return auth.ActionUpdate
+	}
+	return auth.ActionCreate
+}
+
+// checkout 创建借用记录，失败时已写入响应
+func checkout(c *gin.Context, scope *auth.DataScope, asset *models.Asset, req *ScanActionRequest) (*models.BorrowRecord, bool) {
+	if req.BorrowerName == "" {
+		utils.ValidationError(c, "借出时借用人不能为空")
+		return nil, false
+	}
+
+	// 部门受限用户未指定部门时默认归属本部门
+	if req.DepartmentID == nil && !scope.All {
+		req.DepartmentID = scope.DepartmentID
+	}
+	if req.DepartmentID != nil {
+		if !scope.CanAccessDepartment(req.DepartmentID) {
+			utils.ErrorWithMessage(c, utils.FORBIDDEN, "无权为该部门登记借用", nil)
+			return nil, false
+		}
+
+		var department models.Department
+		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return nil, false
+			}
+			utils.InternalError(c, err)
+			return nil, false
+		}
+	}
+
+	borrowDate := time.Now()
+	if req.BorrowDate != nil {
+		borrowDate = *req.BorrowDate
+	}
+	// 借用期间不能与有效预约重叠，预约的资产须通过预约取用
+	conflicts, err := models.FindReservationConflicts(global.DB, asset.ID, borrowDate, req.ExpectedReturnDate, pickupGrace(), 0)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+	if len(conflicts) > 0 {
+		utils.ErrorWithMessage(c, utils.RESERVATION_CONFLICT, "借用期间资产已被预约，请填写在预约开始前的预计归还日期或通过预约取用", gin.H{"reservations": conflicts})
+		return nil, false
+	}
+
+	record := models.BorrowRecord{
+		AssetID:            asset.ID,
+		BorrowerName:       req.BorrowerName,
+		BorrowerContact:    req.BorrowerContact,
+		DepartmentID:       req.DepartmentID,
+		BorrowDate:         borrowDate,
+		ExpectedReturnDate: req.ExpectedReturnDate,
+		Purpose:            req.Purpose,
+		Notes:              req.Notes,
+		Status:             models.BorrowStatusBorrowed,
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 创建借用记录，资产状态由模型钩子变更为借用中
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return nil, false
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	middleware.SetAuditTarget(c, "borrow_records", record.ID, models.OperationTypeCreate)
+	return &record, true
+}
+
+// checkin 归还资产，随父资产借出的组件一并归还，失败时已写入响应
+func checkin(c *gin.Context, record *models.BorrowRecord, req *ScanActionRequest) (*models.BorrowRecord, bool) {
+	returnDate := time.Now()
+	if req.ReturnDate != nil {
+		returnDate = *req.ReturnDate
+	}
+	updates := map[string]interface{}{
+		"actual_return_date": returnDate,
+		"status":             models.BorrowStatusReturned,
+	}
+	if req.Notes != "" {
+		updates["notes"] = req.Notes
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(record).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	// 更新资产状态
+	if err := models.ChangeAssetStatus(tx, record.AssetID, models.AssetStatusAvailable); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return nil, false
+	}
+
+	// 随父资产借出的组件一并归还
+	if err := models.ReturnComponentRecords(tx, record.ID, returnDate); err != nil {
+		tx.Rollback()
+		utils.StatusTransitionError(c, err)
+		return nil, false
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	middleware.SetAuditTarget(c, "borrow_records", record.ID, models.OperationTypeUpdate)
+	return record, true
+}
+
+// checkInventory 在进行中的盘点任务中登记盘点记录，失败时已写入响应
+func checkInventory(c *gin.Context, asset *models.Asset, tasks []ScanInventoryTask, req *ScanActionRequest) (*models.InventoryRecord, bool) {
+	// 未指定任务时使用唯一尚未盘点该资产的任务
+	var pending []ScanInventoryTask
+	for _, task := range tasks {
+		if task.RecordID == nil && (req.TaskID == 0 || task.ID == req.TaskID) {
+			pending = append(pending, task)
+		}
+	}
+	if len(pending) == 0 {
+		utils.ErrorWithMessage(c, utils.SCAN_ACTION_NOT_ALLOWED, "该资产不在指定的进行中盘点任务范围内，或已盘点", nil)
+		return nil, false
+	}
+	if len(pending) > 1 {
+		utils.ValidationError(c, "该资产在多个进行中的盘点任务中待盘点，请指定盘点任务")
+		return nil, false
+	}
+
+	actualStatus := req.ActualStatus
+	if actualStatus == "" {
+		actualStatus = asset.Status
+	}
+	result := req.Result
+	if result == "" {
+		result = models.InventoryResultNormal
+	}
+	now := time.Now()
+	record := models.InventoryRecord{
+		TaskID:         pending[0].ID,
+		AssetID:        asset.ID,
+		ExpectedStatus: asset.Status,
+		ActualStatus:   actualStatus,
+		Result:         result,
+		Notes:          req.Notes,
+		CheckedAt:      &now,
+		CheckedBy:      auth.GetOperator(c),
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	middleware.SetAuditTarget(c, "inventory_records", record.ID, models.OperationTypeCreate)
+	return &record, true
+}
@@ -0,0 +1,266 @@
+package scan
+
+import (
+	"strings"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/labels"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// openBorrowStatuses 未归还的借用状态
+var openBorrowStatuses = []models.BorrowStatus{models.BorrowStatusBorrowed, models.BorrowStatusOverdue}
+
+// ScanAsset 按资产编号、标签内容或序列号识别资产，返回当前状态下可执行的动作
+func ScanAsset(c *gin.Context) {
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	code := scanCode(c)
+	asset, matchedBy, ok := resolveAsset(c, scope, code)
+	if !ok {
+		return
+	}
+
+	response, err := buildScanResponse(c, scope, asset)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	response.Code = code
+	response.MatchedBy = matchedBy
+
+	utils.Success(c, response)
+}
+
+// pickupGrace 预约开始后的取用宽限时间
+func pickupGrace() time.Duration {
+	return time.Duration(global.AppConfig.ReservationPickupGraceHours) * time.Hour
+}
+
+// scanCode 获取扫码内容，路径参数优先，其次为查询参数 code
+func scanCode(c *gin.Context) string {
+	code := c.Param("code")
+	if code == "" {
+		code = c.Query("code")
+	}
+	return strings.TrimSpace(code)
+}
+
+// resolveAsset 依次按资产编号、标签内容、序列号查找数据范围内的资产，失败时已写入响应
+func resolveAsset(c *gin.Context, scope *auth.DataScope, code string) (*models.Asset, string, bool) {
+	if code == "" {
+		utils.ValidationError(c, "扫码内容不能为空")
+		return nil, "", false
+	}
+
+	var asset models.Asset
+	err := scope.DB().Where("asset_no = ?", code).First(&asset).Error
+	if err == nil {
+		return &asset, MatchedByAssetNo, true
+	}
+	if err != gorm.ErrRecordNotFound {
+		utils.InternalError(c, err)
+		return nil, "", false
+	}
+
+	// 按二维码内容模板解析，如 https://example.com/assets/{id}
+	urlTemplate, err := models.GetSystemConfigValue(global.DB, labels.ConfigKeyQRURL)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, "", false
+	}
+	if id, assetNo, ok := labels.ParsePayload(code, urlTemplate); ok {
+		query := scope.DB()
+		if id != 0 {
+			query = query.Where("id = ?", id)
+		} else {
+			query = query.Where("asset_no = ?", assetNo)
+		}
+		err := query.First(&asset).Error
+		if err == nil {
+			return &asset, MatchedByLabel, true
+		}
+		if err != gorm.ErrRecordNotFound {
+			utils.InternalError(c, err)
+			return nil, "", false
+		}
+	}
+
+	// 序列号不保证唯一，匹配到多个资产时返回候选列表
+	var assets []models.Asset
+	if err := scope.DB().Where("serial_number = ?", code).Order("asset_no ASC").Limit(20).Find(&assets).Error; err != nil {
+		utils.InternalError(c, err)
+		return nil, "", false
+	}
+	switch len(assets) {
+	case 0:
+		utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+		return nil, "", false
+	case 1:
+		return &assets[0], MatchedBySerialNumber, true
+	}
+
+	candidates := make([]ScanAmbiguousItem, len(assets))
+	for i, candidate := range assets {
+		candidates[i] = ScanAmbiguousItem{ID: candidate.ID, AssetNo: candidate.AssetNo, Name: candidate.Name}
+	}
+	utils.Error(c, utils.SCAN_AMBIGUOUS, candidates)
+	return nil, "", false
+}
+
+// buildScanResponse 汇总资产的借用、盘点情况及可执行的动作
+func buildScanResponse(c *gin.Context, scope *auth.DataScope, asset *models.Asset) (*ScanResponse, error) {
+	if err := scope.DB().Preload("Category").Preload("Department").First(asset, asset.ID).Error; err != nil {
+		return nil, err
+	}
+	response := &ScanResponse{
+		Asset:       *asset,
+		ActiveTasks: make([]ScanInventoryTask, 0),
+		Actions:     make([]string, 0),
+		Unavailable: make([]UnavailableAction, 0),
+	}
+
+	// 未归还的借用记录（按数据范围过滤）
+	var openBorrow models.BorrowRecord
+	err := scope.DB().Preload("Department").
+		Where("asset_id = ? AND status IN ?", asset.ID, openBorrowStatuses).
+		Order("borrow_date DESC").
+		First(&openBorrow).Error
+	if err == nil {
+		response.OpenBorrow = &openBorrow
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	reservations, err := models.FindReservationConflicts(global.DB, asset.ID, time.Now(), nil, pickupGrace(), 0)
+	if err != nil {
+		return nil, err
+	}
+	response.Reservations = reservations
+
+	tasks, err := findActiveTasks(scope, asset)
+	if err != nil {
+		return nil, err
+	}
+	response.ActiveTasks = tasks
+
+	roles := auth.GetIdentity(c).Roles
+	checks := []struct {
+		action   string
+		resource string
+		perm     string
+		reason   func() (string, error)
+	}{
+		{ActionCheckout, "borrow", auth.ActionCreate, func() (string, error) { return checkoutBlockedReason(asset) }},
+		{ActionCheckin, "borrow", auth.ActionUpdate, func() (string, error) { return checkinBlockedReason(asset, response.OpenBorrow), nil }},
+		{ActionInventory, "inventory", auth.ActionCreate, func() (string, error) { return inventoryBlockedReason(response.ActiveTasks), nil }},
+	}
+	for _, check := range checks {
+		reason := ""
+		if !auth.HasPermission(roles, check.resource, check.perm) {
+			reason = "没有该操作的权限"
+		} else if reason, err = check.reason(); err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			response.Unavailable = append(response.Unavailable, UnavailableAction{Action: check.action, Reason: reason})
+			continue
+		}
+		response.Actions = append(response.Actions, check.action)
+	}
+
+	return response, nil
+}
+
+// findActiveTasks 获取数据范围内盘点范围包含该资产的进行中任务
+func findActiveTasks(scope *auth.DataScope, asset *models.Asset) ([]ScanInventoryTask, error) {
+	var tasks []models.InventoryTask
+	if err := scope.DB().Where("status = ?", models.InventoryTaskStatusInProgress).Order("id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]ScanInventoryTask, 0, len(tasks))
+	for i := range tasks {
+		var count int64
+		if err := tasks[i].ScopeAssets(global.DB.Model(&models.Asset{})).Where("id = ?", asset.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			continue
+		}
+
+		item := ScanInventoryTask{ID: tasks[i].ID, TaskName: tasks[i].TaskName}
+		var record models.InventoryRecord
+		err := global.DB.Select("id").Where("task_id = ? AND asset_id = ?", tasks[i].ID, asset.ID).First(&record).Error
+		if err == nil {
+			item.RecordID = &record.ID
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// checkoutBlockedReason 检查资产能否借出，不能借出时返回原因
+func checkoutBlockedReason(asset *models.Asset) (string, error) {
+	if asset.Status != models.AssetStatusAvailable {
+		return "资产当前状态为 " + string(asset.Status) + "，不可借用", nil
+	}
+
+	frozen, err := models.IsAssetFrozen(global.DB, asset.ID)
+	if err != nil {
+		return "", err
+	}
+	if frozen {
+		return "资产处置中，已冻结", nil
+	}
+
+	var count int64
+	if err := global.DB.Model(&models.BorrowRecord{}).
+		Where("asset_id = ? AND status IN ?", asset.ID, openBorrowStatuses).
+		Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "资产已有未归还的借用记录", nil
+	}
+	return "", nil
+}
+
+// checkinBlockedReason 检查资产能否归还，不能归还时返回原因
+func checkinBlockedReason(asset *models.Asset, openBorrow *models.BorrowRecord) string {
+	if openBorrow != nil {
+		return ""
+	}
+	if asset.Status == models.AssetStatusBorrowed {
+		return "借用记录不在数据范围内"
+	}
+	return "资产未借出"
+}
+
+// inventoryBlockedReason 检查资产能否盘点，不能盘点时返回原因
+func inventoryBlockedReason(tasks []ScanInventoryTask) string {
+	if len(tasks) == 0 {
+		return "没有包含该资产的进行中盘点任务"
+	}
+	for _, task := range tasks {
+		if task.RecordID == nil {
+			return ""
+		}
+	}
+	return "资产已在全部进行中的盘点任务中盘点"
+}
@@ -0,0 +1,17 @@
+package scan
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册扫码路由
+// 扫码内容含斜杠（如二维码网址）时改用查询参数 code 传入
+func RegisterRoutes(r *gin.RouterGroup) {
+	scan := r.Group("/scan")
+	{
+		scan.GET("", ScanAsset)                        // 扫码识别资产（查询参数 code）
+		scan.POST("/actions", PerformScanAction)       // 执行扫码动作（查询参数 code）
+		scan.GET("/:code", ScanAsset)                  // 扫码识别资产及可执行的动作
+		scan.POST("/:code/actions", PerformScanAction) // 执行借出、归还或盘点
+	}
+}
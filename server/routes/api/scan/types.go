@@ -0,0 +1,84 @@
+package scan
+
+import (
+	"time"
+
+	"asset-management-system/server/models"
+)
+
+// 扫码动作
+const (
+	ActionCheckout  = "checkout"  // 借出，创建借用记录
+	ActionCheckin   = "checkin"   // 归还
+	ActionInventory = "inventory" // 在进行中的盘点任务中登记盘点记录
+)
+
+// 扫码内容的匹配方式
+const (
+	MatchedByAssetNo      = "asset_no"      // 资产编号
+	MatchedByLabel        = "label"         // 按二维码内容模板解析的标签内容
+	MatchedBySerialNumber = "serial_number" // 序列号
+)
+
+// ScanResponse 扫码识别结果
+type ScanResponse struct {
+	Code         string                    `json:"code"`         // 扫码内容
+	MatchedBy    string                    `json:"matched_by"`   // 匹配方式
+	Asset        models.Asset              `json:"asset"`        // 资产
+	OpenBorrow   *models.BorrowRecord      `json:"open_borrow"`  // 未归还的借用记录
+	Reservations []models.AssetReservation `json:"reservations"` // 尚未取用的有效预约，借出时预计归还日期须早于最近的预约
+	ActiveTasks  []ScanInventoryTask       `json:"active_tasks"` // 盘点范围包含该资产的进行中任务
+	Actions      []string                  `json:"actions"`      // 当前可执行的动作
+	Unavailable  []UnavailableAction       `json:"unavailable"`  // 不可执行的动作及原因
+}
+
+// ScanInventoryTask 包含该资产的进行中盘点任务
+type ScanInventoryTask struct {
+	ID       uint   `json:"id"`
+	TaskName string `json:"task_name"`
+	RecordID *uint  `json:"record_id"` // 本任务中该资产的盘点记录，未盘点时为空
+}
+
+// UnavailableAction 不可执行的动作
+type UnavailableAction struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// ScanAmbiguousItem 扫码内容匹配到的多个资产之一
+type ScanAmbiguousItem struct {
+	ID      uint   `json:"id"`
+	AssetNo string `json:"asset_no"`
+	Name    string `json:"name"`
+}
+
+// ScanActionRequest 扫码动作请求，按动作填写对应字段
+type ScanActionRequest struct {
+	Action string `json:"action" validate:"required,oneof=checkout checkin inventory"`
+
+	// 借出
+	BorrowerName       string     `json:"borrower_name" validate:"max=100"` // 借出时必填
+	BorrowerContact    string     `json:"borrower_contact" validate:"max=100"`
+	DepartmentID       *uint      `json:"department_id"`
+	BorrowDate         *time.Time `json:"borrow_date"` // 默认当前时间
+	ExpectedReturnDate *time.Time `json:"expected_return_date"`
+	Purpose            string     `json:"purpose"`
+
+	// 归还
+	ReturnDate *time.Time `json:"return_date"` // 默认当前时间
+
+	// 盘点
+	TaskID       uint                   `json:"task_id"`                                                                          // 盘点任务，只有一个待盘点的任务时可省略
+	ActualStatus models.AssetStatus     `json:"actual_status" validate:"omitempty,oneof=available borrowed maintenance scrapped"` // 默认为系统状态
+	Result       models.InventoryResult `json:"result" validate:"omitempty,oneof=normal surplus deficit damaged"`                 // 默认 normal
+
+	Notes string `json:"notes"`
+}
+
+// ScanActionResponse 扫码动作结果，附带执行后的识别结果以便继续操作
+type ScanActionResponse struct {
+	Action          string                  `json:"action"`
+	BorrowRecord    *models.BorrowRecord    `json:"borrow_record,omitempty"`
+	InventoryRecord *models.InventoryRecord `json:"inventory_record,omitempty"`
+	Scan            *ScanResponse           `json:"scan"`
+}
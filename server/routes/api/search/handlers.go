@@ -0,0 +1,87 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/search"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// Search 全文检索资产、借用记录和分类，结果按相关度排序并高亮命中关键词
+func Search(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	var kinds []string
+	for _, kind := range strings.Split(req.Types, ",") {
+		if kind = strings.TrimSpace(kind); kind == "" {
+			continue
+		}
+		if !slices.Contains(models.SearchKinds, kind) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的检索类型: %s", kind))
+			return
+		}
+		kinds = append(kinds, kind)
+	}
+
+	// 按数据范围过滤检索结果
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	result, err := search.Search(scope.DB(), search.Query{
+		Keyword: req.Keyword,
+		Kinds:   kinds,
+		Offset:  (req.Page - 1) * req.PageSize,
+		Limit:   req.PageSize,
+	})
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, SearchResponse{
+		Engine:      result.Engine,
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+		TotalItems:  result.Total,
+		Data:        result.Hits,
+	})
+}
+
+// Reindex 按现有数据重建全部检索文档
+func Reindex(c *gin.Context) {
+	if err := search.Rebuild(global.DB); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var count int64
+	if err := global.DB.Model(&models.SearchDocument{}).Count(&count).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, ReindexResponse{Engine: search.Engine(), Documents: count})
+}
@@ -0,0 +1,14 @@
+package search
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册全文检索路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	search := r.Group("/search")
+	{
+		search.GET("", Search)           // 全文检索资产、借用记录和分类
+		search.POST("/reindex", Reindex) // 重建检索索引（仅管理员）
+	}
+}
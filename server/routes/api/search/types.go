@@ -0,0 +1,28 @@
+package search
+
+import (
+	"asset-management-system/server/pkg/search"
+)
+
+// SearchRequest 全文检索请求
+type SearchRequest struct {
+	Keyword  string `form:"q" validate:"required,max=200"`                 // 关键词，多个关键词以空格分隔，须全部命中
+	Types    string `form:"types"`                                         // 检索类型，逗号分隔：asset、borrow、category，为空时检索全部
+	Page     int    `form:"page,default=1" validate:"min=1"`               // 页码
+	PageSize int    `form:"page_size,default=20" validate:"min=1,max=100"` // 每页条数
+}
+
+// SearchResponse 全文检索响应
+type SearchResponse struct {
+	Engine      string       `json:"engine"`       // 检索引擎：fts5 或 like
+	CurrentPage int          `json:"current_page"` // 当前页码
+	PageSize    int          `json:"page_size"`    // 每页条数
+	TotalItems  int64        `json:"total_items"`  // 命中总数
+	Data        []search.Hit `json:"data"`         // 按相关度排序的命中结果
+}
+
+// ReindexResponse 重建检索索引响应
+type ReindexResponse struct {
+	Engine    string `json:"engine"`    // 检索引擎
+	Documents int64  `json:"documents"` // 重建后的文档数量
+}
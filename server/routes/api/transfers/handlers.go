@@ -0,0 +1,349 @@
+package transfers
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetTransfers 获取调拨单列表
+func GetTransfers(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters TransferFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"created_at": true,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "asset_id", "from_department_id", "to_department_id", "status", "reviewed_at", "completed_at", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	// 构建查询（按数据范围过滤，调出或调入部门在范围内即可见）
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	query := scope.DB().Model(&models.AssetTransfer{}).
+		Preload("Asset").
+		Preload("FromDepartment").
+		Preload("ToDepartment")
+
+	// 应用筛选条件
+	query = applyTransferFilters(query, filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 获取调拨单列表
+	var transfers []models.AssetTransfer
+	if err := query.
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&transfers).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 返回分页响应
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, transfers)
+	utils.Success(c, response)
+}
+
+// GetTransfer 获取调拨单详情
+func GetTransfer(c *gin.Context) {
+	transfer, ok := findTransfer(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, transfer)
+}
+
+// CreateTransfer 发起调拨申请
+func CreateTransfer(c *gin.Context) {
+	var req CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 只能调拨数据范围内的资产
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	var asset models.Asset
+	if err := scope.DB().First(&asset, req.AssetID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.ASSET_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if asset.Status == models.AssetStatusScrapped {
+		utils.ErrorWithMessage(c, utils.ASSET_NOT_AVAILABLE, models.ErrTransferAssetScrapped.Error(), nil)
+		return
+	}
+	frozen, err := models.IsAssetFrozen(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if frozen {
+		utils.Error(c, utils.ASSET_FROZEN, nil)
+		return
+	}
+	if asset.DepartmentID != nil && *asset.DepartmentID == req.ToDepartmentID {
+		utils.ValidationError(c, "调入部门与资产当前部门相同")
+		return
+	}
+
+	// 验证调入部门是否存在
+	var department models.Department
+	if err := global.DB.First(&department, req.ToDepartmentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 同一资产同时只能有一张进行中的调拨单
+	inProgress, err := models.HasOpenTransfer(global.DB, asset.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+	if inProgress {
+		utils.Error(c, utils.TRANSFER_IN_PROGRESS, nil)
+		return
+	}
+
+	transfer := models.AssetTransfer{
+		AssetID:               asset.ID,
+		FromDepartmentID:      asset.DepartmentID,
+		ToDepartmentID:        req.ToDepartmentID,
+		FromLocation:          asset.Location,
+		ToLocation:            req.ToLocation,
+		FromResponsiblePerson: asset.ResponsiblePerson,
+		ToResponsiblePerson:   req.ToResponsiblePerson,
+		Reason:                req.Reason,
+		IncludeComponents:     req.IncludeComponents,
+		Status:                models.TransferStatusRequested,
+		RequestedBy:           auth.GetOperator(c),
+	}
+
+	if err := global.DB.Create(&transfer).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadTransfer(global.DB).First(&transfer, transfer.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, transfer)
+}
+
+// ApproveTransfer 审批通过调拨单
+func ApproveTransfer(c *gin.Context) {
+	reviewTransfer(c, models.TransferStatusApproved)
+}
+
+// RejectTransfer 驳回调拨单
+func RejectTransfer(c *gin.Context) {
+	reviewTransfer(c, models.TransferStatusRejected)
+}
+
+// CompleteTransfer 完成调拨，在同一事务中更新资产归属和调拨单状态
+func CompleteTransfer(c *gin.Context) {
+	transfer, ok := findTransfer(c)
+	if !ok {
+		return
+	}
+
+	if !transfer.CanTransitionTo(models.TransferStatusCompleted) {
+		utils.Error(c, utils.TRANSFER_INVALID_STATUS, gin.H{"status": transfer.Status})
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := transfer.Complete(tx, auth.GetOperator(c)); err != nil {
+		tx.Rollback()
+		if err == models.ErrTransferAssetScrapped || err == models.ErrTransferAssetFrozen {
+			utils.ErrorWithMessage(c, utils.TRANSFER_INVALID_STATUS, err.Error(), nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadTransfer(global.DB).First(transfer, transfer.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, transfer)
+}
+
+// reviewTransfer 审批调拨单（通过或驳回）
+func reviewTransfer(c *gin.Context, to models.TransferStatus) {
+	var req ReviewTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	transfer, ok := findTransfer(c)
+	if !ok {
+		return
+	}
+
+	if !transfer.CanTransitionTo(to) {
+		utils.Error(c, utils.TRANSFER_INVALID_STATUS, gin.H{"status": transfer.Status})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":         to,
+		"reviewed_by":    auth.GetOperator(c),
+		"reviewed_at":    time.Now(),
+		"review_comment": req.Comment,
+	}
+	if err := global.DB.Model(transfer).Updates(updates).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := preloadTransfer(global.DB).First(transfer, transfer.ID).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, transfer)
+}
+
+// findTransfer 按路径参数查找数据范围内的调拨单，失败时已写入响应
+func findTransfer(c *gin.Context) (*models.AssetTransfer, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的调拨单ID")
+		return nil, false
+	}
+
+	scope, err := auth.GetDataScope(c)
+	if err != nil {
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	var transfer models.AssetTransfer
+	if err := preloadTransfer(scope.DB()).First(&transfer, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.TRANSFER_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &transfer, true
+}
+
+// preloadTransfer 预加载调拨单关联数据
+func preloadTransfer(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Asset").
+		Preload("FromDepartment").
+		Preload("ToDepartment")
+}
+
+// applyTransferFilters 应用调拨单筛选条件
+func applyTransferFilters(query *gorm.DB, filters TransferFilters) *gorm.DB {
+	if filters.AssetID != nil {
+		query = query.Where("asset_id = ?", *filters.AssetID)
+	}
+	if filters.FromDepartmentID != nil {
+		query = query.Where("from_department_id = ?", *filters.FromDepartmentID)
+	}
+	if filters.ToDepartmentID != nil {
+		query = query.Where("to_department_id = ?", *filters.ToDepartmentID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.RequestedBy != nil && *filters.RequestedBy != "" {
+		query = query.Where("requested_by LIKE ?", "%"+*filters.RequestedBy+"%")
+	}
+
+	return query
+}
@@ -0,0 +1,18 @@
+package transfers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册资产调拨路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	transfers := r.Group("/transfers")
+	{
+		transfers.GET("", GetTransfers)                  // 获取调拨单列表
+		transfers.POST("", CreateTransfer)               // 发起调拨申请
+		transfers.GET("/:id", GetTransfer)               // 获取调拨单详情
+		transfers.PUT("/:id/approve", ApproveTransfer)   // 审批通过
+		transfers.PUT("/:id/reject", RejectTransfer)     // 驳回
+		transfers.PUT("/:id/complete", CompleteTransfer) // 完成调拨
+	}
+}
@@ -0,0 +1,29 @@
+package transfers
+
+import (
+	"asset-management-system/server/models"
+)
+
+// CreateTransferRequest 发起调拨申请请求
+type CreateTransferRequest struct {
+	AssetID             uint   `json:"asset_id" validate:"required"`
+	ToDepartmentID      uint   `json:"to_department_id" validate:"required"`
+	ToLocation          string `json:"to_location" validate:"max=200"`
+	ToResponsiblePerson string `json:"to_responsible_person" validate:"max=100"`
+	Reason              string `json:"reason" validate:"required"`
+	IncludeComponents   bool   `json:"include_components"` // 完成时组件随父资产一同调拨
+}
+
+// ReviewTransferRequest 审批调拨单请求
+type ReviewTransferRequest struct {
+	Comment string `json:"comment"`
+}
+
+// TransferFilters 调拨单筛选条件
+type TransferFilters struct {
+	AssetID          *uint                  `json:"asset_id" form:"asset_id"`
+	FromDepartmentID *uint                  `json:"from_department_id" form:"from_department_id"`
+	ToDepartmentID   *uint                  `json:"to_department_id" form:"to_department_id"`
+	Status           *models.TransferStatus `json:"status" form:"status"`
+	RequestedBy      *string                `json:"requested_by" form:"requested_by"`
+}
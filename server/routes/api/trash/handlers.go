@@ -0,0 +1,189 @@
+package trash
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"asset-management-system/server/global"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/trash"
+	"asset-management-system/server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetTrashItems 获取回收站记录列表，只返回有权删除（即有权恢复）的记录类型
+func GetTrashItems(c *gin.Context) {
+	var req ListTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 验证请求数据
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	identity := auth.GetIdentity(c)
+	var types []string
+	for _, recordType := range trash.Types {
+		if req.Type != "" && recordType != req.Type {
+			continue
+		}
+		if auth.HasPermission(identity.Roles, trash.Resource(recordType), auth.ActionDelete) {
+			types = append(types, recordType)
+		}
+	}
+	if req.Type != "" && len(types) == 0 {
+		utils.Error(c, utils.FORBIDDEN, nil)
+		return
+	}
+
+	items, total, err := trash.List(global.DB, trash.ListOptions{
+		Types:         types,
+		Keyword:       req.Keyword,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		RetentionDays: global.AppConfig.TrashRetentionDays,
+	})
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, utils.NewPaginationResponse(req.Page, req.PageSize, total, items))
+}
+
+// RestoreTrashItem 恢复回收站中的记录，须有原记录的删除权限
+func RestoreTrashItem(c *gin.Context) {
+	recordType, id, ok := parseTrashItem(c)
+	if !ok {
+		return
+	}
+	if !auth.HasPermission(auth.GetIdentity(c).Roles, trash.Resource(recordType), auth.ActionDelete) {
+		utils.Error(c, utils.FORBIDDEN, nil)
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := trash.Restore(tx, recordType, id); err != nil {
+		tx.Rollback()
+		respondTrashError(c, err, utils.TRASH_RESTORE_CONFLICT)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "记录已恢复", "type": recordType, "id": id})
+}
+
+// PurgeTrashItem 彻底删除回收站中的记录，关联文件由上传目录清理任务回收
+func PurgeTrashItem(c *gin.Context) {
+	recordType, id, ok := parseTrashItem(c)
+	if !ok {
+		return
+	}
+
+	// 开始事务
+	tx := global.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := trash.Purge(tx, recordType, id); err != nil {
+		tx.Rollback()
+		respondTrashError(c, err, utils.TRASH_PURGE_CONFLICT)
+		return
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "记录已彻底删除", "type": recordType, "id": id})
+}
+
+// PurgeExpired 立即清除超过保留天数的记录，预览时在事务中执行后回滚
+func PurgeExpired(c *gin.Context) {
+	var req PurgeExpiredRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	retentionDays := global.AppConfig.TrashRetentionDays
+	if retentionDays <= 0 {
+		utils.ValidationError(c, "回收站设置为永久保留，没有到期记录")
+		return
+	}
+
+	db := global.DB
+	if req.DryRun {
+		db = global.DB.Begin()
+		defer db.Rollback()
+	}
+
+	result, err := trash.PurgeExpired(db, time.Now().AddDate(0, 0, -retentionDays))
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, PurgeExpiredResponse{
+		DryRun:        req.DryRun,
+		RetentionDays: retentionDays,
+		PurgeResult:   result,
+	})
+}
+
+// parseTrashItem 解析路径中的记录类型和ID，失败时已写入响应
+func parseTrashItem(c *gin.Context) (string, uint, bool) {
+	recordType := c.Param("type")
+	if !trash.IsValidType(recordType) {
+		utils.ValidationError(c, "无效的记录类型")
+		return "", 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的记录ID")
+		return "", 0, false
+	}
+
+	return recordType, uint(id), true
+}
+
+// respondTrashError 按错误类型写入恢复或彻底删除失败的响应
+func respondTrashError(c *gin.Context, err error, conflictCode string) {
+	var conflictErr *trash.ConflictError
+	switch {
+	case errors.Is(err, trash.ErrNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		utils.Error(c, utils.TRASH_NOT_FOUND, nil)
+	case errors.As(err, &conflictErr):
+		utils.ErrorWithMessage(c, conflictCode, conflictErr.Reason, nil)
+	default:
+		utils.InternalError(c, err)
+	}
+}
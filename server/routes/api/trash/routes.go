@@ -0,0 +1,19 @@
+package trash
+
+import (
+	"asset-management-system/server/middleware"
+	"asset-management-system/server/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册回收站路由
+func RegisterRoutes(r *gin.RouterGroup) {
+	trash := r.Group("/trash")
+	{
+		trash.GET("", GetTrashItems)                                                              // 获取回收站记录列表
+		trash.POST("/purge-expired", middleware.UserRoleMiddleware(auth.RoleAdmin), PurgeExpired) // 清除超过保留天数的记录（仅管理员）
+		trash.PUT("/:type/:id/restore", RestoreTrashItem)                                         // 恢复记录
+		trash.DELETE("/:type/:id", middleware.UserRoleMiddleware(auth.RoleAdmin), PurgeTrashItem) // 彻底删除记录（仅管理员）
+	}
+}
@@ -0,0 +1,25 @@
+package trash
+
+import (
+	"asset-management-system/server/pkg/trash"
+)
+
+// ListTrashRequest 回收站列表请求
+type ListTrashRequest struct {
+	Type     string `form:"type" validate:"omitempty,oneof=asset category department borrow inventory_task"` // 记录类型，为空时返回全部有权恢复的类型
+	Keyword  string `form:"keyword" validate:"max=100"`                                                      // 按名称、编号或编码模糊匹配
+	Page     int    `form:"page,default=1" validate:"min=1"`                                                 // 页码
+	PageSize int    `form:"page_size,default=20" validate:"min=1,max=200"`                                   // 每页条数
+}
+
+// PurgeExpiredRequest 清除到期记录请求
+type PurgeExpiredRequest struct {
+	DryRun bool `form:"dry_run"` // 仅预览将被清除的记录
+}
+
+// PurgeExpiredResponse 清除到期记录响应
+type PurgeExpiredResponse struct {
+	DryRun        bool `json:"dry_run"`
+	RetentionDays int  `json:"retention_days"`
+	*trash.PurgeResult
+}
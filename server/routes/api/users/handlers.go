@@ -0,0 +1,408 @@
+package users
+
+import (
+	"asset-management-system/server/global"
+	"asset-management-system/server/models"
+	"asset-management-system/server/pkg/auth"
+	"asset-management-system/server/pkg/utils"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+var validate = validator.New()
+
+// GetUsers 获取用户列表
+func GetUsers(c *gin.Context) {
+	var req utils.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 200 {
+		req.PageSize = 200
+	}
+
+	// 解析筛选条件
+	var filters UserFilters
+	if err := req.ParseFiltersFromQuery(c, &filters); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 设置默认排序
+	req.SetDefaultSorts(map[string]bool{
+		"created_at": true,
+		"id":         true,
+	})
+
+	// 验证排序字段
+	allowedSortFields := []string{"id", "username", "name", "last_login_at", "created_at", "updated_at"}
+	for _, sort := range req.Sorts {
+		if !utils.ValidateSortField(sort.Key, allowedSortFields) {
+			utils.ValidationError(c, fmt.Sprintf("不支持的排序字段: %s", sort.Key))
+			return
+		}
+	}
+
+	query := global.DB.Model(&models.User{})
+	query = applyUserFilters(query, filters)
+
+	// 获取总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	var users []models.User
+	if err := query.
+		Preload("Department").
+		Order(req.GetOrderBy()).
+		Offset(req.GetOffset()).
+		Limit(req.PageSize).
+		Find(&users).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	response := utils.NewPaginationResponse(req.Page, req.PageSize, total, users)
+	utils.Success(c, response)
+}
+
+// GetUser 获取用户详情
+func GetUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的用户ID")
+		return
+	}
+
+	var user models.User
+	if err := global.DB.Preload("Department").First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.USER_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, user)
+}
+
+// CreateUser 创建用户
+func CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validateRoles(req.Roles); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	// 检查用户名是否已存在
+	var existingUser models.User
+	if err := global.DB.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
+		utils.Error(c, utils.USER_USERNAME_EXISTS, nil)
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 验证部门是否存在
+	if req.DepartmentID != nil {
+		var department models.Department
+		if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+				return
+			}
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	user := models.User{
+		Username:     req.Username,
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Roles:        strings.Join(req.Roles, ","),
+		DepartmentID: req.DepartmentID,
+		IsActive:     true,
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+	if err := user.SetPassword(req.Password); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Create(&user).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 布尔零值不会被Create写入，禁用状态需要单独更新
+	if !user.IsActive {
+		if err := global.DB.Model(&user).Update("is_active", false).Error; err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	utils.Success(c, user)
+}
+
+// UpdateUser 更新用户
+func UpdateUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的用户ID")
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		utils.ValidationError(c, err.Error())
+		return
+	}
+
+	var user models.User
+	if err := global.DB.First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.USER_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	// 更新字段
+	updates := make(map[string]interface{})
+	// 角色和部门写入了令牌并缓存在DooTask身份中，变更后须重新登录才能生效
+	permissionsChanged := false
+	if req.Password != nil {
+		if err := user.SetPassword(*req.Password); err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+		updates["password_hash"] = user.PasswordHash
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Roles != nil {
+		if err := validateRoles(*req.Roles); err != nil {
+			utils.ValidationError(c, err.Error())
+			return
+		}
+		updates["roles"] = strings.Join(*req.Roles, ",")
+		permissionsChanged = permissionsChanged || updates["roles"] != user.Roles
+	}
+	if req.DepartmentID != nil {
+		if *req.DepartmentID == 0 {
+			updates["department_id"] = nil
+			permissionsChanged = permissionsChanged || user.DepartmentID != nil
+		} else {
+			var department models.Department
+			if err := global.DB.First(&department, *req.DepartmentID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					utils.Error(c, utils.DEPARTMENT_NOT_FOUND, nil)
+					return
+				}
+				utils.InternalError(c, err)
+				return
+			}
+			updates["department_id"] = *req.DepartmentID
+			permissionsChanged = permissionsChanged || user.DepartmentID == nil || *user.DepartmentID != *req.DepartmentID
+		}
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) > 0 {
+		if err := global.DB.Model(&user).Updates(updates).Error; err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	// 禁用用户或变更角色、部门时立即注销其全部会话
+	if (req.IsActive != nil && !*req.IsActive) || permissionsChanged {
+		if _, err := auth.RevokeUserSessions(user.ID, ""); err != nil {
+			utils.InternalError(c, err)
+			return
+		}
+	}
+
+	// 重新加载用户
+	if err := global.DB.Preload("Department").First(&user, id).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, user)
+}
+
+// DeleteUser 删除用户
+func DeleteUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的用户ID")
+		return
+	}
+
+	if identity := auth.GetIdentity(c); identity != nil && identity.UserID == uint(id) {
+		utils.ErrorWithMessage(c, utils.BAD_REQUEST, "不能删除当前登录用户", nil)
+		return
+	}
+
+	var user models.User
+	if err := global.DB.First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.USER_NOT_FOUND, nil)
+			return
+		}
+		utils.InternalError(c, err)
+		return
+	}
+
+	if err := global.DB.Delete(&user).Error; err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	if _, err := auth.RevokeUserSessions(user.ID, ""); err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "用户删除成功"})
+}
+
+// GetUserSessions 获取用户的有效会话
+func GetUserSessions(c *gin.Context) {
+	user, ok := getUserByParam(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := auth.GetUserSessions(user.ID)
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, sessions)
+}
+
+// RevokeUserSessions 强制下线用户，注销其全部会话
+func RevokeUserSessions(c *gin.Context) {
+	user, ok := getUserByParam(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := auth.RevokeUserSessions(user.ID, "")
+	if err != nil {
+		utils.InternalError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"revoked": revoked})
+}
+
+// GetRoles 获取角色及权限矩阵
+func GetRoles(c *gin.Context) {
+	utils.Success(c, RolesResponse{
+		Roles:       auth.ValidRoles,
+		Permissions: auth.PermissionMatrix,
+	})
+}
+
+// getUserByParam 根据路径参数获取用户，失败时已写入响应
+func getUserByParam(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationError(c, "无效的用户ID")
+		return nil, false
+	}
+
+	var user models.User
+	if err := global.DB.First(&user, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.Error(c, utils.USER_NOT_FOUND, nil)
+			return nil, false
+		}
+		utils.InternalError(c, err)
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// validateRoles 校验角色列表
+func validateRoles(roles []string) error {
+	for _, role := range roles {
+		if !auth.IsValidRole(role) {
+			return fmt.Errorf("无效的角色: %s", role)
+		}
+	}
+	return nil
+}
+
+// applyUserFilters 应用用户筛选条件
+func applyUserFilters(query *gorm.DB, filters UserFilters) *gorm.DB {
+	if filters.Keyword != nil && *filters.Keyword != "" {
+		keyword := "%" + *filters.Keyword + "%"
+		query = query.Where("username LIKE ? OR name LIKE ? OR email LIKE ?", keyword, keyword, keyword)
+	}
+	if filters.Role != nil && *filters.Role != "" {
+		// 角色以逗号分隔存储，前后补逗号后精确匹配
+		query = query.Where("',' || roles || ',' LIKE ?", "%,"+*filters.Role+",%")
+	}
+	if filters.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filters.DepartmentID)
+	}
+	if filters.IsActive != nil {
+		query = query.Where("is_active = ?", *filters.IsActive)
+	}
+
+	return query
+}
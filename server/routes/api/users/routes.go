@@ -0,0 +1,21 @@
+package users
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes 注册用户管理路由（仅管理员）
+func RegisterRoutes(r *gin.RouterGroup) {
+	users := r.Group("/users")
+	{
+		users.GET("", GetUsers)          // 获取用户列表
+		users.POST("", CreateUser)       // 创建用户
+		users.GET("/roles", GetRoles)    // 获取角色及权限矩阵
+		users.GET("/:id", GetUser)       // 获取用户详情
+		users.PUT("/:id", UpdateUser)    // 更新用户
+		users.DELETE("/:id", DeleteUser) // 删除用户
+
+		users.GET("/:id/sessions", GetUserSessions)       // 获取用户会话
+		users.DELETE("/:id/sessions", RevokeUserSessions) // 强制下线用户
+	}
+}
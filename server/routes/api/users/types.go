@@ -0,0 +1,38 @@
+package users
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Username     string   `json:"username" validate:"required,max=100"`
+	Password     string   `json:"password" validate:"required,min=6,max=100"`
+	Name         string   `json:"name" validate:"max=100"`
+	Email        string   `json:"email" validate:"omitempty,email,max=200"`
+	Phone        string   `json:"phone" validate:"max=50"`
+	Roles        []string `json:"roles" validate:"required,min=1"`
+	DepartmentID *uint    `json:"department_id"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// UpdateUserRequest 更新用户请求
+type UpdateUserRequest struct {
+	Password     *string   `json:"password" validate:"omitempty,min=6,max=100"`
+	Name         *string   `json:"name" validate:"omitempty,max=100"`
+	Email        *string   `json:"email" validate:"omitempty,email,max=200"`
+	Phone        *string   `json:"phone" validate:"omitempty,max=50"`
+	Roles        *[]string `json:"roles" validate:"omitempty,min=1"`
+	DepartmentID *uint     `json:"department_id"`
+	IsActive     *bool     `json:"is_active"`
+}
+
+// UserFilters 用户筛选条件
+type UserFilters struct {
+	Keyword      *string `json:"keyword" form:"keyword"`
+	Role         *string `json:"role" form:"role"`
+	DepartmentID *uint   `json:"department_id" form:"department_id"`
+	IsActive     *bool   `json:"is_active" form:"is_active"`
+}
+
+// RolesResponse 角色及权限矩阵响应
+type RolesResponse struct {
+	Roles       []string                       `json:"roles"`
+	Permissions map[string]map[string][]string `json:"permissions"`
+}
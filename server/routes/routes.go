@@ -2,16 +2,31 @@ package routes
 
 import (
 	"asset-management-system/server/middleware"
+	"asset-management-system/server/routes/api/apikeys"
+	"asset-management-system/server/routes/api/assetnorules"
 	"asset-management-system/server/routes/api/assets"
+	"asset-management-system/server/routes/api/attachments"
+	"asset-management-system/server/routes/api/auth"
 	"asset-management-system/server/routes/api/borrow"
 	"asset-management-system/server/routes/api/categories"
+	"asset-management-system/server/routes/api/consumables"
 	"asset-management-system/server/routes/api/dashboard"
 	"asset-management-system/server/routes/api/departments"
+	"asset-management-system/server/routes/api/disposals"
 	"asset-management-system/server/routes/api/inventory"
+	"asset-management-system/server/routes/api/jobs"
+	"asset-management-system/server/routes/api/licenses"
 	"asset-management-system/server/routes/api/logs"
+	"asset-management-system/server/routes/api/maintenance"
 	"asset-management-system/server/routes/api/reports"
+	"asset-management-system/server/routes/api/reservations"
+	"asset-management-system/server/routes/api/scan"
+	"asset-management-system/server/routes/api/search"
 	"asset-management-system/server/routes/api/test"
+	"asset-management-system/server/routes/api/transfers"
+	"asset-management-system/server/routes/api/trash"
 	"asset-management-system/server/routes/api/upload"
+	"asset-management-system/server/routes/api/users"
 	"asset-management-system/server/routes/health"
 
 	"github.com/gin-gonic/gin"
@@ -24,12 +39,15 @@ func RegisterRoutes(r *gin.Engine) {
 
 	// 注册API路由（需要认证）
 	api := r.Group("/api")
-	api.Use(middleware.UserRoleMiddleware())
+	api.Use(middleware.UserRoleMiddleware()) // 按权限矩阵校验角色
 	api.Use(middleware.AuditLogMiddleware(nil)) // 添加审计日志中间件
 	{
 		// 注册测试路由
 		test.RegisterRoutes(api)
 
+		// 认证路由
+		auth.RegisterRoutes(api)
+
 		// 导入仪表板路由
 		dashboard.RegisterRoutes(api)
 
@@ -39,6 +57,12 @@ func RegisterRoutes(r *gin.Engine) {
 		// 资产管理路由
 		assets.RegisterRoutes(api)
 
+		// 附件管理路由
+		attachments.RegisterRoutes(api)
+
+		// 资产编号规则路由
+		assetnorules.RegisterRoutes(api)
+
 		// 分类管理路由
 		categories.RegisterRoutes(api)
 
@@ -48,13 +72,49 @@ func RegisterRoutes(r *gin.Engine) {
 		// 借用管理路由
 		borrow.RegisterRoutes(api)
 
+		// 资产预约路由
+		reservations.RegisterRoutes(api)
+
+		// 资产调拨路由
+		transfers.RegisterRoutes(api)
+
+		// 维修工单路由
+		maintenance.RegisterRoutes(api)
+
+		// 资产处置路由
+		disposals.RegisterRoutes(api)
+
 		// 盘点管理路由
 		inventory.RegisterRoutes(api)
 
+		// 耗材管理路由
+		consumables.RegisterRoutes(api)
+
+		// 软件许可证路由
+		licenses.RegisterRoutes(api)
+
 		// 报表统计路由
 		reports.RegisterRoutes(api)
 
+		// 全文检索路由
+		search.RegisterRoutes(api)
+
+		// 扫码借还、盘点路由
+		scan.RegisterRoutes(api)
+
+		// 后台批量任务路由
+		jobs.RegisterRoutes(api)
+
+		// 回收站路由
+		trash.RegisterRoutes(api)
+
 		// 操作日志路由
 		logs.RegisterRoutes(api)
+
+		// 用户管理路由（仅管理员）
+		users.RegisterRoutes(api)
+
+		// API密钥管理路由（仅管理员）
+		apikeys.RegisterRoutes(api)
 	}
 }